@@ -0,0 +1,174 @@
+package bn256
+
+// gfpBatchInvert replaces each element of a with its inverse using a single
+// field inversion (Montgomery's trick). Zero elements are left as zero.
+func gfpBatchInvert(a []gfP) {
+	if len(a) == 0 {
+		return
+	}
+
+	// prefix[i] is the product of the non-zero elements before a[i].
+	prefix := make([]gfP, len(a))
+	acc := *newGFp(1)
+	for i := range a {
+		prefix[i] = acc
+		if a[i] != (gfP{}) {
+			gfpMul(&acc, &acc, &a[i])
+		}
+	}
+
+	inv := &gfP{}
+	inv.Invert(&acc)
+
+	t := &gfP{}
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i] == (gfP{}) {
+			continue
+		}
+		gfpMul(t, inv, &prefix[i])
+		gfpMul(inv, inv, &a[i])
+		a[i] = *t
+	}
+}
+
+// gfp2BatchInvert replaces each element of a with its inverse using a single
+// field inversion. Zero elements are left as zero.
+func gfp2BatchInvert(a []gfP2) {
+	if len(a) == 0 {
+		return
+	}
+
+	prefix := make([]gfP2, len(a))
+	acc := &gfP2{}
+	acc.SetOne()
+	for i := range a {
+		prefix[i] = *acc
+		if !a[i].IsZero() {
+			acc.Mul(acc, &a[i])
+		}
+	}
+
+	inv := (&gfP2{}).Invert(acc)
+
+	t := &gfP2{}
+	for i := len(a) - 1; i >= 0; i-- {
+		if a[i].IsZero() {
+			continue
+		}
+		t.Mul(inv, &prefix[i])
+		inv.Mul(inv, &a[i])
+		a[i] = *t
+	}
+}
+
+// BatchInvertFp replaces each element of a with its inverse, sharing a single
+// field inversion between them. Zero elements are left as zero.
+func BatchInvertFp(a []Fp) {
+	t := make([]gfP, len(a))
+	for i := range a {
+		t[i] = a[i].p
+	}
+	gfpBatchInvert(t)
+	for i := range a {
+		a[i].p = t[i]
+	}
+}
+
+// BatchInvertFp2 replaces each element of a with its inverse, sharing a single
+// field inversion between them. Zero elements are left as zero.
+func BatchInvertFp2(a []Fp2) {
+	t := make([]gfP2, len(a))
+	for i := range a {
+		t[i] = a[i].p
+	}
+	gfp2BatchInvert(t)
+	for i := range a {
+		a[i].p = t[i]
+	}
+}
+
+// BatchNormalizeG1 converts every point to affine coordinates, sharing a single
+// field inversion between them. Afterwards, Marshal on any of the points does
+// not need to invert.
+func BatchNormalizeG1(points []*G1) {
+	zInv := make([]gfP, len(points))
+	for i, p := range points {
+		if p.p == nil {
+			p.p = &curvePoint{}
+		}
+		zInv[i] = p.p.z
+	}
+	gfpBatchInvert(zInv)
+
+	for i, p := range points {
+		p.p.makeAffineWith(&zInv[i])
+	}
+}
+
+// BatchNormalizeG2 converts every point to affine coordinates, sharing a single
+// field inversion between them. Afterwards, Marshal on any of the points does
+// not need to invert.
+func BatchNormalizeG2(points []*G2) {
+	zInv := make([]gfP2, len(points))
+	for i, p := range points {
+		if p.p == nil {
+			p.p = &twistPoint{}
+		}
+		zInv[i] = p.p.z
+	}
+	gfp2BatchInvert(zInv)
+
+	for i, p := range points {
+		p.p.makeAffineWith(&zInv[i])
+	}
+}
+
+// BatchMarshalG1 returns the concatenation of the encodings of points, as
+// produced by Marshal, sharing a single field inversion between them. The
+// points themselves are not modified.
+func BatchMarshalG1(points []*G1) []byte {
+	// Each value is a 256-bit number.
+	const numBytes = 256 / 8
+
+	affine := make([]curvePoint, len(points))
+	zInv := make([]gfP, len(points))
+	for i, p := range points {
+		if p.p != nil {
+			affine[i].Set(p.p)
+		}
+		zInv[i] = affine[i].z
+	}
+	gfpBatchInvert(zInv)
+
+	ret := make([]byte, len(points)*2*numBytes)
+	for i := range affine {
+		affine[i].makeAffineWith(&zInv[i])
+		marshalG1(ret[i*2*numBytes:], &affine[i])
+	}
+	return ret
+}
+
+// BatchMarshalG2 returns the concatenation of the encodings of points, as
+// produced by Marshal, sharing a single field inversion between them. The
+// points themselves are not modified.
+func BatchMarshalG2(points []*G2) []byte {
+	// Each value is a 256-bit number.
+	const numBytes = 256 / 8
+
+	affine := make([]twistPoint, len(points))
+	zInv := make([]gfP2, len(points))
+	for i, p := range points {
+		if p.p != nil {
+			affine[i].Set(p.p)
+		}
+		zInv[i] = affine[i].z
+	}
+	gfp2BatchInvert(zInv)
+
+	ret := make([]byte, len(points)*4*numBytes)
+	for i := range affine {
+		affine[i].makeAffineWith(&zInv[i])
+		marshalG2(ret[i*4*numBytes:], &affine[i])
+	}
+	return ret
+}
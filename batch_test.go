@@ -0,0 +1,174 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestBatchInvert(t *testing.T) {
+	a := make([]gfP, 20)
+	b := make([]gfP2, len(a))
+	for i := range a {
+		if i%7 == 3 {
+			continue // leave some zeros
+		}
+		x, _ := rand.Int(rand.Reader, P)
+		y, _ := rand.Int(rand.Reader, P)
+		a[i] = gfPFromBig(x)
+		b[i] = gfP2{gfPFromBig(x), gfPFromBig(y)}
+	}
+	wantA := make([]gfP, len(a))
+	wantB := make([]gfP2, len(b))
+	for i := range a {
+		wantA[i].Invert(&a[i])
+		wantB[i].Invert(&b[i])
+	}
+
+	gfpBatchInvert(a)
+	gfp2BatchInvert(b)
+	for i := range a {
+		if a[i] != wantA[i] {
+			t.Errorf("gfP element %d: have %v, want %v", i, &a[i], &wantA[i])
+		}
+		if b[i] != wantB[i] {
+			t.Errorf("gfP2 element %d: have %v, want %v", i, &b[i], &wantB[i])
+		}
+	}
+
+	gfpBatchInvert(nil)
+	gfp2BatchInvert(nil)
+}
+
+func TestBatchInvertFp(t *testing.T) {
+	a := make([]Fp, 20)
+	b := make([]Fp2, len(a))
+	for i := range a {
+		if i%7 == 3 {
+			continue // leave some zeros
+		}
+		x, _ := rand.Int(rand.Reader, P)
+		y, _ := rand.Int(rand.Reader, P)
+		a[i].SetBig(x)
+		b[i].SetBig(x, y)
+	}
+	wantA := make([]Fp, len(a))
+	wantB := make([]Fp2, len(b))
+	for i := range a {
+		wantA[i].Invert(&a[i])
+		wantB[i].Invert(&b[i])
+	}
+
+	BatchInvertFp(a)
+	BatchInvertFp2(b)
+	for i := range a {
+		if !a[i].Equal(&wantA[i]) {
+			t.Errorf("Fp element %d: have %v, want %v", i, &a[i], &wantA[i])
+		}
+		if !b[i].Equal(&wantB[i]) {
+			t.Errorf("Fp2 element %d: have %v, want %v", i, &b[i], &wantB[i])
+		}
+	}
+
+	BatchInvertFp(nil)
+	BatchInvertFp2(nil)
+}
+
+// batchPoints returns n points in projective coordinates, including the
+// identity and the generator.
+func batchPoints(n int) ([]*G1, []*G2) {
+	g1s, g2s := make([]*G1, n), make([]*G2, n)
+	for i := range g1s {
+		k, _ := rand.Int(rand.Reader, Order)
+		switch i {
+		case 0:
+			k = new(big.Int)
+		case 1:
+			k = big.NewInt(1)
+		}
+		g1s[i] = new(G1).ScalarBaseMult(k)
+		g2s[i] = new(G2).ScalarBaseMult(k)
+	}
+	return g1s, g2s
+}
+
+func TestBatchMarshal(t *testing.T) {
+	g1s, g2s := batchPoints(10)
+
+	// Batch marshalling leaves the points alone.
+	before := *g1s[5].p
+	m1, m2 := BatchMarshalG1(g1s), BatchMarshalG2(g2s)
+	if *g1s[5].p != before {
+		t.Fatal("BatchMarshalG1 modified its input")
+	}
+
+	for i := range g1s {
+		if !bytes.Equal(m1[64*i:64*(i+1)], g1s[i].Marshal()) {
+			t.Errorf("G1 point %d encodes differently", i)
+		}
+		if !bytes.Equal(m2[128*i:128*(i+1)], g2s[i].Marshal()) {
+			t.Errorf("G2 point %d encodes differently", i)
+		}
+	}
+
+	if len(BatchMarshalG1(nil)) != 0 || len(BatchMarshalG2(nil)) != 0 {
+		t.Error("empty batch has a non-empty encoding")
+	}
+}
+
+func TestBatchNormalize(t *testing.T) {
+	g1s, g2s := batchPoints(10)
+	want1, want2 := BatchMarshalG1(g1s), BatchMarshalG2(g2s)
+
+	BatchNormalizeG1(g1s)
+	BatchNormalizeG2(g2s)
+	for i := range g1s {
+		if !g1s[i].p.IsInfinity() && g1s[i].p.z != *newGFp(1) {
+			t.Errorf("G1 point %d is not affine", i)
+		}
+		if !g2s[i].p.IsInfinity() && !g2s[i].p.z.IsOne() {
+			t.Errorf("G2 point %d is not affine", i)
+		}
+	}
+	if !bytes.Equal(BatchMarshalG1(g1s), want1) || !bytes.Equal(BatchMarshalG2(g2s), want2) {
+		t.Error("normalization changed the points")
+	}
+}
+
+func TestBatchZeroValue(t *testing.T) {
+	g1s := []*G1{new(G1), new(G1).ScalarBaseMult(big.NewInt(2))}
+	g2s := []*G2{new(G2), new(G2).ScalarBaseMult(big.NewInt(2))}
+	want1, want2 := BatchMarshalG1(g1s), BatchMarshalG2(g2s)
+
+	BatchNormalizeG1(g1s)
+	BatchNormalizeG2(g2s)
+	if !g1s[0].IsIdentity() || !g2s[0].IsIdentity() {
+		t.Error("zero value did not normalize to the identity")
+	}
+	if !bytes.Equal(BatchMarshalG1(g1s), want1) || !bytes.Equal(BatchMarshalG2(g2s), want2) {
+		t.Error("normalization changed the points")
+	}
+}
+
+func BenchmarkMarshalG1(b *testing.B) {
+	g1s, _ := batchPoints(256)
+	projective := make([]curvePoint, len(g1s))
+	for i, p := range g1s {
+		projective[i] = *p.p
+	}
+
+	b.Run("Batch", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			BatchMarshalG1(g1s)
+		}
+	})
+	b.Run("Single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j, p := range g1s {
+				*p.p = projective[j]
+				p.Marshal()
+			}
+		}
+	})
+}
@@ -0,0 +1,237 @@
+// Package bbs implements BBS signatures, which sign a vector of messages and
+// allow the holder of a signature to prove knowledge of it while revealing
+// only some of the messages.
+//
+// Signatures are in G₁ and public keys in G₂. A signature on the messages
+// m₁, …, m_L is (A, e) with
+//
+//	A = (1/(x+e))·(P₁ + domain·Q₁ + m₁·H₁ + … + m_L·H_L)
+//
+// where x is the secret key, P₁ is the generator of G₁, and Q₁ and the Hᵢ
+// are derived with bn256.HashToG1, so that nobody knows a discrete logarithm
+// relation between them. The construction follows the IRTF draft "The BBS
+// Signature Scheme" (draft-irtf-cfrg-bbs-signatures), instantiated over
+// bn256, with scalars derived from SHA-256 transcripts of the transcript
+// package under its own domain separation tags; it does not interoperate with
+// the BLS12-381 ciphersuites of the draft.
+package bbs
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/transcript"
+)
+
+// Domain separation tags. Scalars are derived from transcripts labelled with
+// the last four.
+var generatorDomain = []byte("bn256/bbs/generators")
+
+const (
+	messageDomain   = "bn256/bbs/message"
+	domainDomain    = "bn256/bbs/domain"
+	signatureDomain = "bn256/bbs/signature"
+	challengeDomain = "bn256/bbs/challenge"
+)
+
+var (
+	errInvalid   = errors.New("bbs: invalid signature")
+	errMalformed = errors.New("bbs: malformed input")
+	errIndex     = errors.New("bbs: invalid or repeated message index")
+)
+
+// SecretKey is a BBS secret key.
+type SecretKey struct {
+	X *big.Int
+}
+
+// PublicKey is a BBS public key.
+type PublicKey struct {
+	W *bn256.G2
+}
+
+// KeyGen returns a new key pair using randomness from r.
+func KeyGen(r io.Reader) (*SecretKey, *PublicKey, error) {
+	for {
+		x, W, err := bn256.RandomG2(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		if x.Sign() != 0 {
+			return &SecretKey{x}, &PublicKey{W}, nil
+		}
+	}
+}
+
+// Signature is a BBS signature.
+type Signature struct {
+	A *bn256.G1
+	E *big.Int
+}
+
+// Sign returns the signature of messages under the key pair (sk, pk). header
+// is bound to the signature and must be presented with it, but is not a
+// message and cannot be hidden in proofs.
+func Sign(sk *SecretKey, pk *PublicKey, header []byte, messages [][]byte) (*Signature, error) {
+	gens := generators(len(messages))
+	domain := calculateDomain(pk, gens, header)
+	scalars := messagesToScalars(messages)
+
+	// e is derived from the secret key and the signed values, so signing
+	// needs no randomness.
+	t := transcript.New(signatureDomain)
+	t.AppendScalar("sk", sk.X)
+	for _, m := range scalars {
+		t.AppendScalar("m", m)
+	}
+	t.AppendScalar("domain", domain)
+	e := t.ChallengeScalar("e")
+
+	B := computeB(gens, domain, scalars)
+	xe := new(big.Int).Add(sk.X, e)
+	xe.Mod(xe, bn256.Order)
+	if xe.Sign() == 0 {
+		return nil, errors.New("bbs: signing failed")
+	}
+	A := new(bn256.G1).ScalarMult(B, xe.ModInverse(xe, bn256.Order))
+	return &Signature{A, e}, nil
+}
+
+// Verify returns nil iff sig is a signature of messages and header under pk.
+func Verify(pk *PublicKey, sig *Signature, header []byte, messages [][]byte) error {
+	if sig.A == nil || sig.E == nil || isIdentity(sig.A.Marshal()) || !canonical(sig.E) {
+		return errMalformed
+	}
+	gens := generators(len(messages))
+	domain := calculateDomain(pk, gens, header)
+	B := computeB(gens, domain, messagesToScalars(messages))
+
+	// e(A, W + e·g₂) = e(B, g₂)
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	We := new(bn256.G2).ScalarBaseMult(sig.E)
+	We.Add(We, pk.W)
+	if !bn256.PairingCheck([]*bn256.G1{sig.A, new(bn256.G1).Neg(B)}, []*bn256.G2{We, g2}) {
+		return errInvalid
+	}
+	return nil
+}
+
+// Marshal converts sig into a byte slice.
+func (sig *Signature) Marshal() []byte {
+	return appendScalar(sig.A.Marshal(), sig.E)
+}
+
+// Unmarshal sets sig to the result of converting the output of Marshal back
+// into a signature and then returns the remainder of m.
+func (sig *Signature) Unmarshal(m []byte) ([]byte, error) {
+	A := new(bn256.G1)
+	m, err := A.Unmarshal(m)
+	if err != nil {
+		return nil, err
+	}
+	e, m, err := readScalar(m)
+	if err != nil {
+		return nil, err
+	}
+	sig.A, sig.E = A, e
+	return m, nil
+}
+
+// gens holds the generators for signing L messages.
+type gens struct {
+	P1, Q1 *bn256.G1
+	H      []*bn256.G1
+}
+
+// generators returns the generators for signing L messages. The generators
+// for fewer messages are a prefix of those for more.
+func generators(L int) *gens {
+	g := &gens{
+		P1: new(bn256.G1).ScalarBaseMult(big.NewInt(1)),
+		Q1: bn256.HashToG1([]byte("Q1"), generatorDomain),
+		H:  make([]*bn256.G1, L),
+	}
+	for i := range g.H {
+		g.H[i] = bn256.HashToG1(appendUint64([]byte("H"), uint64(i)), generatorDomain)
+	}
+	return g
+}
+
+// calculateDomain returns the scalar that binds a signature to the public
+// key, the generators and the header.
+func calculateDomain(pk *PublicKey, g *gens, header []byte) *big.Int {
+	t := transcript.New(domainDomain)
+	t.AppendG2("W", pk.W)
+	t.AppendMessage("L", appendUint64(nil, uint64(len(g.H))))
+	t.AppendG1("Q1", g.Q1)
+	for _, h := range g.H {
+		t.AppendG1("H", h)
+	}
+	t.AppendMessage("header", header)
+	return t.ChallengeScalar("domain")
+}
+
+// computeB returns P₁ + domain·Q₁ + Σ mᵢ·Hᵢ.
+func computeB(g *gens, domain *big.Int, messages []*big.Int) *bn256.G1 {
+	points := append([]*bn256.G1{g.P1, g.Q1}, g.H...)
+	scalars := append([]*big.Int{big.NewInt(1), domain}, messages...)
+	return new(bn256.G1).MultiScalarMult(points, scalars)
+}
+
+// MessageToScalar returns the scalar that msg is signed as.
+func MessageToScalar(msg []byte) *big.Int {
+	t := transcript.New(messageDomain)
+	t.AppendMessage("msg", msg)
+	return t.ChallengeScalar("m")
+}
+
+func messagesToScalars(messages [][]byte) []*big.Int {
+	ret := make([]*big.Int, len(messages))
+	for i, m := range messages {
+		ret[i] = MessageToScalar(m)
+	}
+	return ret
+}
+
+// appendScalar appends the 32-byte big-endian encoding of k mod Order to b.
+func appendScalar(b []byte, k *big.Int) []byte {
+	r := new(big.Int).Mod(k, bn256.Order).Bytes()
+	b = append(b, make([]byte, 32-len(r))...)
+	return append(b, r...)
+}
+
+func appendUint64(b []byte, x uint64) []byte {
+	for i := 7; i >= 0; i-- {
+		b = append(b, byte(x>>uint(8*i)))
+	}
+	return b
+}
+
+// readScalar reads a canonical scalar from m and returns it and the rest of m.
+func readScalar(m []byte) (*big.Int, []byte, error) {
+	if len(m) < 32 {
+		return nil, nil, errMalformed
+	}
+	k := new(big.Int).SetBytes(m[:32])
+	if !canonical(k) {
+		return nil, nil, errMalformed
+	}
+	return k, m[32:], nil
+}
+
+// canonical returns true iff k is a reduced scalar.
+func canonical(k *big.Int) bool {
+	return k.Sign() >= 0 && k.Cmp(bn256.Order) < 0
+}
+
+// isIdentity returns true iff m is the encoding of the identity.
+func isIdentity(m []byte) bool {
+	for _, b := range m {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,206 @@
+package bbs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+var testMessages = [][]byte{
+	[]byte("name: Alice"),
+	[]byte("born: 1990-01-01"),
+	[]byte("country: NZ"),
+	[]byte("licence: B"),
+	[]byte(""),
+}
+
+func keyGen(t *testing.T) (*SecretKey, *PublicKey) {
+	t.Helper()
+
+	sk, pk, err := KeyGen(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sk, pk
+}
+
+func TestSignVerify(t *testing.T) {
+	sk, pk := keyGen(t)
+	header := []byte("issuer: DMV")
+
+	for _, msgs := range [][][]byte{nil, testMessages[:1], testMessages} {
+		sig, err := Sign(sk, pk, header, msgs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(pk, sig, header, msgs); err != nil {
+			t.Fatalf("%d messages: %v", len(msgs), err)
+		}
+
+		other := new(Signature)
+		if _, err := other.Unmarshal(sig.Marshal()); err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(pk, other, header, msgs); err != nil {
+			t.Fatal("signature rejected after round trip")
+		}
+	}
+
+	sig, _ := Sign(sk, pk, header, testMessages)
+	again, _ := Sign(sk, pk, header, testMessages)
+	if !bytes.Equal(sig.Marshal(), again.Marshal()) {
+		t.Fatal("signing is not deterministic")
+	}
+
+	changed := append([][]byte(nil), testMessages...)
+	changed[2] = []byte("country: AU")
+	if err := Verify(pk, sig, header, changed); err == nil {
+		t.Fatal("signature accepted for changed messages")
+	}
+	if err := Verify(pk, sig, header, testMessages[:4]); err == nil {
+		t.Fatal("signature accepted for fewer messages")
+	}
+	if err := Verify(pk, sig, []byte("issuer: other"), testMessages); err == nil {
+		t.Fatal("signature accepted with another header")
+	}
+	_, otherPK := keyGen(t)
+	if err := Verify(otherPK, sig, header, testMessages); err == nil {
+		t.Fatal("signature accepted under another key")
+	}
+
+	bad := &Signature{sig.A, new(big.Int).Add(sig.E, big.NewInt(1))}
+	if err := Verify(pk, bad, header, testMessages); err == nil {
+		t.Fatal("signature with altered e accepted")
+	}
+	identity := &Signature{new(bn256.G1).ScalarBaseMult(new(big.Int)), sig.E}
+	if err := Verify(pk, identity, header, testMessages); err == nil {
+		t.Fatal("signature with identity A accepted")
+	}
+}
+
+func TestProof(t *testing.T) {
+	sk, pk := keyGen(t)
+	header, ph := []byte("issuer: DMV"), []byte("nonce 1234")
+	sig, _ := Sign(sk, pk, header, testMessages)
+
+	for _, disclosed := range [][]int{nil, {0}, {3, 1}, {0, 1, 2, 3, 4}} {
+		proof, err := ProofGen(pk, sig, header, ph, testMessages, disclosed, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs := make([][]byte, len(disclosed))
+		for i, j := range disclosed {
+			msgs[i] = testMessages[j]
+		}
+
+		decoded := new(Proof)
+		if err := decoded.Unmarshal(proof.Marshal()); err != nil {
+			t.Fatal(err)
+		}
+		if err := ProofVerify(pk, decoded, header, ph, disclosed, msgs); err != nil {
+			t.Fatalf("disclosing %v: %v", disclosed, err)
+		}
+
+		if err := ProofVerify(pk, proof, header, []byte("nonce 5678"), disclosed, msgs); err == nil {
+			t.Fatal("proof accepted with another presentation header")
+		}
+		if err := ProofVerify(pk, proof, []byte("issuer: other"), ph, disclosed, msgs); err == nil {
+			t.Fatal("proof accepted with another header")
+		}
+		if len(disclosed) > 0 {
+			lie := append([][]byte(nil), msgs...)
+			lie[0] = []byte("forged")
+			if err := ProofVerify(pk, proof, header, ph, disclosed, lie); err == nil {
+				t.Fatal("proof accepted for a forged disclosed message")
+			}
+		}
+	}
+}
+
+func TestProofUnlinkable(t *testing.T) {
+	sk, pk := keyGen(t)
+	sig, _ := Sign(sk, pk, nil, testMessages)
+
+	a, _ := ProofGen(pk, sig, nil, nil, testMessages, []int{0}, rand.Reader)
+	b, _ := ProofGen(pk, sig, nil, nil, testMessages, []int{0}, rand.Reader)
+	if bytes.Equal(a.Abar.Marshal(), b.Abar.Marshal()) || bytes.Equal(a.D.Marshal(), b.D.Marshal()) {
+		t.Fatal("proofs share values")
+	}
+}
+
+func TestProofTampered(t *testing.T) {
+	sk, pk := keyGen(t)
+	sig, _ := Sign(sk, pk, nil, testMessages)
+	proof, _ := ProofGen(pk, sig, nil, nil, testMessages, []int{1, 2}, rand.Reader)
+	msgs := [][]byte{testMessages[1], testMessages[2]}
+	g := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	one := big.NewInt(1)
+
+	for i, f := range []func(p *Proof){
+		func(p *Proof) { p.Abar = new(bn256.G1).Add(p.Abar, g) },
+		func(p *Proof) { p.Bbar = new(bn256.G1).Add(p.Bbar, g) },
+		func(p *Proof) { p.D = new(bn256.G1).Add(p.D, g) },
+		func(p *Proof) { p.EHat = add(p.EHat, one) },
+		func(p *Proof) { p.R1Hat = add(p.R1Hat, one) },
+		func(p *Proof) { p.R3Hat = add(p.R3Hat, one) },
+		func(p *Proof) { p.MHat[0] = add(p.MHat[0], one) },
+		func(p *Proof) { p.C = add(p.C, one) },
+		func(p *Proof) { p.MHat = p.MHat[1:] },
+		func(p *Proof) {
+			// Scaling Abar and Bbar together keeps the pairing equation.
+			p.Abar = new(bn256.G1).Add(p.Abar, p.Abar)
+			p.Bbar = new(bn256.G1).Add(p.Bbar, p.Bbar)
+		},
+	} {
+		p := new(Proof)
+		if err := p.Unmarshal(proof.Marshal()); err != nil {
+			t.Fatal(err)
+		}
+		f(p)
+		if err := ProofVerify(pk, p, nil, nil, []int{1, 2}, msgs); err == nil {
+			t.Errorf("tampered proof %d accepted", i)
+		}
+	}
+
+	if err := ProofVerify(pk, proof, nil, nil, []int{2, 1}, [][]byte{msgs[1], msgs[0]}); err != nil {
+		t.Fatal("proof rejected with disclosed messages in another order")
+	}
+	if err := ProofVerify(pk, proof, nil, nil, []int{1, 1}, msgs); err == nil {
+		t.Fatal("repeated index accepted")
+	}
+}
+
+func TestProofGenChecksSignature(t *testing.T) {
+	sk, pk := keyGen(t)
+	sig, _ := Sign(sk, pk, nil, testMessages)
+	if _, err := ProofGen(pk, sig, nil, nil, testMessages[:4], nil, rand.Reader); err == nil {
+		t.Fatal("proof generated for the wrong messages")
+	}
+	if _, err := ProofGen(pk, sig, nil, nil, testMessages, []int{5}, rand.Reader); err == nil {
+		t.Fatal("index out of range accepted")
+	}
+}
+
+func BenchmarkProofGen(b *testing.B) {
+	sk, pk, _ := KeyGen(rand.Reader)
+	sig, _ := Sign(sk, pk, nil, testMessages)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ProofGen(pk, sig, nil, nil, testMessages, []int{0}, rand.Reader)
+	}
+}
+
+func BenchmarkProofVerify(b *testing.B) {
+	sk, pk, _ := KeyGen(rand.Reader)
+	sig, _ := Sign(sk, pk, nil, testMessages)
+	proof, _ := ProofGen(pk, sig, nil, nil, testMessages, []int{0}, rand.Reader)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ProofVerify(pk, proof, nil, nil, []int{0}, testMessages[:1])
+	}
+}
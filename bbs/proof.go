@@ -0,0 +1,265 @@
+package bbs
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/transcript"
+)
+
+// Proof is a zero-knowledge proof of knowledge of a signature on a vector of
+// messages, of which some are disclosed. It reveals nothing about the
+// signature or the undisclosed messages, and two proofs derived from the same
+// signature cannot be linked.
+type Proof struct {
+	Abar, Bbar, D      *bn256.G1
+	EHat, R1Hat, R3Hat *big.Int
+	MHat               []*big.Int // responses for the undisclosed messages
+	C                  *big.Int
+}
+
+// ProofGen returns a proof of knowledge of sig, which must be a signature of
+// messages and header under pk, that discloses the messages at the given
+// indices. ph is bound to the proof, for example to prevent its replay in
+// another presentation. Randomness is read from r.
+func ProofGen(pk *PublicKey, sig *Signature, header, ph []byte, messages [][]byte, disclosed []int, r io.Reader) (*Proof, error) {
+	L := len(messages)
+	disclosed, undisclosed, err := splitIndices(disclosed, L)
+	if err != nil {
+		return nil, err
+	}
+	if err := Verify(pk, sig, header, messages); err != nil {
+		return nil, err
+	}
+
+	gens := generators(L)
+	domain := calculateDomain(pk, gens, header)
+	scalars := messagesToScalars(messages)
+
+	rs, err := proofNonces(r, 5+len(undisclosed))
+	if err != nil {
+		return nil, err
+	}
+	r1, r2, eTilde, r1Tilde, r3Tilde, mTilde := rs[0], rs[1], rs[2], rs[3], rs[4], rs[5:]
+	if r2.Sign() == 0 {
+		return nil, errInvalid
+	}
+
+	B := computeB(gens, domain, scalars)
+	D := new(bn256.G1).ScalarMult(B, r2)
+	Abar := new(bn256.G1).ScalarMult(sig.A, mul(r1, r2))
+	// Bbar = r1·D - e·Abar
+	Bbar := new(bn256.G1).MultiScalarMult([]*bn256.G1{D, Abar}, []*big.Int{r1, neg(sig.E)})
+
+	T1 := new(bn256.G1).MultiScalarMult([]*bn256.G1{Abar, D}, []*big.Int{eTilde, r1Tilde})
+	T2points := []*bn256.G1{D}
+	for _, j := range undisclosed {
+		T2points = append(T2points, gens.H[j])
+	}
+	T2 := new(bn256.G1).MultiScalarMult(T2points, append([]*big.Int{r3Tilde}, mTilde...))
+
+	c := challenge(Abar, Bbar, D, T1, T2, disclosed, scalars, domain, ph)
+
+	proof := &Proof{
+		Abar:  Abar,
+		Bbar:  Bbar,
+		D:     D,
+		EHat:  add(eTilde, mul(sig.E, c)),
+		R1Hat: sub(r1Tilde, mul(r1, c)),
+		R3Hat: sub(r3Tilde, mul(new(big.Int).ModInverse(r2, bn256.Order), c)),
+		MHat:  make([]*big.Int, len(undisclosed)),
+		C:     c,
+	}
+	for i, j := range undisclosed {
+		proof.MHat[i] = add(mTilde[i], mul(scalars[j], c))
+	}
+	return proof, nil
+}
+
+// ProofVerify returns nil iff proof is a valid proof of knowledge of a
+// signature under pk on messages including disclosedMessages at
+// disclosedIndices, bound to header and ph.
+func ProofVerify(pk *PublicKey, proof *Proof, header, ph []byte, disclosedIndices []int, disclosedMessages [][]byte) error {
+	if len(disclosedIndices) != len(disclosedMessages) || proof.Abar == nil || proof.Bbar == nil ||
+		proof.D == nil || proof.EHat == nil || proof.R1Hat == nil || proof.R3Hat == nil || proof.C == nil {
+		return errMalformed
+	}
+	for _, m := range proof.MHat {
+		if m == nil {
+			return errMalformed
+		}
+	}
+	if isIdentity(proof.Abar.Marshal()) {
+		return errInvalid
+	}
+
+	// Sort the disclosed messages by index.
+	byIndex := make(map[int][]byte, len(disclosedIndices))
+	for i, j := range disclosedIndices {
+		byIndex[j] = disclosedMessages[i]
+	}
+	L := len(proof.MHat) + len(disclosedIndices)
+	disclosed, undisclosed, err := splitIndices(disclosedIndices, L)
+	if err != nil {
+		return err
+	}
+	scalars := make([]*big.Int, L)
+	for _, j := range disclosed {
+		scalars[j] = MessageToScalar(byIndex[j])
+	}
+
+	gens := generators(L)
+	domain := calculateDomain(pk, gens, header)
+
+	// T1 = c·Bbar + ê·Abar + r̂1·D
+	T1 := new(bn256.G1).MultiScalarMult(
+		[]*bn256.G1{proof.Bbar, proof.Abar, proof.D},
+		[]*big.Int{proof.C, proof.EHat, proof.R1Hat},
+	)
+
+	// T2 = c·Bv + r̂3·D + Σ m̂ⱼ·Hⱼ, where Bv = P₁ + domain·Q₁ + Σ mᵢ·Hᵢ over
+	// the disclosed messages.
+	points := []*bn256.G1{gens.P1, gens.Q1, proof.D}
+	coeffs := []*big.Int{proof.C, mul(domain, proof.C), proof.R3Hat}
+	for _, j := range disclosed {
+		points = append(points, gens.H[j])
+		coeffs = append(coeffs, mul(scalars[j], proof.C))
+	}
+	for i, j := range undisclosed {
+		points = append(points, gens.H[j])
+		coeffs = append(coeffs, proof.MHat[i])
+	}
+	T2 := new(bn256.G1).MultiScalarMult(points, coeffs)
+
+	c := challenge(proof.Abar, proof.Bbar, proof.D, T1, T2, disclosed, scalars, domain, ph)
+	if c.Cmp(proof.C) != 0 {
+		return errInvalid
+	}
+
+	// e(Abar, W) = e(Bbar, g₂)
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	negBbar := new(bn256.G1).Neg(proof.Bbar)
+	if !bn256.PairingCheck([]*bn256.G1{proof.Abar, negBbar}, []*bn256.G2{pk.W, g2}) {
+		return errInvalid
+	}
+	return nil
+}
+
+// challenge returns the Fiat-Shamir challenge of a proof.
+func challenge(Abar, Bbar, D, T1, T2 *bn256.G1, disclosed []int, scalars []*big.Int, domain *big.Int, ph []byte) *big.Int {
+	t := transcript.New(challengeDomain)
+	t.AppendMessage("R", appendUint64(nil, uint64(len(disclosed))))
+	for _, j := range disclosed {
+		t.AppendMessage("i", appendUint64(nil, uint64(j)))
+		t.AppendScalar("m", scalars[j])
+	}
+	t.AppendG1("Abar", Abar)
+	t.AppendG1("Bbar", Bbar)
+	t.AppendG1("D", D)
+	t.AppendG1("T1", T1)
+	t.AppendG1("T2", T2)
+	t.AppendScalar("domain", domain)
+	t.AppendMessage("ph", ph)
+	return t.ChallengeScalar("c")
+}
+
+// splitIndices returns the sorted disclosed indices and the undisclosed ones
+// out of L.
+func splitIndices(indices []int, L int) (disclosed, undisclosed []int, err error) {
+	seen := make([]bool, L)
+	for _, j := range indices {
+		if j < 0 || j >= L || seen[j] {
+			return nil, nil, errIndex
+		}
+		seen[j] = true
+	}
+	disclosed = append([]int(nil), indices...)
+	sort.Ints(disclosed)
+	for j := 0; j < L; j++ {
+		if !seen[j] {
+			undisclosed = append(undisclosed, j)
+		}
+	}
+	return disclosed, undisclosed, nil
+}
+
+// Marshal converts proof into a byte slice: Abar, Bbar and D followed by ê,
+// r̂1, r̂3, the m̂ⱼ and c.
+func (proof *Proof) Marshal() []byte {
+	var ret []byte
+	for _, p := range []*bn256.G1{proof.Abar, proof.Bbar, proof.D} {
+		ret = append(ret, p.Marshal()...)
+	}
+	for _, k := range append([]*big.Int{proof.EHat, proof.R1Hat, proof.R3Hat}, proof.MHat...) {
+		ret = appendScalar(ret, k)
+	}
+	return appendScalar(ret, proof.C)
+}
+
+// Unmarshal sets proof to the result of converting the output of Marshal back
+// into a proof. It consumes all of m, since the number of undisclosed messages
+// is not known in advance.
+func (proof *Proof) Unmarshal(m []byte) error {
+	const fixed = 3*64 + 4*32
+	if len(m) < fixed || (len(m)-fixed)%32 != 0 {
+		return errMalformed
+	}
+
+	points := make([]*bn256.G1, 3)
+	var err error
+	for i := range points {
+		points[i] = new(bn256.G1)
+		if m, err = points[i].Unmarshal(m); err != nil {
+			return err
+		}
+	}
+	scalars := make([]*big.Int, len(m)/32)
+	for i := range scalars {
+		if scalars[i], m, err = readScalar(m); err != nil {
+			return err
+		}
+	}
+
+	n := len(scalars)
+	proof.Abar, proof.Bbar, proof.D = points[0], points[1], points[2]
+	proof.EHat, proof.R1Hat, proof.R3Hat = scalars[0], scalars[1], scalars[2]
+	proof.MHat = scalars[3 : n-1]
+	proof.C = scalars[n-1]
+	return nil
+}
+
+func add(a, b *big.Int) *big.Int {
+	c := new(big.Int).Add(a, b)
+	return c.Mod(c, bn256.Order)
+}
+
+func sub(a, b *big.Int) *big.Int {
+	c := new(big.Int).Sub(a, b)
+	return c.Mod(c, bn256.Order)
+}
+
+func mul(a, b *big.Int) *big.Int {
+	c := new(big.Int).Mul(a, b)
+	return c.Mod(c, bn256.Order)
+}
+
+func neg(a *big.Int) *big.Int {
+	c := new(big.Int).Neg(a)
+	return c.Mod(c, bn256.Order)
+}
+
+// proofNonces returns n blinding scalars for a proof, read from r. A zero
+// nonce is as likely as any other and is not rejected.
+func proofNonces(r io.Reader, n int) ([]*big.Int, error) {
+	rs := make([]*big.Int, n)
+	for i := range rs {
+		var err error
+		if rs[i], err = rand.Int(r, bn256.Order); err != nil {
+			return nil, err
+		}
+	}
+	return rs, nil
+}
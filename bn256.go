@@ -11,6 +11,14 @@
 // Barreto-Naehrig curve as described in
 // http://cryptojedi.org/papers/dclxvi-20100714.pdf. Its output is compatible
 // with the implementation described in that paper.
+//
+// Values of type G1, G2 and GT, and of the field types, may be read from many
+// goroutines at once: methods that do not set their receiver, such as Marshal
+// and String, and methods that take the value as an argument, such as Add or
+// Pair, never modify it. A method that sets its receiver, such as ScalarMult,
+// Unmarshal or Finalize, must not run concurrently with any other use of that
+// receiver, and BatchNormalizeG1 and BatchNormalizeG2 set every point they are
+// given.
 package bn256
 
 import (
@@ -46,7 +54,7 @@ func RandomG1(r io.Reader) (*big.Int, *G1, error) {
 }
 
 func (g *G1) String() string {
-	return "bn256.G1" + g.p.String()
+	return "bn256.G1" + g.point().String()
 }
 
 // ScalarBaseMult sets e to g*k where g is the generator of the group and then
@@ -100,19 +108,25 @@ func (e *G1) Marshal() []byte {
 	// Each value is a 256-bit number.
 	const numBytes = 256 / 8
 
-	e.p.MakeAffine()
 	ret := make([]byte, numBytes*2)
-	if e.p.IsInfinity() {
-		return ret
+	marshalG1(ret, e.point().affine())
+	return ret
+}
+
+// marshalG1 writes the encoding of p, which must be affine, to out.
+func marshalG1(out []byte, p *curvePoint) {
+	// Each value is a 256-bit number.
+	const numBytes = 256 / 8
+
+	if p.IsInfinity() {
+		return
 	}
 	temp := &gfP{}
 
-	montDecode(temp, &e.p.x)
-	temp.Marshal(ret)
-	montDecode(temp, &e.p.y)
-	temp.Marshal(ret[numBytes:])
-
-	return ret
+	montDecode(temp, &p.x)
+	temp.Marshal(out)
+	montDecode(temp, &p.y)
+	temp.Marshal(out[numBytes:])
 }
 
 // Unmarshal sets e to the result of converting the output of Marshal back into
@@ -157,6 +171,52 @@ func (e *G1) Unmarshal(m []byte) ([]byte, error) {
 	return m[2*numBytes:], nil
 }
 
+// SetCoordinates sets e to the point with affine coordinates (x, y). As in
+// Unmarshal, (0, 0) stands for the identity, and an error is returned, leaving
+// e unchanged, if a coordinate is not in [0, P) or the point is not on the
+// curve.
+func (e *G1) SetCoordinates(x, y *big.Int) error {
+	for _, k := range []*big.Int{x, y} {
+		if k.Sign() < 0 || k.Cmp(P) >= 0 {
+			return errors.New("bn256: coordinate exceeds modulus")
+		}
+	}
+
+	c := &curvePoint{}
+	if x.Sign() == 0 && y.Sign() == 0 {
+		c.SetInfinity()
+	} else {
+		c.x, c.y = gfPFromBig(x), gfPFromBig(y)
+		montEncode(&c.x, &c.x)
+		montEncode(&c.y, &c.y)
+		c.z = *newGFp(1)
+		c.t = *newGFp(1)
+
+		if !c.IsOnCurve() {
+			return errors.New("bn256: malformed point")
+		}
+	}
+
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.Set(c)
+	return nil
+}
+
+// Coordinates returns the affine coordinates of e, or (0, 0) if e is the
+// identity.
+func (e *G1) Coordinates() (x, y *big.Int) {
+	a := &curvePoint{}
+	if e.p != nil {
+		a = e.p.affine()
+	}
+	if a.IsInfinity() {
+		return new(big.Int), new(big.Int)
+	}
+	return (&Fp{a.x}).Big(), (&Fp{a.y}).Big()
+}
+
 // G2 is an abstract cyclic group. The zero value is suitable for use as the
 // output of an operation, but cannot be used as an input.
 type G2 struct {
@@ -174,7 +234,7 @@ func RandomG2(r io.Reader) (*big.Int, *G2, error) {
 }
 
 func (e *G2) String() string {
-	return "bn256.G2" + e.p.String()
+	return "bn256.G2" + e.point().String()
 }
 
 // ScalarBaseMult sets e to g*k where g is the generator of the group and then
@@ -228,27 +288,32 @@ func (e *G2) Marshal() []byte {
 	// Each value is a 256-bit number.
 	const numBytes = 256 / 8
 
+	ret := make([]byte, numBytes*4)
 	if e.p == nil {
-		e.p = &twistPoint{}
+		return ret
 	}
+	marshalG2(ret, e.p.affine())
+	return ret
+}
 
-	e.p.MakeAffine()
-	ret := make([]byte, numBytes*4)
-	if e.p.IsInfinity() {
-		return ret
+// marshalG2 writes the encoding of p, which must be affine, to out.
+func marshalG2(out []byte, p *twistPoint) {
+	// Each value is a 256-bit number.
+	const numBytes = 256 / 8
+
+	if p.IsInfinity() {
+		return
 	}
 	temp := &gfP{}
 
-	montDecode(temp, &e.p.x.x)
-	temp.Marshal(ret)
-	montDecode(temp, &e.p.x.y)
-	temp.Marshal(ret[numBytes:])
-	montDecode(temp, &e.p.y.x)
-	temp.Marshal(ret[2*numBytes:])
-	montDecode(temp, &e.p.y.y)
-	temp.Marshal(ret[3*numBytes:])
-
-	return ret
+	montDecode(temp, &p.x.x)
+	temp.Marshal(out)
+	montDecode(temp, &p.x.y)
+	temp.Marshal(out[numBytes:])
+	montDecode(temp, &p.y.x)
+	temp.Marshal(out[2*numBytes:])
+	montDecode(temp, &p.y.y)
+	temp.Marshal(out[3*numBytes:])
 }
 
 // Unmarshal sets e to the result of converting the output of Marshal back into
@@ -262,6 +327,8 @@ func (e *G2) Unmarshal(m []byte) ([]byte, error) {
 	// Unmarshal the points and check their caps
 	if e.p == nil {
 		e.p = &twistPoint{}
+	} else {
+		e.p.x, e.p.y = gfP2{}, gfP2{}
 	}
 	var err error
 	if err = e.p.x.x.Unmarshal(m); err != nil {
@@ -298,6 +365,43 @@ func (e *G2) Unmarshal(m []byte) ([]byte, error) {
 	return m[4*numBytes:], nil
 }
 
+// SetCoordinates sets e to the point with affine coordinates (x, y). As in
+// Unmarshal, (0, 0) stands for the identity, and an error is returned, leaving
+// e unchanged, if the point is not on the twist or not in the group.
+func (e *G2) SetCoordinates(x, y *Fp2) error {
+	c := &twistPoint{}
+	if x.IsZero() && y.IsZero() {
+		c.SetInfinity()
+	} else {
+		c.x, c.y = x.p, y.p
+		c.z.SetOne()
+		c.t.SetOne()
+
+		if !c.IsOnCurve() {
+			return errors.New("bn256: malformed point")
+		}
+	}
+
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.Set(c)
+	return nil
+}
+
+// Coordinates returns the affine coordinates of e, or (0, 0) if e is the
+// identity.
+func (e *G2) Coordinates() (x, y *Fp2) {
+	a := &twistPoint{}
+	if e.p != nil {
+		a = e.p.affine()
+	}
+	if a.IsInfinity() {
+		return new(Fp2), new(Fp2)
+	}
+	return &Fp2{a.x}, &Fp2{a.y}
+}
+
 // GT is an abstract cyclic group. The zero value is suitable for use as the
 // output of an operation, but cannot be used as an input.
 type GT struct {
@@ -331,7 +435,7 @@ func Miller(g1 *G1, g2 *G2) *GT {
 }
 
 func (g *GT) String() string {
-	return "bn256.GT" + g.p.String()
+	return "bn256.GT" + g.point().String()
 }
 
 // ScalarMult sets e to a*k and then returns e.
@@ -383,31 +487,32 @@ func (e *GT) Marshal() []byte {
 	const numBytes = 256 / 8
 
 	ret := make([]byte, numBytes*12)
+	p := e.point()
 	temp := &gfP{}
 
-	montDecode(temp, &e.p.x.x.x)
+	montDecode(temp, &p.x.x.x)
 	temp.Marshal(ret)
-	montDecode(temp, &e.p.x.x.y)
+	montDecode(temp, &p.x.x.y)
 	temp.Marshal(ret[numBytes:])
-	montDecode(temp, &e.p.x.y.x)
+	montDecode(temp, &p.x.y.x)
 	temp.Marshal(ret[2*numBytes:])
-	montDecode(temp, &e.p.x.y.y)
+	montDecode(temp, &p.x.y.y)
 	temp.Marshal(ret[3*numBytes:])
-	montDecode(temp, &e.p.x.z.x)
+	montDecode(temp, &p.x.z.x)
 	temp.Marshal(ret[4*numBytes:])
-	montDecode(temp, &e.p.x.z.y)
+	montDecode(temp, &p.x.z.y)
 	temp.Marshal(ret[5*numBytes:])
-	montDecode(temp, &e.p.y.x.x)
+	montDecode(temp, &p.y.x.x)
 	temp.Marshal(ret[6*numBytes:])
-	montDecode(temp, &e.p.y.x.y)
+	montDecode(temp, &p.y.x.y)
 	temp.Marshal(ret[7*numBytes:])
-	montDecode(temp, &e.p.y.y.x)
+	montDecode(temp, &p.y.y.x)
 	temp.Marshal(ret[8*numBytes:])
-	montDecode(temp, &e.p.y.y.y)
+	montDecode(temp, &p.y.y.y)
 	temp.Marshal(ret[9*numBytes:])
-	montDecode(temp, &e.p.y.z.x)
+	montDecode(temp, &p.y.z.x)
 	temp.Marshal(ret[10*numBytes:])
-	montDecode(temp, &e.p.y.z.y)
+	montDecode(temp, &p.y.z.y)
 	temp.Marshal(ret[11*numBytes:])
 
 	return ret
@@ -425,6 +530,8 @@ func (e *GT) Unmarshal(m []byte) ([]byte, error) {
 
 	if e.p == nil {
 		e.p = &gfP12{}
+	} else {
+		*e.p = gfP12{}
 	}
 
 	var err error
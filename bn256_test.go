@@ -3,6 +3,8 @@ package bn256
 import (
 	"bytes"
 	"crypto/rand"
+	"math/big"
+	"sync"
 	"testing"
 )
 
@@ -44,6 +46,95 @@ func TestG2Marshal(t *testing.T) {
 	}
 }
 
+func TestG1Coordinates(t *testing.T) {
+	_, g, _ := RandomG1(rand.Reader)
+	x, y := g.Coordinates()
+	m := g.Marshal()
+	if x.Cmp(new(big.Int).SetBytes(m[:32])) != 0 || y.Cmp(new(big.Int).SetBytes(m[32:])) != 0 {
+		t.Fatal("coordinates differ from Marshal")
+	}
+
+	h := new(G1)
+	if err := h.SetCoordinates(x, y); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(h.Marshal(), m) {
+		t.Fatal("SetCoordinates did not round trip")
+	}
+
+	zero := new(big.Int)
+	if err := h.SetCoordinates(zero, zero); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(h.Marshal(), make([]byte, 64)) {
+		t.Fatal("(0, 0) is not the identity")
+	}
+	if x, y := h.Coordinates(); x.Sign() != 0 || y.Sign() != 0 {
+		t.Fatal("identity does not have coordinates (0, 0)")
+	}
+	if x, y := new(G1).Coordinates(); x.Sign() != 0 || y.Sign() != 0 {
+		t.Fatal("zero value does not have coordinates (0, 0)")
+	}
+
+	for _, c := range [][2]*big.Int{
+		{big.NewInt(1), big.NewInt(3)},
+		{new(big.Int).Add(x, P), y},
+		{x, new(big.Int).Neg(y)},
+	} {
+		if err := g.SetCoordinates(c[0], c[1]); err == nil {
+			t.Errorf("(%v, %v) accepted", c[0], c[1])
+		}
+	}
+	if !bytes.Equal(g.Marshal(), m) {
+		t.Error("failed SetCoordinates changed the point")
+	}
+}
+
+func TestG2Coordinates(t *testing.T) {
+	_, g, _ := RandomG2(rand.Reader)
+	x, y := g.Coordinates()
+	m := g.Marshal()
+	if !bytes.Equal(append(x.Bytes(), y.Bytes()...), m) {
+		t.Fatal("coordinates differ from Marshal")
+	}
+
+	h := new(G2)
+	if err := h.SetCoordinates(x, y); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(h.Marshal(), m) {
+		t.Fatal("SetCoordinates did not round trip")
+	}
+	if err := h.SetCoordinates(new(Fp2), new(Fp2)); err != nil || !bytes.Equal(h.Marshal(), make([]byte, 128)) {
+		t.Fatal("(0, 0) is not the identity")
+	}
+	if x, y := new(G2).Coordinates(); !x.IsZero() || !y.IsZero() {
+		t.Fatal("zero value does not have coordinates (0, 0)")
+	}
+
+	// (x, y·ξ) is off the twist, and points of the twist outside G₂ exist
+	// for small real x.
+	if err := g.SetCoordinates(x, new(Fp2).MulXi(y)); err == nil {
+		t.Error("point off the twist accepted")
+	}
+	b := new(Fp2).SetParts(new(Fp).SetInt64(3), new(Fp))
+	b.Mul(b, new(Fp2).Invert(new(Fp2).SetBig(big.NewInt(9), big.NewInt(1))))
+	for i := int64(1); i < 100; i++ {
+		a := new(Fp2).SetBig(big.NewInt(i), new(big.Int))
+		rhs := new(Fp2).Square(a)
+		rhs.Mul(rhs, a).Add(rhs, b)
+		if root := new(Fp2).Sqrt(rhs); root != nil {
+			if err := g.SetCoordinates(a, root); err == nil {
+				t.Error("point outside G₂ accepted")
+			}
+			break
+		}
+	}
+	if !bytes.Equal(g.Marshal(), m) {
+		t.Error("failed SetCoordinates changed the point")
+	}
+}
+
 func TestBilinearity(t *testing.T) {
 	for i := 0; i < 2; i++ {
 		a, p1, _ := RandomG1(rand.Reader)
@@ -92,6 +183,76 @@ func TestTripartiteDiffieHellman(t *testing.T) {
 	}
 }
 
+func TestAddAliased(t *testing.T) {
+	k, Ga, err := RandomG1(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Gb := new(G1).ScalarBaseMult(new(big.Int).Lsh(k, 1))
+	if Ga.Add(Ga, Ga); !bytes.Equal(Ga.Marshal(), Gb.Marshal()) {
+		t.Fatal("G1: a+a != 2a")
+	}
+
+	k, Ha, err := RandomG2(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Hb := new(G2).ScalarBaseMult(new(big.Int).Lsh(k, 1))
+	if Ha.Add(Ha, Ha); !bytes.Equal(Ha.Marshal(), Hb.Marshal()) {
+		t.Fatal("G2: a+a != 2a")
+	}
+}
+
+// TestConcurrentReads shares points in projective coordinates between
+// goroutines that only read them; run with -race to check the contract in the
+// package documentation.
+func TestConcurrentReads(t *testing.T) {
+	k, _ := rand.Int(rand.Reader, Order)
+	g1 := new(G1).ScalarBaseMult(k)
+	g2 := new(G2).ScalarBaseMult(k)
+	gt := Miller(g1, g2)
+	before1, before2 := *g1.p, *g2.p
+	want1 := new(G1).Set(g1).Marshal()
+	want2 := new(G2).Set(g2).Marshal()
+	wantPair := Pair(new(G1).Set(g1), new(G2).Set(g2)).Marshal()
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 64)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !bytes.Equal(g1.Marshal(), want1) {
+				errs <- "G1 Marshal"
+			}
+			if !bytes.Equal(g2.Marshal(), want2) {
+				errs <- "G2 Marshal"
+			}
+			if !bytes.Equal(Pair(g1, g2).Marshal(), wantPair) {
+				errs <- "Pair"
+			}
+			_ = g1.String() + g2.String() + gt.String()
+			gt.Marshal()
+			new(G1).Add(g1, g1)
+			new(G2).ScalarMult(g2, k)
+			new(GT).Add(gt, gt)
+			new(G1).MultiScalarMult([]*G1{g1, g1}, []*big.Int{k, k})
+			BatchMarshalG1([]*G1{g1})
+			BatchMarshalG2([]*G2{g2})
+			PairingCheck([]*G1{g1}, []*G2{g2})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for e := range errs {
+		t.Errorf("%s returned a wrong result", e)
+	}
+	if *g1.p != before1 || *g2.p != before2 {
+		t.Error("reading a point modified it")
+	}
+}
+
 func BenchmarkG1(b *testing.B) {
 	x, _ := rand.Int(rand.Reader, Order)
 	b.ResetTimer()
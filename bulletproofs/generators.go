@@ -0,0 +1,72 @@
+package bulletproofs
+
+import (
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// domain is the domain separation tag used to derive generators.
+var domain = []byte("bn256/bulletproofs/generators")
+
+// Generators holds the points used by range proofs.
+//
+// B and BBlinding are the Pedersen bases that values are committed under. G
+// and H hold capacity generators for each of up to parties aggregated values:
+// the generators of party j are G[j] and H[j]. Everything except B, which is
+// the generator of G₁, is derived with bn256.HashToG1, so nobody knows a
+// discrete logarithm relation between any of the points.
+type Generators struct {
+	B, BBlinding *bn256.G1
+	G, H         [][]*bn256.G1
+}
+
+// NewGenerators returns generators for proving that up to parties values each
+// lie in a range of up to capacity bits. NewGenerators is deterministic.
+func NewGenerators(capacity, parties int) *Generators {
+	gens := &Generators{
+		B:         new(bn256.G1).ScalarBaseMult(big.NewInt(1)),
+		BBlinding: bn256.HashToG1([]byte("B_blinding"), domain),
+		G:         make([][]*bn256.G1, parties),
+		H:         make([][]*bn256.G1, parties),
+	}
+	for j := 0; j < parties; j++ {
+		gens.G[j] = make([]*bn256.G1, capacity)
+		gens.H[j] = make([]*bn256.G1, capacity)
+		for i := 0; i < capacity; i++ {
+			gens.G[j][i] = bn256.HashToG1(generatorInput('G', j, i), domain)
+			gens.H[j][i] = bn256.HashToG1(generatorInput('H', j, i), domain)
+		}
+	}
+	return gens
+}
+
+// generatorInput encodes the kind of a generator, its party and its index
+// unambiguously.
+func generatorInput(kind byte, j, i int) []byte {
+	return []byte{
+		kind,
+		byte(j >> 24), byte(j >> 16), byte(j >> 8), byte(j),
+		byte(i >> 24), byte(i >> 16), byte(i >> 8), byte(i),
+	}
+}
+
+// Commit returns the Pedersen commitment v·B + blinding·BBlinding.
+func (gens *Generators) Commit(v uint64, blinding *big.Int) *bn256.G1 {
+	return new(bn256.G1).MultiScalarMult(
+		[]*bn256.G1{gens.B, gens.BBlinding},
+		[]*big.Int{new(big.Int).SetUint64(v), blinding},
+	)
+}
+
+// share returns the G and H generators for an aggregated proof of m values of
+// n bits each, laid out one party after another.
+func (gens *Generators) share(n, m int) (G, H []*bn256.G1) {
+	G = make([]*bn256.G1, 0, n*m)
+	H = make([]*bn256.G1, 0, n*m)
+	for j := 0; j < m; j++ {
+		G = append(G, gens.G[j][:n]...)
+		H = append(H, gens.H[j][:n]...)
+	}
+	return G, H
+}
@@ -0,0 +1,184 @@
+package bulletproofs
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+var (
+	errLength    = errors.New("bulletproofs: vector length is not a power of two or vectors differ in length")
+	errInvalid   = errors.New("bulletproofs: invalid proof")
+	errMalformed = errors.New("bulletproofs: malformed proof")
+)
+
+// InnerProductProof is a proof of knowledge of vectors a and b such that
+//
+//	P = ⟨a, G⟩ + ⟨b, H⟩ + ⟨a, b⟩·Q
+//
+// for public generators G, H and Q and a public point P. Its size is
+// logarithmic in the length of the vectors.
+type InnerProductProof struct {
+	L, R []*bn256.G1
+	A, B *big.Int
+}
+
+// ProveInnerProduct returns a proof that the commitment ⟨a, G⟩ + ⟨b, H⟩ +
+// ⟨a, b⟩·Q is well formed. All four vectors must have the same length, which
+// must be a power of two.
+func ProveInnerProduct(t *Transcript, Q *bn256.G1, G, H []*bn256.G1, a, b []*big.Int) (*InnerProductProof, error) {
+	n := len(G)
+	if n == 0 || n&(n-1) != 0 || len(H) != n || len(a) != n || len(b) != n {
+		return nil, errLength
+	}
+
+	t.AppendMessage("dom-sep", []byte("ipp v1"))
+	t.appendUint64("n", uint64(n))
+
+	// Work on copies so that the caller's vectors are left alone.
+	G = append([]*bn256.G1(nil), G...)
+	H = append([]*bn256.G1(nil), H...)
+	a = append([]*big.Int(nil), a...)
+	b = append([]*big.Int(nil), b...)
+
+	proof := &InnerProductProof{}
+	for n > 1 {
+		n /= 2
+		aLo, aHi := a[:n], a[n:]
+		bLo, bHi := b[:n], b[n:]
+		GLo, GHi := G[:n], G[n:]
+		HLo, HHi := H[:n], H[n:]
+
+		cL := innerProduct(aLo, bHi)
+		cR := innerProduct(aHi, bLo)
+
+		L := new(bn256.G1).MultiScalarMult(
+			concatPoints(GHi, HLo, []*bn256.G1{Q}),
+			concatScalars(aLo, bHi, []*big.Int{cL}),
+		)
+		R := new(bn256.G1).MultiScalarMult(
+			concatPoints(GLo, HHi, []*bn256.G1{Q}),
+			concatScalars(aHi, bLo, []*big.Int{cR}),
+		)
+		proof.L = append(proof.L, L)
+		proof.R = append(proof.R, R)
+
+		t.appendPoint("L", L)
+		t.appendPoint("R", R)
+		u := t.challengeScalar("u")
+		uInv := inv(u)
+		if uInv == nil {
+			return nil, errors.New("bulletproofs: zero challenge")
+		}
+
+		for i := 0; i < n; i++ {
+			aLo[i] = add(mul(aLo[i], u), mul(aHi[i], uInv))
+			bLo[i] = add(mul(bLo[i], uInv), mul(bHi[i], u))
+			GLo[i] = new(bn256.G1).MultiScalarMult([]*bn256.G1{GLo[i], GHi[i]}, []*big.Int{uInv, u})
+			HLo[i] = new(bn256.G1).MultiScalarMult([]*bn256.G1{HLo[i], HHi[i]}, []*big.Int{u, uInv})
+		}
+		a, b, G, H = aLo, bLo, GLo, HLo
+	}
+
+	proof.A, proof.B = a[0], b[0]
+	return proof, nil
+}
+
+// Verify returns nil iff p proves knowledge of an opening of P with respect to
+// the generators G, H and Q. t must be in the same state as the transcript
+// passed to ProveInnerProduct was.
+func (p *InnerProductProof) Verify(t *Transcript, Q, P *bn256.G1, G, H []*bn256.G1) error {
+	n := len(G)
+	if n == 0 || n&(n-1) != 0 || len(H) != n {
+		return errLength
+	}
+
+	u2, uInv2, s, err := p.verificationScalars(t, n)
+	if err != nil {
+		return err
+	}
+
+	// a·⟨s, G⟩ + b·⟨s⁻¹, H⟩ + ab·Q - P - Σ (u²·L + u⁻²·R) = 0
+	points := concatPoints(G, H, []*bn256.G1{Q, P}, p.L, p.R)
+	scalars := make([]*big.Int, 0, len(points))
+	for i := 0; i < n; i++ {
+		scalars = append(scalars, mul(p.A, s[i]))
+	}
+	for i := 0; i < n; i++ {
+		scalars = append(scalars, mul(p.B, s[n-1-i]))
+	}
+	scalars = append(scalars, mul(p.A, p.B), big.NewInt(-1))
+	for _, x := range u2 {
+		scalars = append(scalars, new(big.Int).Neg(x))
+	}
+	for _, x := range uInv2 {
+		scalars = append(scalars, new(big.Int).Neg(x))
+	}
+
+	if !new(bn256.G1).MultiScalarMult(points, scalars).IsIdentity() {
+		return errInvalid
+	}
+	return nil
+}
+
+// verificationScalars replays the transcript of p for vectors of length n. It
+// returns the squares of the challenges, the squares of their inverses and the
+// vector s such that the final generators are G' = ⟨s, G⟩ and H' = ⟨s⁻¹, H⟩.
+// s⁻¹ is s in reverse order.
+func (p *InnerProductProof) verificationScalars(t *Transcript, n int) (u2, uInv2, s []*big.Int, err error) {
+	k := len(p.L)
+	if len(p.R) != k || n != 1<<uint(k) || p.A == nil || p.B == nil {
+		return nil, nil, nil, errMalformed
+	}
+
+	t.AppendMessage("dom-sep", []byte("ipp v1"))
+	t.appendUint64("n", uint64(n))
+
+	u := make([]*big.Int, k)
+	uInv := make([]*big.Int, k)
+	u2 = make([]*big.Int, k)
+	uInv2 = make([]*big.Int, k)
+	for j := 0; j < k; j++ {
+		t.appendPoint("L", p.L[j])
+		t.appendPoint("R", p.R[j])
+		u[j] = t.challengeScalar("u")
+		if uInv[j] = inv(u[j]); uInv[j] == nil {
+			return nil, nil, nil, errInvalid
+		}
+		u2[j] = mul(u[j], u[j])
+		uInv2[j] = mul(uInv[j], uInv[j])
+	}
+
+	// Round j halves the vectors, so the bit k-1-j of an index says whether
+	// it was in the upper half, which is scaled by u, or the lower half,
+	// which is scaled by u⁻¹.
+	s = make([]*big.Int, n)
+	for i := range s {
+		s[i] = big.NewInt(1)
+		for j := 0; j < k; j++ {
+			if i>>uint(k-1-j)&1 == 1 {
+				s[i] = mul(s[i], u[j])
+			} else {
+				s[i] = mul(s[i], uInv[j])
+			}
+		}
+	}
+	return u2, uInv2, s, nil
+}
+
+func concatPoints(vs ...[]*bn256.G1) []*bn256.G1 {
+	var ret []*bn256.G1
+	for _, v := range vs {
+		ret = append(ret, v...)
+	}
+	return ret
+}
+
+func concatScalars(vs ...[]*big.Int) []*big.Int {
+	var ret []*big.Int
+	for _, v := range vs {
+		ret = append(ret, v...)
+	}
+	return ret
+}
@@ -0,0 +1,76 @@
+package bulletproofs
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+func randomVector(t *testing.T, n int) []*big.Int {
+	t.Helper()
+
+	ret, err := randomScalars(rand.Reader, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+func TestInnerProduct(t *testing.T) {
+	gens := NewGenerators(16, 1)
+	G, H := gens.share(16, 1)
+	Q := bn256.HashToG1([]byte("Q"), []byte("test"))
+
+	for _, n := range []int{1, 2, 16} {
+		a, b := randomVector(t, n), randomVector(t, n)
+		P := new(bn256.G1).MultiScalarMult(
+			concatPoints(G[:n], H[:n], []*bn256.G1{Q}),
+			concatScalars(a, b, []*big.Int{innerProduct(a, b)}),
+		)
+
+		proof, err := ProveInnerProduct(NewTranscript("test"), Q, G[:n], H[:n], a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(proof.L) != len(proof.R) || 1<<uint(len(proof.L)) != n {
+			t.Fatalf("n=%d: proof has %d rounds", n, len(proof.L))
+		}
+		if err := proof.Verify(NewTranscript("test"), Q, P, G[:n], H[:n]); err != nil {
+			t.Fatalf("n=%d: %v", n, err)
+		}
+
+		// With a single element there are no challenges to bind.
+		if err := proof.Verify(NewTranscript("other"), Q, P, G[:n], H[:n]); n > 1 && err == nil {
+			t.Fatalf("n=%d: proof accepted with a different transcript", n)
+		}
+		wrong := new(bn256.G1).Add(P, Q)
+		if err := proof.Verify(NewTranscript("test"), Q, wrong, G[:n], H[:n]); err == nil {
+			t.Fatalf("n=%d: proof accepted for a different commitment", n)
+		}
+	}
+}
+
+func TestInnerProductLength(t *testing.T) {
+	gens := NewGenerators(4, 1)
+	G, H := gens.share(4, 1)
+	Q := bn256.HashToG1([]byte("Q"), []byte("test"))
+
+	a, b := randomVector(t, 3), randomVector(t, 3)
+	if _, err := ProveInnerProduct(NewTranscript("test"), Q, G[:3], H[:3], a, b); err == nil {
+		t.Fatal("vectors whose length is not a power of two accepted")
+	}
+	if _, err := ProveInnerProduct(NewTranscript("test"), Q, G[:2], H[:2], a[:2], b[:1]); err == nil {
+		t.Fatal("vectors of different lengths accepted")
+	}
+
+	a, b = randomVector(t, 4), randomVector(t, 4)
+	proof, err := ProveInnerProduct(NewTranscript("test"), Q, G, H, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proof.Verify(NewTranscript("test"), Q, Q, G[:2], H[:2]); err == nil {
+		t.Fatal("proof accepted for vectors of the wrong length")
+	}
+}
@@ -0,0 +1,105 @@
+package bulletproofs
+
+import (
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+const (
+	pointSize  = 64
+	scalarSize = 32
+	headerSize = 4*pointSize + 3*scalarSize
+)
+
+// Marshal converts p into a byte slice. The encoding is A, S, T1, T2, τx, μ,
+// t̂, then each pair of L and R points of the inner-product argument, then
+// its final scalars a and b. Points use the encoding of bn256.G1.Marshal and
+// scalars are 32-byte big-endian numbers.
+func (p *RangeProof) Marshal() []byte {
+	ret := make([]byte, 0, headerSize+len(p.IPP.L)*2*pointSize+2*scalarSize)
+	for _, pt := range []*bn256.G1{p.A, p.S, p.T1, p.T2} {
+		ret = append(ret, pt.Marshal()...)
+	}
+	for _, s := range []*big.Int{p.TauX, p.Mu, p.THat} {
+		ret = append(ret, scalarBytes(s)...)
+	}
+	for i := range p.IPP.L {
+		ret = append(ret, p.IPP.L[i].Marshal()...)
+		ret = append(ret, p.IPP.R[i].Marshal()...)
+	}
+	ret = append(ret, scalarBytes(p.IPP.A)...)
+	return append(ret, scalarBytes(p.IPP.B)...)
+}
+
+// Unmarshal sets p to the result of converting the output of Marshal back
+// into a range proof. Unlike the Unmarshal methods of bn256, it consumes all
+// of m, since the length of a proof is not known in advance.
+func (p *RangeProof) Unmarshal(m []byte) error {
+	body := len(m) - headerSize - 2*scalarSize
+	if body < 0 || body%(2*pointSize) != 0 {
+		return errMalformed
+	}
+	k := body / (2 * pointSize)
+
+	readPoints := func(n int) ([]*bn256.G1, error) {
+		ret := make([]*bn256.G1, n)
+		for i := range ret {
+			ret[i] = new(bn256.G1)
+			var err error
+			if m, err = ret[i].Unmarshal(m); err != nil {
+				return nil, err
+			}
+		}
+		return ret, nil
+	}
+
+	head, err := readPoints(4)
+	if err != nil {
+		return err
+	}
+	s, rest, err := readScalars(m, 3)
+	if err != nil {
+		return err
+	}
+	m = rest
+	lr, err := readPoints(2 * k)
+	if err != nil {
+		return err
+	}
+	ab, _, err := readScalars(m, 2)
+	if err != nil {
+		return err
+	}
+
+	p.A, p.S, p.T1, p.T2 = head[0], head[1], head[2], head[3]
+	p.TauX, p.Mu, p.THat = s[0], s[1], s[2]
+	p.IPP = &InnerProductProof{
+		L: make([]*bn256.G1, k),
+		R: make([]*bn256.G1, k),
+		A: ab[0],
+		B: ab[1],
+	}
+	for i := 0; i < k; i++ {
+		p.IPP.L[i] = lr[2*i]
+		p.IPP.R[i] = lr[2*i+1]
+	}
+	return nil
+}
+
+// readScalars reads n canonical scalars from m and returns them and the rest
+// of m.
+func readScalars(m []byte, n int) ([]*big.Int, []byte, error) {
+	if len(m) < n*scalarSize {
+		return nil, nil, errMalformed
+	}
+	ret := make([]*big.Int, n)
+	for i := range ret {
+		ret[i] = new(big.Int).SetBytes(m[:scalarSize])
+		if ret[i].Cmp(bn256.Order) >= 0 {
+			return nil, nil, errMalformed
+		}
+		m = m[scalarSize:]
+	}
+	return ret, m, nil
+}
@@ -0,0 +1,343 @@
+// Package bulletproofs implements Bulletproofs range proofs over G₁.
+//
+// A range proof shows that a Pedersen commitment V = v·B + γ·B̃ hides a value
+// v in [0, 2ⁿ) without revealing anything else about v. Proofs for m values
+// can be aggregated into a single proof that is only logarithmically larger
+// than a proof for one value. Proofs are made non-interactive with a
+// Transcript, and the verifier checks all equations with a single
+// multi-scalar multiplication.
+//
+// See Bünz et al., "Bulletproofs: Short Proofs for Confidential Transactions
+// and More", https://eprint.iacr.org/2017/1066.
+package bulletproofs
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// RangeProof is a proof that one or more committed values lie in [0, 2ⁿ).
+type RangeProof struct {
+	A, S, T1, T2   *bn256.G1
+	TauX, Mu, THat *big.Int
+	IPP            *InnerProductProof
+}
+
+// ProveSingle returns a proof that v lies in [0, 2ⁿ) together with the
+// commitment v·B + blinding·B̃ that it refers to. n must be 8, 16, 32 or 64
+// and at most the capacity of gens. Randomness is read from r.
+func ProveSingle(gens *Generators, t *Transcript, v uint64, blinding *big.Int, n int, r io.Reader) (*RangeProof, *bn256.G1, error) {
+	proof, V, err := ProveMultiple(gens, t, []uint64{v}, []*big.Int{blinding}, n, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, V[0], nil
+}
+
+// ProveMultiple returns an aggregated proof that each of values lies in
+// [0, 2ⁿ) together with the commitments to the values under the matching
+// blinding factors. The number of values must be a power of two and at most
+// the number of parties of gens. Randomness is read from r.
+func ProveMultiple(gens *Generators, t *Transcript, values []uint64, blindings []*big.Int, n int, r io.Reader) (*RangeProof, []*bn256.G1, error) {
+	m := len(values)
+	if len(blindings) != m {
+		return nil, nil, errors.New("bulletproofs: mismatched number of values and blinding factors")
+	}
+	if err := checkSize(gens, n, m); err != nil {
+		return nil, nil, err
+	}
+	for _, v := range values {
+		if n < 64 && v>>uint(n) != 0 {
+			return nil, nil, errors.New("bulletproofs: value out of range")
+		}
+	}
+
+	V := make([]*bn256.G1, m)
+	for j := range V {
+		V[j] = gens.Commit(values[j], blindings[j])
+	}
+	beginTranscript(t, n, m, V)
+
+	G, H := gens.share(n, m)
+	nm := n * m
+
+	aL := make([]*big.Int, nm)
+	aR := make([]*big.Int, nm)
+	for j, v := range values {
+		for k := 0; k < n; k++ {
+			bit := int64(v >> uint(k) & 1)
+			aL[j*n+k] = big.NewInt(bit)
+			aR[j*n+k] = sub(big.NewInt(bit), big.NewInt(1))
+		}
+	}
+
+	alpha, err := randomScalar(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	rho, err := randomScalar(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	sL, err := randomScalars(r, nm)
+	if err != nil {
+		return nil, nil, err
+	}
+	sR, err := randomScalars(r, nm)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bases := concatPoints([]*bn256.G1{gens.BBlinding}, G, H)
+	A := new(bn256.G1).MultiScalarMult(bases, concatScalars([]*big.Int{alpha}, aL, aR))
+	S := new(bn256.G1).MultiScalarMult(bases, concatScalars([]*big.Int{rho}, sL, sR))
+
+	t.appendPoint("A", A)
+	t.appendPoint("S", S)
+	y := t.challengeScalar("y")
+	z := t.challengeScalar("z")
+
+	// l(X) = l0 + l1·X and r(X) = r0 + r1·X, where
+	//
+	//	l0 = aL - z
+	//	l1 = sL
+	//	r0 = yⁱ∘(aR + z) + z²⁺ʲ·2ᵏ
+	//	r1 = yⁱ∘sR
+	//
+	// for the i-th entry, which is bit k of party j.
+	yPow := powers(y, nm)
+	zPow := powers(z, m+2)
+	l0 := make([]*big.Int, nm)
+	r0 := make([]*big.Int, nm)
+	r1 := make([]*big.Int, nm)
+	for i := range l0 {
+		j, k := i/n, i%n
+		l0[i] = sub(aL[i], z)
+		r0[i] = add(mul(yPow[i], add(aR[i], z)), mul(zPow[2+j], new(big.Int).Lsh(big.NewInt(1), uint(k))))
+		r1[i] = mul(yPow[i], sR[i])
+	}
+
+	// t(X) = ⟨l(X), r(X)⟩ = t0 + t1·X + t2·X²
+	t1 := add(innerProduct(l0, r1), innerProduct(sL, r0))
+	t2 := innerProduct(sL, r1)
+
+	tau1, err := randomScalar(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	tau2, err := randomScalar(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	pedersen := []*bn256.G1{gens.B, gens.BBlinding}
+	T1 := new(bn256.G1).MultiScalarMult(pedersen, []*big.Int{t1, tau1})
+	T2 := new(bn256.G1).MultiScalarMult(pedersen, []*big.Int{t2, tau2})
+
+	t.appendPoint("T1", T1)
+	t.appendPoint("T2", T2)
+	x := t.challengeScalar("x")
+
+	tauX := add(mul(tau2, mul(x, x)), mul(tau1, x))
+	for j, gamma := range blindings {
+		tauX = add(tauX, mul(zPow[2+j], gamma))
+	}
+	mu := add(alpha, mul(rho, x))
+
+	l := make([]*big.Int, nm)
+	rv := make([]*big.Int, nm)
+	for i := range l {
+		l[i] = add(l0[i], mul(sL[i], x))
+		rv[i] = add(r0[i], mul(r1[i], x))
+	}
+	tHat := innerProduct(l, rv)
+
+	t.appendScalar("t_x_blinding", tauX)
+	t.appendScalar("e_blinding", mu)
+	t.appendScalar("t_x", tHat)
+	w := t.challengeScalar("w")
+	Q := new(bn256.G1).ScalarMult(gens.B, w)
+
+	// The inner-product argument runs over H'ᵢ = y⁻ⁱ·Hᵢ.
+	yInv := inv(y)
+	if yInv == nil {
+		return nil, nil, errors.New("bulletproofs: zero challenge")
+	}
+	yInvPow := powers(yInv, nm)
+	HPrime := make([]*bn256.G1, nm)
+	for i := range HPrime {
+		HPrime[i] = new(bn256.G1).ScalarMult(H[i], yInvPow[i])
+	}
+
+	ipp, err := ProveInnerProduct(t, Q, G, HPrime, l, rv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proof := &RangeProof{
+		A: A, S: S, T1: T1, T2: T2,
+		TauX: tauX, Mu: mu, THat: tHat,
+		IPP: ipp,
+	}
+	return proof, V, nil
+}
+
+// VerifySingle returns nil iff p proves that the value committed to by V lies
+// in [0, 2ⁿ). t must be in the same state as the transcript passed to the
+// prover was.
+func (p *RangeProof) VerifySingle(gens *Generators, t *Transcript, V *bn256.G1, n int) error {
+	return p.VerifyMultiple(gens, t, []*bn256.G1{V}, n)
+}
+
+// VerifyMultiple returns nil iff p proves that each of the values committed
+// to by V lies in [0, 2ⁿ). t must be in the same state as the transcript
+// passed to the prover was.
+func (p *RangeProof) VerifyMultiple(gens *Generators, t *Transcript, V []*bn256.G1, n int) error {
+	m := len(V)
+	if err := checkSize(gens, n, m); err != nil {
+		return err
+	}
+	if p.A == nil || p.S == nil || p.T1 == nil || p.T2 == nil ||
+		p.TauX == nil || p.Mu == nil || p.THat == nil || p.IPP == nil {
+		return errMalformed
+	}
+	beginTranscript(t, n, m, V)
+
+	G, H := gens.share(n, m)
+	nm := n * m
+
+	t.appendPoint("A", p.A)
+	t.appendPoint("S", p.S)
+	y := t.challengeScalar("y")
+	z := t.challengeScalar("z")
+	t.appendPoint("T1", p.T1)
+	t.appendPoint("T2", p.T2)
+	x := t.challengeScalar("x")
+	t.appendScalar("t_x_blinding", p.TauX)
+	t.appendScalar("e_blinding", p.Mu)
+	t.appendScalar("t_x", p.THat)
+	w := t.challengeScalar("w")
+
+	u2, uInv2, s, err := p.IPP.verificationScalars(t, nm)
+	if err != nil {
+		return err
+	}
+
+	yInv := inv(y)
+	if yInv == nil {
+		return errInvalid
+	}
+
+	// The two checks
+	//
+	//	t̂·B + τx·B̃ = Σ z²⁺ʲ·Vⱼ + δ(y, z)·B + x·T1 + x²·T2
+	//
+	// and the inner-product argument over G, H' and Q = w·B for the point
+	//
+	//	P = A + x·S - μ·B̃ - z·⟨1, G⟩ + ⟨z·yⁱ + z²⁺ʲ·2ᵏ, H'⟩
+	//
+	// are combined into one multi-scalar multiplication, the first being
+	// weighted by a random c.
+	c, err := randomScalar(rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	yPow := powers(y, nm)
+	yInvPow := powers(yInv, nm)
+	zPow := powers(z, m+3)
+	twoPow := powers(big.NewInt(2), n)
+
+	// δ(y, z) = (z - z²)·⟨1, yⁿᵐ⟩ - Σ z³⁺ʲ·⟨1, 2ⁿ⟩
+	sumY := new(big.Int)
+	for _, yi := range yPow {
+		sumY.Add(sumY, yi)
+	}
+	sumTwo := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(n)), big.NewInt(1))
+	delta := mul(sub(z, zPow[2]), sumY)
+	for j := 0; j < m; j++ {
+		delta = sub(delta, mul(zPow[3+j], sumTwo))
+	}
+
+	a, b := p.IPP.A, p.IPP.B
+	points := concatPoints(G, H,
+		[]*bn256.G1{gens.B, gens.BBlinding, p.A, p.S, p.T1, p.T2},
+		V, p.IPP.L, p.IPP.R)
+	scalars := make([]*big.Int, 0, len(points))
+	for i := 0; i < nm; i++ {
+		scalars = append(scalars, add(mul(a, s[i]), z))
+	}
+	for i := 0; i < nm; i++ {
+		j, k := i/n, i%n
+		hi := sub(mul(b, s[nm-1-i]), mul(zPow[2+j], twoPow[k]))
+		scalars = append(scalars, sub(mul(yInvPow[i], hi), z))
+	}
+	scalars = append(scalars,
+		add(mul(sub(mul(a, b), p.THat), w), mul(c, sub(p.THat, delta))),
+		add(p.Mu, mul(c, p.TauX)),
+		big.NewInt(-1),
+		new(big.Int).Neg(x),
+		new(big.Int).Neg(mul(c, x)),
+		new(big.Int).Neg(mul(c, mul(x, x))),
+	)
+	for j := 0; j < m; j++ {
+		scalars = append(scalars, new(big.Int).Neg(mul(c, zPow[2+j])))
+	}
+	for _, u := range u2 {
+		scalars = append(scalars, new(big.Int).Neg(u))
+	}
+	for _, u := range uInv2 {
+		scalars = append(scalars, new(big.Int).Neg(u))
+	}
+
+	if !new(bn256.G1).MultiScalarMult(points, scalars).IsIdentity() {
+		return errInvalid
+	}
+	return nil
+}
+
+// checkSize returns an error unless gens can be used for a proof of m values
+// of n bits each.
+func checkSize(gens *Generators, n, m int) error {
+	switch n {
+	case 8, 16, 32, 64:
+	default:
+		return errors.New("bulletproofs: invalid bit size")
+	}
+	if m == 0 || m&(m-1) != 0 {
+		return errors.New("bulletproofs: number of values is not a power of two")
+	}
+	if m > len(gens.G) || n > len(gens.G[0]) {
+		return errors.New("bulletproofs: not enough generators")
+	}
+	return nil
+}
+
+// beginTranscript writes the statement of a range proof to t.
+func beginTranscript(t *Transcript, n, m int, V []*bn256.G1) {
+	t.AppendMessage("dom-sep", []byte("rangeproof v1"))
+	t.appendUint64("n", uint64(n))
+	t.appendUint64("m", uint64(m))
+	for _, v := range V {
+		t.appendPoint("V", v)
+	}
+}
+
+func randomScalar(r io.Reader) (*big.Int, error) {
+	return rand.Int(r, bn256.Order)
+}
+
+func randomScalars(r io.Reader, n int) ([]*big.Int, error) {
+	ret := make([]*big.Int, n)
+	for i := range ret {
+		k, err := randomScalar(r)
+		if err != nil {
+			return nil, err
+		}
+		ret[i] = k
+	}
+	return ret, nil
+}
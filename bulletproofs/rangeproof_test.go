@@ -0,0 +1,212 @@
+package bulletproofs
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+func TestRangeProofSingle(t *testing.T) {
+	gens := NewGenerators(64, 1)
+
+	for _, tc := range []struct {
+		v uint64
+		n int
+	}{
+		{0, 8}, {255, 8}, {1 << 15, 16}, {math.MaxUint32, 32}, {math.MaxUint64, 64}, {12345, 64},
+	} {
+		blinding := randomVector(t, 1)[0]
+		proof, V, err := ProveSingle(gens, NewTranscript("test"), tc.v, blinding, tc.n, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(V.Marshal(), gens.Commit(tc.v, blinding).Marshal()) {
+			t.Fatalf("v=%d: wrong commitment", tc.v)
+		}
+		if err := proof.VerifySingle(gens, NewTranscript("test"), V, tc.n); err != nil {
+			t.Fatalf("v=%d, n=%d: %v", tc.v, tc.n, err)
+		}
+
+		if err := proof.VerifySingle(gens, NewTranscript("other"), V, tc.n); err == nil {
+			t.Fatalf("v=%d: proof accepted with a different transcript", tc.v)
+		}
+		other := gens.Commit(tc.v+1, blinding)
+		if err := proof.VerifySingle(gens, NewTranscript("test"), other, tc.n); err == nil {
+			t.Fatalf("v=%d: proof accepted for a different commitment", tc.v)
+		}
+	}
+}
+
+func TestRangeProofOutOfRange(t *testing.T) {
+	gens := NewGenerators(64, 1)
+	blinding := randomVector(t, 1)[0]
+
+	if _, _, err := ProveSingle(gens, NewTranscript("test"), 256, blinding, 8, rand.Reader); err == nil {
+		t.Fatal("proved value out of range")
+	}
+	if _, _, err := ProveSingle(gens, NewTranscript("test"), 1, blinding, 12, rand.Reader); err == nil {
+		t.Fatal("accepted invalid bit size")
+	}
+
+	// A proof for 16 bits does not verify as a proof for 8 bits.
+	proof, V, err := ProveSingle(gens, NewTranscript("test"), 1000, blinding, 16, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proof.VerifySingle(gens, NewTranscript("test"), V, 8); err == nil {
+		t.Fatal("proof accepted for a smaller range")
+	}
+}
+
+// TestRangeProofForged checks that a prover who ignores the range check on a
+// value that does not fit in n bits produces a proof that does not verify.
+func TestRangeProofForged(t *testing.T) {
+	gens := NewGenerators(8, 1)
+	blinding := randomVector(t, 1)[0]
+
+	proof, _, err := ProveSingle(gens, NewTranscript("test"), 200, blinding, 8, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The proof for 200 claims to be about 200+256, which is congruent in the
+	// bits the proof covers but outside the range.
+	V := gens.Commit(200+256, blinding)
+	if err := proof.VerifySingle(gens, NewTranscript("test"), V, 8); err == nil {
+		t.Fatal("proof accepted for a value out of range")
+	}
+}
+
+func TestRangeProofMultiple(t *testing.T) {
+	gens := NewGenerators(32, 4)
+
+	for _, m := range []int{1, 2, 4} {
+		values := make([]uint64, m)
+		for j := range values {
+			values[j] = uint64(j) * 1000003
+		}
+		blindings := randomVector(t, m)
+
+		proof, V, err := ProveMultiple(gens, NewTranscript("test"), values, blindings, 32, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := proof.VerifyMultiple(gens, NewTranscript("test"), V, 32); err != nil {
+			t.Fatalf("m=%d: %v", m, err)
+		}
+
+		if m > 1 {
+			V[0], V[1] = V[1], V[0]
+			if err := proof.VerifyMultiple(gens, NewTranscript("test"), V, 32); err == nil {
+				t.Fatalf("m=%d: proof accepted with commitments reordered", m)
+			}
+		}
+	}
+
+	if _, _, err := ProveMultiple(gens, NewTranscript("test"), make([]uint64, 3), randomVector(t, 3), 32, rand.Reader); err == nil {
+		t.Fatal("accepted a number of values that is not a power of two")
+	}
+	if _, _, err := ProveMultiple(gens, NewTranscript("test"), make([]uint64, 8), randomVector(t, 8), 32, rand.Reader); err == nil {
+		t.Fatal("accepted more values than parties")
+	}
+	if _, _, err := ProveMultiple(gens, NewTranscript("test"), make([]uint64, 2), randomVector(t, 2), 64, rand.Reader); err == nil {
+		t.Fatal("accepted more bits than the capacity")
+	}
+}
+
+func TestRangeProofTampered(t *testing.T) {
+	gens := NewGenerators(16, 2)
+	proof, V, err := ProveMultiple(gens, NewTranscript("test"), []uint64{7, 65535}, randomVector(t, 2), 16, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	one := big.NewInt(1)
+
+	tamper := []func(p *RangeProof){
+		func(p *RangeProof) { p.A = new(bn256.G1).Add(p.A, gens.B) },
+		func(p *RangeProof) { p.S = new(bn256.G1).Add(p.S, gens.B) },
+		func(p *RangeProof) { p.T1 = new(bn256.G1).Add(p.T1, gens.B) },
+		func(p *RangeProof) { p.T2 = new(bn256.G1).Add(p.T2, gens.B) },
+		func(p *RangeProof) { p.TauX = add(p.TauX, one) },
+		func(p *RangeProof) { p.Mu = add(p.Mu, one) },
+		func(p *RangeProof) { p.THat = add(p.THat, one) },
+		func(p *RangeProof) { p.IPP.L[0] = new(bn256.G1).Add(p.IPP.L[0], gens.B) },
+		func(p *RangeProof) { p.IPP.R[2] = new(bn256.G1).Add(p.IPP.R[2], gens.B) },
+		func(p *RangeProof) { p.IPP.A = add(p.IPP.A, one) },
+		func(p *RangeProof) { p.IPP.B = add(p.IPP.B, one) },
+		func(p *RangeProof) { p.IPP.L, p.IPP.R = p.IPP.L[1:], p.IPP.R[1:] },
+	}
+	for i, f := range tamper {
+		p := new(RangeProof)
+		if err := p.Unmarshal(proof.Marshal()); err != nil {
+			t.Fatal(err)
+		}
+		f(p)
+		if err := p.VerifyMultiple(gens, NewTranscript("test"), V, 16); err == nil {
+			t.Errorf("tampered proof %d accepted", i)
+		}
+	}
+}
+
+func TestRangeProofMarshal(t *testing.T) {
+	gens := NewGenerators(64, 2)
+	proof, V, err := ProveMultiple(gens, NewTranscript("test"), []uint64{1, 2}, randomVector(t, 2), 64, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := proof.Marshal()
+	// log₂(128) = 7 rounds of the inner-product argument.
+	if want := 4*64 + 3*32 + 7*2*64 + 2*32; len(m) != want {
+		t.Fatalf("proof is %d bytes, want %d", len(m), want)
+	}
+
+	p := new(RangeProof)
+	if err := p.Unmarshal(m); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(p.Marshal(), m) {
+		t.Fatal("proof changed after round trip")
+	}
+	if err := p.VerifyMultiple(gens, NewTranscript("test"), V, 64); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := new(RangeProof).Unmarshal(m[:len(m)-1]); err == nil {
+		t.Fatal("truncated proof accepted")
+	}
+	bad := append([]byte(nil), m...)
+	bad[10] ^= 1
+	if err := new(RangeProof).Unmarshal(bad); err == nil {
+		t.Fatal("proof with a point off the curve accepted")
+	}
+	bad = append([]byte(nil), m...)
+	copy(bad[4*64:], bytes.Repeat([]byte{0xff}, 32))
+	if err := new(RangeProof).Unmarshal(bad); err == nil {
+		t.Fatal("proof with a non-canonical scalar accepted")
+	}
+}
+
+func BenchmarkProveSingle64(b *testing.B) {
+	gens := NewGenerators(64, 1)
+	blinding, _ := rand.Int(rand.Reader, bn256.Order)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ProveSingle(gens, NewTranscript("bench"), 1234567, blinding, 64, rand.Reader)
+	}
+}
+
+func BenchmarkVerifySingle64(b *testing.B) {
+	gens := NewGenerators(64, 1)
+	blinding, _ := rand.Int(rand.Reader, bn256.Order)
+	proof, V, _ := ProveSingle(gens, NewTranscript("bench"), 1234567, blinding, 64, rand.Reader)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		proof.VerifySingle(gens, NewTranscript("bench"), V, 64)
+	}
+}
@@ -0,0 +1,55 @@
+package bulletproofs
+
+import (
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// scalarBytes returns the 32-byte big-endian encoding of s mod Order.
+func scalarBytes(s *big.Int) []byte {
+	k := new(big.Int).Mod(s, bn256.Order)
+	b := k.Bytes()
+	ret := make([]byte, 32)
+	copy(ret[32-len(b):], b)
+	return ret
+}
+
+func add(a, b *big.Int) *big.Int {
+	c := new(big.Int).Add(a, b)
+	return c.Mod(c, bn256.Order)
+}
+
+func sub(a, b *big.Int) *big.Int {
+	c := new(big.Int).Sub(a, b)
+	return c.Mod(c, bn256.Order)
+}
+
+func mul(a, b *big.Int) *big.Int {
+	c := new(big.Int).Mul(a, b)
+	return c.Mod(c, bn256.Order)
+}
+
+func inv(a *big.Int) *big.Int {
+	return new(big.Int).ModInverse(a, bn256.Order)
+}
+
+// powers returns 1, x, x², …, xⁿ⁻¹.
+func powers(x *big.Int, n int) []*big.Int {
+	ret := make([]*big.Int, n)
+	acc := big.NewInt(1)
+	for i := range ret {
+		ret[i] = acc
+		acc = mul(acc, x)
+	}
+	return ret
+}
+
+// innerProduct returns Σ a[i]·b[i].
+func innerProduct(a, b []*big.Int) *big.Int {
+	sum, t := new(big.Int), new(big.Int)
+	for i := range a {
+		sum.Add(sum, t.Mul(a[i], b[i]))
+	}
+	return sum.Mod(sum, bn256.Order)
+}
@@ -0,0 +1,79 @@
+package bulletproofs
+
+import (
+	"crypto/sha256"
+	"hash"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// Transcript is a Fiat-Shamir transcript. The prover and verifier feed it the
+// same sequence of labelled messages, so the challenges it derives bind every
+// message that came before them. Callers may append their own messages before
+// proving or verifying to bind a proof to its context.
+//
+// The state is a SHA-256 chaining value: each message replaces it with the
+// hash of the old state and the length-prefixed label and message.
+type Transcript struct {
+	state [sha256.Size]byte
+}
+
+// NewTranscript returns a transcript for the protocol identified by label.
+func NewTranscript(label string) *Transcript {
+	t := &Transcript{}
+	t.AppendMessage("dom-sep", []byte(label))
+	return t
+}
+
+// AppendMessage adds msg to the transcript under label.
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	h := sha256.New()
+	h.Write(t.state[:])
+	writeBytes(h, []byte(label))
+	writeBytes(h, msg)
+	h.Sum(t.state[:0])
+}
+
+func (t *Transcript) appendUint64(label string, x uint64) {
+	var buf [8]byte
+	for i := range buf {
+		buf[i] = byte(x >> uint(56-8*i))
+	}
+	t.AppendMessage(label, buf[:])
+}
+
+func (t *Transcript) appendPoint(label string, p *bn256.G1) {
+	t.AppendMessage(label, p.Marshal())
+}
+
+func (t *Transcript) appendScalar(label string, s *big.Int) {
+	t.AppendMessage(label, scalarBytes(s))
+}
+
+// challengeScalar returns a challenge derived from the transcript. 64 bytes
+// of output are reduced modulo Order so that the result is close to uniform.
+// The challenge is then appended to the transcript.
+func (t *Transcript) challengeScalar(label string) *big.Int {
+	buf := make([]byte, 0, 2*sha256.Size)
+	for i := byte(0); i < 2; i++ {
+		h := sha256.New()
+		h.Write(t.state[:])
+		writeBytes(h, []byte("challenge"))
+		writeBytes(h, []byte(label))
+		h.Write([]byte{i})
+		buf = h.Sum(buf)
+	}
+
+	c := new(big.Int).SetBytes(buf)
+	c.Mod(c, bn256.Order)
+	t.appendScalar(label, c)
+	return c
+}
+
+// writeBytes writes b to h, preceded by its length.
+func writeBytes(h hash.Hash, b []byte) {
+	n := uint32(len(b))
+	h.Write([]byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)})
+	h.Write(b)
+}
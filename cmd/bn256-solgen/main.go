@@ -0,0 +1,64 @@
+// Command bn256-solgen generates a Solidity contract verifying Groth16 proofs
+// under a verifying key.
+//
+// Usage:
+//
+//	bn256-solgen [-name Verifier] [-license UNLICENSED] [-o Verifier.sol] key
+//
+// The key file holds a verifying key in the encoding of
+// solgen.VerifyingKey.Marshal. The contract is written to standard output
+// unless -o is given.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/clearmatics/bn256/solgen"
+)
+
+func main() {
+	name := flag.String("name", "Verifier", "name of the generated contract")
+	license := flag.String("license", "UNLICENSED", "SPDX license identifier of the generated file")
+	output := flag.String("o", "", "write the contract to `file` instead of standard output")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: bn256-solgen [flags] key\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *output, &solgen.Options{ContractName: *name, License: *license}); err != nil {
+		fmt.Fprintln(os.Stderr, "bn256-solgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(keyFile, output string, opts *solgen.Options) error {
+	data, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return err
+	}
+	vk := new(solgen.VerifyingKey)
+	if err := vk.Unmarshal(data); err != nil {
+		return err
+	}
+
+	if output == "" {
+		return solgen.Generate(os.Stdout, vk, opts)
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := solgen.Generate(f, vk, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
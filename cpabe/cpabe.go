@@ -0,0 +1,326 @@
+// Package cpabe implements Waters' ciphertext-policy attribute-based
+// encryption.
+//
+// An authority issues users private keys for sets of attributes, and data is
+// encrypted to a policy over attributes, such as "(dept:eng and
+// level:senior) or auditor", so that it can be decrypted with any key whose
+// attribute set satisfies the policy. Keys of different users cannot be
+// combined to satisfy a policy that neither satisfies alone.
+//
+// Policies are trees of AND, OR and threshold gates, which are converted to
+// monotone span programs. With master secrets α and a, a key for the set S is
+//
+//	K = (α + a·t)·g₂, L = t·g₂, Kₓ = t·H(x) for x ∈ S
+//
+// where H hashes attributes to G₁, so that any string can be an attribute.
+// A ciphertext for a span program (M, ρ) with shares λᵢ of s is
+//
+//	C' = s·g₁, Cᵢ = λᵢ·a·g₁ - rᵢ·H(ρ(i)), Dᵢ = rᵢ·g₂
+//
+// and the message is encrypted with a key derived from e(g₁, g₂)^(α·s).
+// Decryption computes it with coefficients ωᵢ such that Σ ωᵢ·λᵢ = s as
+//
+//	e(C', K) · e(-Σ ωᵢ·Cᵢ, L) · Π e(-ωᵢ·K_ρ(i), Dᵢ)
+//
+// multiplying the results of Miller's algorithm and applying a single final
+// exponentiation. The message itself is encrypted with AES-256-GCM.
+//
+// See Waters, "Ciphertext-Policy Attribute-Based Encryption: An Expressive,
+// Efficient, and Provably Secure Realization", https://eprint.iacr.org/2008/290,
+// of which this is the random oracle variant for large attribute universes,
+// adapted to asymmetric pairings.
+package cpabe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// Domain separation tags.
+var (
+	attributeDomain = []byte("bn256/cpabe/attribute")
+	keyLabel        = []byte("bn256/cpabe/key")
+)
+
+var (
+	errMalformed   = errors.New("cpabe: malformed ciphertext")
+	errUnsatisfied = errors.New("cpabe: attributes do not satisfy the policy")
+	errDecrypt     = errors.New("cpabe: decryption failed")
+)
+
+// PublicKey is the public key of an authority.
+type PublicKey struct {
+	G1A      *bn256.G1 // a·g₁
+	G2A      *bn256.G2 // a·g₂
+	EggAlpha *bn256.GT // e(g₁, g₂)^α
+}
+
+// MasterKey is the secret key of an authority.
+type MasterKey struct {
+	G2Alpha *bn256.G2 // α·g₂
+}
+
+// Setup returns the keys of a new authority using randomness from r.
+func Setup(r io.Reader) (*PublicKey, *MasterKey, error) {
+	alpha, err := rand.Int(r, bn256.Order)
+	if err != nil {
+		return nil, nil, err
+	}
+	a, err := rand.Int(r, bn256.Order)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	g1 := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	mk := &MasterKey{new(bn256.G2).ScalarBaseMult(alpha)}
+	pk := &PublicKey{
+		G1A:      new(bn256.G1).ScalarBaseMult(a),
+		G2A:      new(bn256.G2).ScalarBaseMult(a),
+		EggAlpha: bn256.Pair(g1, mk.G2Alpha),
+	}
+	return pk, mk, nil
+}
+
+// PrivateKey is the private key for a set of attributes.
+type PrivateKey struct {
+	K, L *bn256.G2
+	KX   map[string]*bn256.G1
+}
+
+// KeyGen returns the private key for attributes under the authority keys pk
+// and mk, using randomness from r.
+func KeyGen(r io.Reader, pk *PublicKey, mk *MasterKey, attributes []string) (*PrivateKey, error) {
+	t, err := rand.Int(r, bn256.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	sk := &PrivateKey{
+		K:  new(bn256.G2).ScalarMult(pk.G2A, t),
+		L:  new(bn256.G2).ScalarBaseMult(t),
+		KX: make(map[string]*bn256.G1, len(attributes)),
+	}
+	sk.K.Add(sk.K, mk.G2Alpha)
+	for _, x := range attributes {
+		sk.KX[x] = new(bn256.G1).ScalarMult(hashAttribute(x), t)
+	}
+	return sk, nil
+}
+
+// Attributes returns the set of attributes of sk.
+func (sk *PrivateKey) Attributes() map[string]bool {
+	ret := make(map[string]bool, len(sk.KX))
+	for x := range sk.KX {
+		ret[x] = true
+	}
+	return ret
+}
+
+// Ciphertext is a message encrypted to a policy.
+type Ciphertext struct {
+	Policy  *Policy
+	C0      *bn256.G1   // C'
+	C       []*bn256.G1 // one per leaf of Policy
+	D       []*bn256.G2 // one per leaf of Policy
+	Payload []byte
+}
+
+// Encrypt encrypts msg to policy under pk using randomness from r.
+func Encrypt(r io.Reader, pk *PublicKey, policy *Policy, msg []byte) (*Ciphertext, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	m := newMSP(policy)
+
+	ks, err := randomScalars(r, m.cols+len(m.rows))
+	if err != nil {
+		return nil, err
+	}
+	s, ys, rs := ks[0], ks[1:m.cols], ks[m.cols:]
+	lambda := m.shares(s, ys)
+
+	ct := &Ciphertext{
+		Policy: policy,
+		C0:     new(bn256.G1).ScalarBaseMult(s),
+		C:      make([]*bn256.G1, len(m.rows)),
+		D:      make([]*bn256.G2, len(m.rows)),
+	}
+	for i, x := range m.attrs {
+		ct.C[i] = new(bn256.G1).MultiScalarMult(
+			[]*bn256.G1{pk.G1A, hashAttribute(x)},
+			[]*big.Int{lambda[i], new(big.Int).Sub(bn256.Order, rs[i])},
+		)
+		ct.D[i] = new(bn256.G2).ScalarBaseMult(rs[i])
+	}
+
+	aead, err := newAEAD(new(bn256.GT).ScalarMult(pk.EggAlpha, s))
+	if err != nil {
+		return nil, err
+	}
+	ct.Payload = aead.Seal(nil, make([]byte, aead.NonceSize()), msg, ct.header())
+	return ct, nil
+}
+
+// Decrypt decrypts ct with sk, whose attributes must satisfy the policy of
+// ct.
+func Decrypt(sk *PrivateKey, ct *Ciphertext) ([]byte, error) {
+	if ct.Policy == nil || ct.C0 == nil || ct.Policy.Validate() != nil {
+		return nil, errMalformed
+	}
+	m := newMSP(ct.Policy)
+	if len(ct.C) != len(m.rows) || len(ct.D) != len(m.rows) {
+		return nil, errMalformed
+	}
+	omega := coefficients(ct.Policy, sk.Attributes())
+	if omega == nil {
+		return nil, errUnsatisfied
+	}
+
+	var cs []*bn256.G1
+	var ws []*big.Int
+	acc := bn256.Miller(ct.C0, sk.K)
+	for i, w := range omega {
+		if w == nil {
+			continue
+		}
+		if ct.C[i] == nil || ct.D[i] == nil {
+			return nil, errMalformed
+		}
+		cs = append(cs, ct.C[i])
+		ws = append(ws, w)
+
+		kx := new(bn256.G1).ScalarMult(sk.KX[m.attrs[i]], new(big.Int).Sub(bn256.Order, w))
+		mulMiller(acc, kx, ct.D[i])
+	}
+	c := new(bn256.G1).MultiScalarMult(cs, ws)
+	mulMiller(acc, c.Neg(c), sk.L)
+
+	aead, err := newAEAD(acc.Finalize())
+	if err != nil {
+		return nil, err
+	}
+	msg, err := aead.Open(nil, make([]byte, aead.NonceSize()), ct.Payload, ct.header())
+	if err != nil {
+		return nil, errDecrypt
+	}
+	return msg, nil
+}
+
+// mulMiller multiplies acc by the result of Miller's algorithm on a and b,
+// unless either is the identity, for which the pairing is one.
+func mulMiller(acc *bn256.GT, a *bn256.G1, b *bn256.G2) {
+	if isIdentity(a.Marshal()) || isIdentity(b.Marshal()) {
+		return
+	}
+	acc.Add(acc, bn256.Miller(a, b))
+}
+
+// newAEAD returns the cipher keyed with a key derived from k. Each key is
+// used for a single message, so a fixed nonce is safe.
+func newAEAD(k *bn256.GT) (cipher.AEAD, error) {
+	h := sha256.New()
+	h.Write([]byte{byte(len(keyLabel))})
+	h.Write(keyLabel)
+	h.Write(k.Marshal())
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func hashAttribute(x string) *bn256.G1 {
+	return bn256.HashToG1([]byte(x), attributeDomain)
+}
+
+// header returns the encoding of everything in ct but the payload, which is
+// authenticated with it.
+func (ct *Ciphertext) header() []byte {
+	policy := []byte(ct.Policy.String())
+	ret := appendUint32(nil, uint32(len(policy)))
+	ret = append(ret, policy...)
+	ret = append(ret, ct.C0.Marshal()...)
+	for i := range ct.C {
+		ret = append(ret, ct.C[i].Marshal()...)
+		ret = append(ret, ct.D[i].Marshal()...)
+	}
+	return ret
+}
+
+// Marshal converts ct into a byte slice: the policy, as a length-prefixed
+// string, C', the pairs (Cᵢ, Dᵢ) and the payload.
+func (ct *Ciphertext) Marshal() []byte {
+	return append(ct.header(), ct.Payload...)
+}
+
+// Unmarshal sets ct to the result of converting the output of Marshal back
+// into a ciphertext. It consumes all of m.
+func (ct *Ciphertext) Unmarshal(m []byte) error {
+	if len(m) < 4 {
+		return errMalformed
+	}
+	n := uint32(m[0])<<24 | uint32(m[1])<<16 | uint32(m[2])<<8 | uint32(m[3])
+	m = m[4:]
+	if uint32(len(m)) < n {
+		return errMalformed
+	}
+	policy, err := ParsePolicy(string(m[:n]))
+	if err != nil {
+		return err
+	}
+	m = m[n:]
+
+	C0 := new(bn256.G1)
+	if m, err = C0.Unmarshal(m); err != nil {
+		return err
+	}
+	leaves := policy.leaves()
+	C, D := make([]*bn256.G1, leaves), make([]*bn256.G2, leaves)
+	for i := range C {
+		C[i], D[i] = new(bn256.G1), new(bn256.G2)
+		if m, err = C[i].Unmarshal(m); err != nil {
+			return err
+		}
+		if m, err = D[i].Unmarshal(m); err != nil {
+			return err
+		}
+	}
+
+	ct.Policy, ct.C0, ct.C, ct.D = policy, C0, C, D
+	ct.Payload = append([]byte(nil), m...)
+	return nil
+}
+
+// randomScalars returns n scalars read from r for Encrypt: the secret s, the
+// other entries of the share vector and the per-row randomness. Zero is not
+// excluded.
+func randomScalars(r io.Reader, n int) (ks []*big.Int, err error) {
+	ks = make([]*big.Int, n)
+	for i := range ks {
+		if ks[i], err = rand.Int(r, bn256.Order); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+func appendUint32(b []byte, x uint32) []byte {
+	return append(b, byte(x>>24), byte(x>>16), byte(x>>8), byte(x))
+}
+
+// isIdentity returns true iff m is the encoding of the identity.
+func isIdentity(m []byte) bool {
+	for _, b := range m {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
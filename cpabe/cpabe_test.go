@@ -0,0 +1,137 @@
+package cpabe
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+func setup(t *testing.T) (*PublicKey, *MasterKey) {
+	t.Helper()
+
+	pk, mk, err := Setup(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pk, mk
+}
+
+func keyGen(t *testing.T, pk *PublicKey, mk *MasterKey, attrs ...string) *PrivateKey {
+	t.Helper()
+
+	sk, err := KeyGen(rand.Reader, pk, mk, attrs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sk
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	pk, mk := setup(t)
+	msg := []byte("quarterly figures")
+
+	for _, tc := range []struct {
+		policy string
+		attrs  []string
+		ok     bool
+	}{
+		{"a", []string{"a"}, true},
+		{"a", []string{"b"}, false},
+		{"a and b", []string{"a", "b"}, true},
+		{"a and b", []string{"a", "c"}, false},
+		{"a or b", []string{"b"}, true},
+		{"a or b", nil, false},
+		{"(dept:eng and level:senior) or auditor", []string{"dept:eng", "level:senior"}, true},
+		{"(dept:eng and level:senior) or auditor", []string{"auditor"}, true},
+		{"(dept:eng and level:senior) or auditor", []string{"dept:eng", "level:junior"}, false},
+		{"2 of (a, b, c)", []string{"c", "a"}, true},
+		{"2 of (a, b, c)", []string{"b"}, false},
+		{"3 of (a, b and c, d or e, f)", []string{"a", "b", "c", "e"}, true},
+		{"3 of (a, b and c, d or e, f)", []string{"a", "b", "e"}, false},
+		{"a and (a or b)", []string{"a"}, true},
+	} {
+		policy, err := ParsePolicy(tc.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ct, err := Encrypt(rand.Reader, pk, policy, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sk := keyGen(t, pk, mk, tc.attrs...)
+
+		got, err := Decrypt(sk, ct)
+		if tc.ok && (err != nil || !bytes.Equal(got, msg)) {
+			t.Errorf("%q with %v: decryption failed: %v", tc.policy, tc.attrs, err)
+		}
+		if !tc.ok && err == nil {
+			t.Errorf("%q with %v: decryption succeeded", tc.policy, tc.attrs)
+		}
+	}
+}
+
+// TestCollusion checks that users cannot combine their keys to satisfy a
+// policy that neither satisfies alone.
+func TestCollusion(t *testing.T) {
+	pk, mk := setup(t)
+	ct, _ := Encrypt(rand.Reader, pk, And(Attr("a"), Attr("b")), []byte("secret"))
+	alice := keyGen(t, pk, mk, "a")
+	bob := keyGen(t, pk, mk, "b")
+
+	combined := &PrivateKey{K: alice.K, L: alice.L, KX: map[string]*bn256.G1{
+		"a": alice.KX["a"],
+		"b": bob.KX["b"],
+	}}
+	if _, err := Decrypt(combined, ct); err == nil {
+		t.Fatal("combined keys decrypted")
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	pk, mk := setup(t)
+	sk := keyGen(t, pk, mk, "board member", "legal")
+	policy := Or(And(Attr("dept:eng"), Attr("level:senior")), Threshold(2, Attr("audit"), Attr("legal"), Attr("board member")))
+	msg := []byte("minutes")
+	ct, _ := Encrypt(rand.Reader, pk, policy, msg)
+
+	decoded := new(Ciphertext)
+	if err := decoded.Unmarshal(ct.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Decrypt(sk, decoded); err != nil || !bytes.Equal(got, msg) {
+		t.Fatal("decryption failed after round trip")
+	}
+
+	// The policy is authenticated with the payload.
+	decoded.Policy = Threshold(2, Attr("audit"), Attr("legal"), Attr("board member"))
+	decoded.C, decoded.D = decoded.C[2:], decoded.D[2:]
+	if _, err := Decrypt(sk, decoded); err == nil {
+		t.Fatal("ciphertext with a changed policy decrypted")
+	}
+
+	m := ct.Marshal()
+	m[len(m)-1] ^= 1
+	if err := decoded.Unmarshal(m); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decrypt(sk, decoded); err == nil {
+		t.Fatal("tampered payload decrypted")
+	}
+	if err := decoded.Unmarshal(m[:100]); err == nil {
+		t.Fatal("truncated ciphertext accepted")
+	}
+}
+
+func BenchmarkDecrypt(b *testing.B) {
+	pk, mk, _ := Setup(rand.Reader)
+	sk, _ := KeyGen(rand.Reader, pk, mk, []string{"a", "b", "c", "d"})
+	policy, _ := ParsePolicy("a and b and 2 of (c, d, e)")
+	ct, _ := Encrypt(rand.Reader, pk, policy, []byte("message"))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Decrypt(sk, ct)
+	}
+}
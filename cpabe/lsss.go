@@ -0,0 +1,140 @@
+package cpabe
+
+import (
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// msp is a monotone span program, the linear secret sharing scheme of a
+// policy: row i of the matrix is labelled with attrs[i], and the sets of
+// attributes whose rows span (1, 0, …, 0) are those that satisfy the policy.
+type msp struct {
+	rows  [][]*big.Int
+	attrs []string
+	cols  int
+}
+
+// newMSP returns the span program of p, which must be valid. Each leaf of p
+// gives a row, in depth-first order.
+//
+// The root is labelled with (1). A gate with threshold k whose label v has
+// been built when the matrix had c columns adds k-1 columns and labels its
+// child i with
+//
+//	v ‖ 0 … 0 ‖ i ‖ i² ‖ … ‖ iᵏ⁻¹
+//
+// so that Lagrange interpolation at zero over any k children gives back v,
+// as in Shamir's scheme. OR gates copy v to their children.
+func newMSP(p *Policy) *msp {
+	m := &msp{cols: 1}
+	m.add(p, []*big.Int{big.NewInt(1)})
+	for i, row := range m.rows {
+		m.rows[i] = pad(row, m.cols)
+	}
+	return m
+}
+
+func (m *msp) add(p *Policy, v []*big.Int) {
+	if p.isLeaf() {
+		m.rows = append(m.rows, v)
+		m.attrs = append(m.attrs, p.Attribute)
+		return
+	}
+
+	c, k := m.cols, p.Threshold
+	m.cols += k - 1
+	for i, child := range p.Children {
+		x := big.NewInt(int64(i + 1))
+		w := pad(v, c)
+		for j := 1; j < k; j++ {
+			w = append(w, new(big.Int).Exp(x, big.NewInt(int64(j)), nil))
+		}
+		m.add(child, w)
+	}
+}
+
+// shares returns the shares M·(s, y₂, …, y_cols) of s, where ys holds the
+// y values.
+func (m *msp) shares(s *big.Int, ys []*big.Int) []*big.Int {
+	v := append([]*big.Int{s}, ys...)
+	ret := make([]*big.Int, len(m.rows))
+	for i, row := range m.rows {
+		sum := new(big.Int)
+		for j, a := range row {
+			sum.Add(sum, new(big.Int).Mul(a, v[j]))
+		}
+		ret[i] = sum.Mod(sum, bn256.Order)
+	}
+	return ret
+}
+
+// coefficients returns ω such that Σ ωᵢ·Mᵢ = (1, 0, …, 0) over the rows of
+// the span program of p, with ωᵢ = nil for rows that are not used. Only rows
+// labelled with attributes in attrs are used. It returns nil if attrs does
+// not satisfy p.
+func coefficients(p *Policy, attrs map[string]bool) []*big.Int {
+	if !p.Satisfied(attrs) {
+		return nil
+	}
+	w := make([]*big.Int, p.leaves())
+	next := 0
+	assign(p, attrs, big.NewInt(1), w, &next)
+	return w
+}
+
+// assign sets the coefficients of the leaves of p, which start at w[*next],
+// given the coefficient of p, which is nil if p is not used.
+func assign(p *Policy, attrs map[string]bool, coeff *big.Int, w []*big.Int, next *int) {
+	if p.isLeaf() {
+		w[*next] = coeff
+		*next++
+		return
+	}
+
+	// Interpolate over the first k satisfied children.
+	var chosen []int64
+	if coeff != nil {
+		for i, c := range p.Children {
+			if len(chosen) < p.Threshold && c.Satisfied(attrs) {
+				chosen = append(chosen, int64(i+1))
+			}
+		}
+	}
+	for i, c := range p.Children {
+		var cc *big.Int
+		for _, x := range chosen {
+			if x == int64(i+1) {
+				cc = new(big.Int).Mul(coeff, lagrangeAtZero(chosen, x))
+				cc.Mod(cc, bn256.Order)
+			}
+		}
+		assign(c, attrs, cc, w, next)
+	}
+}
+
+// lagrangeAtZero returns the Lagrange coefficient of x at zero over xs,
+// which must contain x.
+func lagrangeAtZero(xs []int64, x int64) *big.Int {
+	num, den := big.NewInt(1), big.NewInt(1)
+	for _, y := range xs {
+		if y == x {
+			continue
+		}
+		num.Mul(num, big.NewInt(y))
+		den.Mul(den, big.NewInt(y-x))
+	}
+	den.Mod(den, bn256.Order)
+	num.Mul(num, den.ModInverse(den, bn256.Order))
+	return num.Mod(num, bn256.Order)
+}
+
+// pad returns a copy of v extended with zeros to n entries.
+func pad(v []*big.Int, n int) []*big.Int {
+	ret := make([]*big.Int, n)
+	copy(ret, v)
+	for i := len(v); i < n; i++ {
+		ret[i] = new(big.Int)
+	}
+	return ret
+}
@@ -0,0 +1,299 @@
+package cpabe
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var errPolicy = errors.New("cpabe: invalid policy")
+
+// Policy is a monotone access policy: a tree of threshold gates over
+// attributes. A leaf is satisfied by a set of attributes that contains
+// Attribute, and a gate by a set that satisfies at least Threshold of its
+// children, so that AND and OR gates are the thresholds n and 1 of n.
+type Policy struct {
+	Attribute string
+	Threshold int
+	Children  []*Policy
+}
+
+// Attr returns the policy that is satisfied by sets containing attribute.
+func Attr(attribute string) *Policy {
+	return &Policy{Attribute: attribute}
+}
+
+// And returns the policy that is satisfied by sets satisfying all children.
+func And(children ...*Policy) *Policy {
+	return &Policy{Threshold: len(children), Children: children}
+}
+
+// Or returns the policy that is satisfied by sets satisfying any child.
+func Or(children ...*Policy) *Policy {
+	return &Policy{Threshold: 1, Children: children}
+}
+
+// Threshold returns the policy that is satisfied by sets satisfying at least
+// k of children.
+func Threshold(k int, children ...*Policy) *Policy {
+	return &Policy{Threshold: k, Children: children}
+}
+
+func (p *Policy) isLeaf() bool {
+	return len(p.Children) == 0
+}
+
+// Validate returns nil iff p is well formed: every leaf has a non-empty
+// attribute and every gate a threshold between 1 and its number of children.
+func (p *Policy) Validate() error {
+	if p.isLeaf() {
+		if p.Attribute == "" {
+			return errPolicy
+		}
+		return nil
+	}
+	if p.Attribute != "" || p.Threshold < 1 || p.Threshold > len(p.Children) {
+		return errPolicy
+	}
+	for _, c := range p.Children {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Satisfied returns true iff the set attrs satisfies p.
+func (p *Policy) Satisfied(attrs map[string]bool) bool {
+	if p.isLeaf() {
+		return attrs[p.Attribute]
+	}
+	n := 0
+	for _, c := range p.Children {
+		if c.Satisfied(attrs) {
+			n++
+		}
+	}
+	return n >= p.Threshold
+}
+
+// leaves returns the number of leaves of p.
+func (p *Policy) leaves() int {
+	if p.isLeaf() {
+		return 1
+	}
+	n := 0
+	for _, c := range p.Children {
+		n += c.leaves()
+	}
+	return n
+}
+
+// String returns p in the syntax accepted by ParsePolicy.
+func (p *Policy) String() string {
+	if p.isLeaf() {
+		if isPlainAttribute(p.Attribute) {
+			return p.Attribute
+		}
+		return strconv.Quote(p.Attribute)
+	}
+
+	parts := make([]string, len(p.Children))
+	for i, c := range p.Children {
+		parts[i] = c.String()
+	}
+	switch {
+	case len(p.Children) == 1:
+		return fmt.Sprintf("1 of (%s)", parts[0])
+	case p.Threshold == len(p.Children):
+		return "(" + strings.Join(parts, " and ") + ")"
+	case p.Threshold == 1:
+		return "(" + strings.Join(parts, " or ") + ")"
+	}
+	return fmt.Sprintf("%d of (%s)", p.Threshold, strings.Join(parts, ", "))
+}
+
+// ParsePolicy parses a policy such as
+//
+//	(dept:eng and level:senior) or 2 of (audit, legal, "board member")
+//
+// "and" binds tighter than "or", and "k of (p₁, …, pₙ)" is a threshold gate.
+// Keywords are case-insensitive. Attributes are runs of letters, digits and
+// any of _-.:=@/, or double-quoted Go strings.
+func ParsePolicy(s string) (*Policy, error) {
+	ps := &parser{s: s}
+	p, err := ps.or()
+	if err != nil {
+		return nil, err
+	}
+	if tok := ps.next(); tok != "" {
+		return nil, fmt.Errorf("cpabe: unexpected %q in policy", tok)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+type parser struct {
+	s    string
+	peek string
+}
+
+func (ps *parser) or() (*Policy, error) {
+	return ps.gate("or", ps.and, Or)
+}
+
+func (ps *parser) and() (*Policy, error) {
+	return ps.gate("and", ps.factor, And)
+}
+
+// gate parses operands separated by the keyword op and joins them with join
+// if there is more than one.
+func (ps *parser) gate(op string, operand func() (*Policy, error), join func(...*Policy) *Policy) (*Policy, error) {
+	p, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	children := []*Policy{p}
+	for strings.EqualFold(ps.lookahead(), op) {
+		ps.next()
+		if p, err = operand(); err != nil {
+			return nil, err
+		}
+		children = append(children, p)
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return join(children...), nil
+}
+
+func (ps *parser) factor() (*Policy, error) {
+	tok := ps.next()
+	switch {
+	case tok == "":
+		return nil, errors.New("cpabe: unexpected end of policy")
+	case tok == "(":
+		p, err := ps.or()
+		if err != nil {
+			return nil, err
+		}
+		if ps.next() != ")" {
+			return nil, errors.New("cpabe: missing ) in policy")
+		}
+		return p, nil
+	case tok == ")" || tok == ",":
+		return nil, fmt.Errorf("cpabe: unexpected %q in policy", tok)
+	case tok[0] == '"':
+		attr, err := strconv.Unquote(tok)
+		if err != nil {
+			return nil, fmt.Errorf("cpabe: invalid attribute %s in policy", tok)
+		}
+		return Attr(attr), nil
+	case !isPlainAttribute(tok):
+		return nil, fmt.Errorf("cpabe: unexpected %q in policy", tok)
+	}
+
+	if !strings.EqualFold(ps.lookahead(), "of") {
+		return Attr(tok), nil
+	}
+	k, err := strconv.Atoi(tok)
+	if err != nil {
+		return nil, fmt.Errorf("cpabe: invalid threshold %q in policy", tok)
+	}
+	ps.next()
+	if ps.next() != "(" {
+		return nil, errors.New("cpabe: missing ( in policy")
+	}
+	var children []*Policy
+	for {
+		p, err := ps.or()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, p)
+		switch ps.next() {
+		case ",":
+		case ")":
+			return Threshold(k, children...), nil
+		default:
+			return nil, errors.New("cpabe: missing ) in policy")
+		}
+	}
+}
+
+// lookahead returns the next token without consuming it.
+func (ps *parser) lookahead() string {
+	if ps.peek == "" {
+		ps.peek = ps.scan()
+	}
+	return ps.peek
+}
+
+// next consumes and returns the next token, or "" at the end of the input.
+func (ps *parser) next() string {
+	tok := ps.lookahead()
+	ps.peek = ""
+	return tok
+}
+
+func (ps *parser) scan() string {
+	ps.s = strings.TrimLeftFunc(ps.s, unicode.IsSpace)
+	if ps.s == "" {
+		return ""
+	}
+
+	n := 1
+	switch c := ps.s[0]; {
+	case c == '(' || c == ')' || c == ',':
+	case c == '"':
+		// Find the closing quote, skipping escaped characters.
+		for n < len(ps.s) && ps.s[n] != '"' {
+			if ps.s[n] == '\\' {
+				n++
+			}
+			n++
+		}
+		if n < len(ps.s) {
+			n++
+		}
+	default:
+		n = strings.IndexFunc(ps.s, func(r rune) bool { return !isAttributeRune(r) })
+		if n < 0 {
+			n = len(ps.s)
+		} else if n == 0 {
+			// An invalid character: return it so the caller reports it.
+			n = 1
+		}
+	}
+	if n > len(ps.s) {
+		n = len(ps.s)
+	}
+	tok := ps.s[:n]
+	ps.s = ps.s[n:]
+	return tok
+}
+
+func isAttributeRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.:=@/", r)
+}
+
+// isPlainAttribute returns true iff attr can be written without quotes.
+func isPlainAttribute(attr string) bool {
+	if attr == "" {
+		return false
+	}
+	for _, r := range attr {
+		if !isAttributeRune(r) {
+			return false
+		}
+	}
+	switch strings.ToLower(attr) {
+	case "and", "or", "of":
+		return false
+	}
+	return true
+}
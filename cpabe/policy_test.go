@@ -0,0 +1,111 @@
+package cpabe
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+func TestParsePolicy(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want *Policy
+	}{
+		{"a", Attr("a")},
+		{"a and b or c", Or(And(Attr("a"), Attr("b")), Attr("c"))},
+		{"a AND (b Or c)", And(Attr("a"), Or(Attr("b"), Attr("c")))},
+		{"a and b and c", And(Attr("a"), Attr("b"), Attr("c"))},
+		{"2 of (a, b and c, d)", Threshold(2, Attr("a"), And(Attr("b"), Attr("c")), Attr("d"))},
+		{`"board member" or user@example.com`, Or(Attr("board member"), Attr("user@example.com"))},
+		{`"and" and 2`, And(Attr("and"), Attr("2"))},
+		{"((a))", Attr("a")},
+	} {
+		got, err := ParsePolicy(tc.in)
+		if err != nil {
+			t.Errorf("%q: %v", tc.in, err)
+			continue
+		}
+		if got.String() != tc.want.String() {
+			t.Errorf("%q: got %s, want %s", tc.in, got, tc.want)
+		}
+
+		// String gives back the same tree.
+		again, err := ParsePolicy(got.String())
+		if err != nil || !equalPolicy(again, got) {
+			t.Errorf("%q: %s does not parse back", tc.in, got)
+		}
+	}
+
+	for _, in := range []string{
+		"", "a and", "or b", "(a", "a)", "a b", "3 of (a, b)", "0 of (a)",
+		"x of (a, b)", "2 of a, b", "2 of (a, b", `"unterminated`, "a & b", "and",
+	} {
+		if _, err := ParsePolicy(in); err == nil {
+			t.Errorf("%q accepted", in)
+		}
+	}
+}
+
+func equalPolicy(a, b *Policy) bool {
+	if a.Attribute != b.Attribute || len(a.Children) != len(b.Children) {
+		return false
+	}
+	if !a.isLeaf() && a.Threshold != b.Threshold {
+		return false
+	}
+	for i := range a.Children {
+		if !equalPolicy(a.Children[i], b.Children[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// TestMSP checks that the coefficients recombine the shares of the span
+// program for every subset of the attributes of a policy.
+func TestMSP(t *testing.T) {
+	policy, _ := ParsePolicy("(a and b) or 3 of (c, d or e, 2 of (a, f, g), h)")
+	attrs := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	m := newMSP(policy)
+	if len(m.rows) != policy.leaves() {
+		t.Fatalf("%d rows for %d leaves", len(m.rows), policy.leaves())
+	}
+
+	s := big.NewInt(12345)
+	ys := make([]*big.Int, m.cols-1)
+	for i := range ys {
+		ys[i] = big.NewInt(int64(1000 + i))
+	}
+	lambda := m.shares(s, ys)
+
+	for mask := 0; mask < 1<<uint(len(attrs)); mask++ {
+		set := make(map[string]bool)
+		for i, x := range attrs {
+			if mask>>uint(i)&1 == 1 {
+				set[x] = true
+			}
+		}
+
+		omega := coefficients(policy, set)
+		if (omega != nil) != policy.Satisfied(set) {
+			t.Fatalf("%v: coefficients do not match satisfaction", set)
+		}
+		if omega == nil {
+			continue
+		}
+		sum := new(big.Int)
+		for i, w := range omega {
+			if w == nil {
+				continue
+			}
+			if !set[m.attrs[i]] {
+				t.Fatalf("%v: row %d of attribute %s used", set, i, m.attrs[i])
+			}
+			sum.Add(sum, new(big.Int).Mul(w, lambda[i]))
+		}
+		if sum.Mod(sum, bn256.Order).Cmp(s) != 0 {
+			t.Fatalf("%v: shares recombined to %v", set, sum)
+		}
+	}
+}
@@ -21,10 +21,10 @@ var curveGen = &curvePoint{
 }
 
 func (c *curvePoint) String() string {
-	c.MakeAffine()
+	a := c.affine()
 	x, y := &gfP{}, &gfP{}
-	montDecode(x, &c.x)
-	montDecode(y, &c.y)
+	montDecode(x, &a.x)
+	montDecode(y, &a.y)
 	return "(" + x.String() + ", " + y.String() + ")"
 }
 
@@ -35,18 +35,22 @@ func (c *curvePoint) Set(a *curvePoint) {
 	c.t.Set(&a.t)
 }
 
-// IsOnCurve returns true iff c is on the curve.
+// IsOnCurve returns true iff c is on the curve. It checks y²=x³+3z⁶ on the
+// Jacobian coordinates, so c is neither normalized nor modified.
 func (c *curvePoint) IsOnCurve() bool {
-	c.MakeAffine()
 	if c.IsInfinity() {
 		return true
 	}
 
-	y2, x3 := &gfP{}, &gfP{}
+	y2, x3, z6 := &gfP{}, &gfP{}, &gfP{}
 	gfpMul(y2, &c.y, &c.y)
 	gfpMul(x3, &c.x, &c.x)
 	gfpMul(x3, x3, &c.x)
-	gfpAdd(x3, x3, curveB)
+	gfpMul(z6, &c.z, &c.z)
+	gfpMul(z6, z6, &c.z)
+	gfpMul(z6, z6, z6)
+	gfpMul(z6, z6, curveB)
+	gfpAdd(x3, x3, z6)
 
 	return *y2 == *x3
 }
@@ -151,12 +155,18 @@ func (c *curvePoint) Add(a, b *curvePoint) {
 
 func (c *curvePoint) Double(a *curvePoint) {
 	// See http://hyperelliptic.org/EFD/g1p/auto-code/shortw/jacobian-0/doubling/dbl-2009-l.op3
+
+	// Compute z first, as a.y is overwritten below when c aliases a.
+	t, t2 := &gfP{}, &gfP{}
+	gfpMul(t, &a.y, &a.z)
+	z := &gfP{}
+	gfpAdd(z, t, t)
+
 	A, B, C := &gfP{}, &gfP{}, &gfP{}
 	gfpMul(A, &a.x, &a.x)
 	gfpMul(B, &a.y, &a.y)
 	gfpMul(C, B, B)
 
-	t, t2 := &gfP{}, &gfP{}
 	gfpAdd(t, &a.x, B)
 	gfpMul(t2, t, t)
 	gfpSub(t, t2, A)
@@ -178,8 +188,7 @@ func (c *curvePoint) Double(a *curvePoint) {
 	gfpMul(t2, e, &c.y)
 	gfpSub(&c.y, t2, t)
 
-	gfpMul(t, &a.y, &a.z)
-	gfpAdd(&c.z, t, t)
+	c.z.Set(z)
 }
 
 func (c *curvePoint) Mul(a *curvePoint, scalar *big.Int) {
@@ -206,6 +215,15 @@ func (c *curvePoint) Mul(a *curvePoint, scalar *big.Int) {
 	c.Set(sum)
 }
 
+// affine returns a copy of c in affine coordinates, leaving c unchanged so
+// that it may be shared between goroutines.
+func (c *curvePoint) affine() *curvePoint {
+	a := &curvePoint{}
+	a.Set(c)
+	a.MakeAffine()
+	return a
+}
+
 func (c *curvePoint) MakeAffine() {
 	if c.z == *newGFp(1) {
 		return
@@ -218,6 +236,16 @@ func (c *curvePoint) MakeAffine() {
 
 	zInv := &gfP{}
 	zInv.Invert(&c.z)
+	c.makeAffineWith(zInv)
+}
+
+// makeAffineWith converts c to affine coordinates given zInv = 1/c.z, which
+// lets the inversion be shared between many points.
+func (c *curvePoint) makeAffineWith(zInv *gfP) {
+	if c.IsInfinity() {
+		c.SetInfinity()
+		return
+	}
 
 	t, zInv2 := &gfP{}, &gfP{}
 	gfpMul(t, &c.y, zInv)
@@ -0,0 +1,426 @@
+// Package dkg implements the Joint-Feldman distributed key generation protocol
+// of Pedersen, with the complaint handling of Gennaro et al.
+//
+// n participants, indexed 1 to n, jointly generate a secret key that is shared
+// among them with threshold t, without any of them learning it. Each
+// participant acts as a dealer of a random secret with Feldman verifiable
+// secret sharing; the joint key is the sum of the secrets of the dealers that
+// were not disqualified.
+//
+// The protocol is a state machine that runs in four phases:
+//
+//  1. Deal: each dealer sends a share of its secret and commitments to its
+//     sharing polynomial to each participant.
+//  2. Response: each participant checks the deals it received and broadcasts
+//     a response for every dealer, which is a complaint if the deal was
+//     missing or invalid.
+//  3. Justification: each dealer that received a complaint broadcasts the
+//     share of the complainer, so that everyone can check it.
+//  4. Finalize: dealers whose commitments were inconsistent, or that did not
+//     justify a complaint with a valid share, are disqualified, and each
+//     participant adds up the shares of the remaining dealers.
+//
+// The public key is not uniformly random. Joint-Feldman lets the dealers
+// see each other's commitments to their secrets before the set of qualified
+// dealers is fixed, so a dealer controlled by the adversary can, for
+// instance, decide whether to be disqualified after seeing the key that would
+// result either way. Gennaro et al. show that this lets the adversary bias
+// the public key, and close the gap with a further extraction phase based on
+// Pedersen commitments, which this package does not implement. The secret
+// key still stays unknown to any t-1 participants, and in "Secure
+// Applications of Pedersen's Distributed Key Generation Protocol" the same
+// authors show that some schemes, such as threshold Schnorr signatures,
+// remain secure with a biased key. Callers must check that this holds for
+// their scheme, and must not use the key where uniformity matters, such as a
+// source of shared randomness.
+//
+// The package does no networking. The caller carries messages between
+// participants, and must deliver deals over private, authenticated channels
+// and responses and justifications over an authenticated, reliable broadcast
+// channel, so that all honest participants finalize with the same messages.
+//
+// See Gennaro, Jarecki, Krawczyk and Rabin, "Secure Distributed Key
+// Generation for Discrete-Log Based Cryptosystems",
+// https://link.springer.com/article/10.1007/s00145-006-0347-3.
+package dkg
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/tbls"
+)
+
+var (
+	errConfig = errors.New("dkg: invalid configuration")
+	errPhase  = errors.New("dkg: message for the wrong phase")
+	errQual   = errors.New("dkg: too few qualified dealers")
+)
+
+// Config describes a run of the protocol.
+type Config struct {
+	// Group is the group that commitments and the public key live in.
+	Group Group
+	// N is the number of participants and T the number of shares needed to
+	// use the key.
+	N, T int
+}
+
+// Deal is a message from a dealer to one participant.
+type Deal struct {
+	Dealer, Recipient int
+	Commitments       []Point
+	Share             *big.Int
+}
+
+// Response is the verdict of a participant on the deal it received from a
+// dealer. If Complaint is false, Digest identifies the commitments the
+// participant received.
+type Response struct {
+	Dealer, Verifier int
+	Complaint        bool
+	Digest           []byte
+}
+
+// Justification answers a complaint by revealing the share of the complainer
+// together with the dealer's commitments.
+type Justification struct {
+	Dealer, Recipient int
+	Commitments       []Point
+	Share             *big.Int
+}
+
+// Result is the outcome of the protocol for one participant.
+type Result struct {
+	// Share is the participant's share of the joint secret key.
+	Share *tbls.Share
+	// Commitments are the commitments to the joint sharing polynomial, the
+	// first of which is the joint public key.
+	Commitments []Point
+	// Qualified lists the dealers whose secrets make up the key.
+	Qualified []int
+}
+
+// PublicKey returns the joint public key.
+func (res *Result) PublicKey() Point {
+	return res.Commitments[0]
+}
+
+type phase int
+
+const (
+	dealPhase phase = iota
+	responsePhase
+	justificationPhase
+	finalizePhase
+	donePhase
+)
+
+// Participant is the state of one participant in the protocol.
+type Participant struct {
+	cfg   *Config
+	index int
+	phase phase
+
+	// coeffs is the sharing polynomial of this participant as a dealer.
+	coeffs []*big.Int
+
+	// deals holds the valid deal received from each dealer.
+	deals map[int]*Deal
+	// responses holds the responses about each dealer.
+	responses map[int][]*Response
+}
+
+// NewParticipant returns the participant with the given index, which is from 1
+// to cfg.N, and chooses its secret using randomness from r.
+func NewParticipant(cfg *Config, index int, r io.Reader) (*Participant, error) {
+	if cfg.Group == nil || cfg.T < 1 || cfg.T > cfg.N || index < 1 || index > cfg.N {
+		return nil, errConfig
+	}
+
+	coeffs := make([]*big.Int, cfg.T)
+	for k := range coeffs {
+		a, err := rand.Int(r, bn256.Order)
+		if err != nil {
+			return nil, err
+		}
+		coeffs[k] = a
+	}
+
+	return &Participant{
+		cfg:       cfg,
+		index:     index,
+		coeffs:    coeffs,
+		deals:     make(map[int]*Deal),
+		responses: make(map[int][]*Response),
+	}, nil
+}
+
+// Index returns the index of p.
+func (p *Participant) Index() int {
+	return p.index
+}
+
+// Deals returns the deals of p for each participant, including itself.
+func (p *Participant) Deals() ([]*Deal, error) {
+	if p.phase != dealPhase {
+		return nil, errPhase
+	}
+	p.phase = responsePhase
+
+	commits := p.commitments()
+	deals := make([]*Deal, p.cfg.N)
+	for j := range deals {
+		deals[j] = &Deal{
+			Dealer:      p.index,
+			Recipient:   j + 1,
+			Commitments: commits,
+			Share:       evalPoly(p.coeffs, j+1),
+		}
+	}
+	return deals, nil
+}
+
+// Respond processes the deals received by p and returns its responses, one for
+// each dealer. Deals addressed to other participants are ignored. p complains
+// about every dealer whose deal is missing or invalid.
+func (p *Participant) Respond(deals []*Deal) ([]*Response, error) {
+	if p.phase != responsePhase {
+		return nil, errPhase
+	}
+	p.phase = justificationPhase
+
+	for _, d := range deals {
+		if d.Recipient != p.index || d.Dealer < 1 || d.Dealer > p.cfg.N {
+			continue
+		}
+		if _, ok := p.deals[d.Dealer]; ok {
+			// A second deal from the same dealer is an attempt to
+			// equivocate; complain about both.
+			p.deals[d.Dealer] = nil
+			continue
+		}
+		if p.verifyShare(d.Commitments, p.index, d.Share) {
+			p.deals[d.Dealer] = d
+		} else {
+			p.deals[d.Dealer] = nil
+		}
+	}
+
+	responses := make([]*Response, p.cfg.N)
+	for i := range responses {
+		dealer := i + 1
+		resp := &Response{Dealer: dealer, Verifier: p.index}
+		if d := p.deals[dealer]; d != nil {
+			resp.Digest = digest(d.Commitments)
+		} else {
+			resp.Complaint = true
+		}
+		responses[i] = resp
+	}
+	return responses, nil
+}
+
+// Justify records the responses of all participants and returns the
+// justifications of p for the complaints about it.
+func (p *Participant) Justify(responses []*Response) ([]*Justification, error) {
+	if p.phase != justificationPhase {
+		return nil, errPhase
+	}
+	p.phase = finalizePhase
+
+	// Keep one response per dealer and verifier.
+	seen := make(map[[2]int]bool)
+	for _, r := range responses {
+		key := [2]int{r.Dealer, r.Verifier}
+		if r.Dealer < 1 || r.Dealer > p.cfg.N || r.Verifier < 1 || r.Verifier > p.cfg.N || seen[key] {
+			continue
+		}
+		seen[key] = true
+		p.responses[r.Dealer] = append(p.responses[r.Dealer], r)
+	}
+
+	var justifications []*Justification
+	var commits []Point
+	for _, r := range p.responses[p.index] {
+		if !r.Complaint {
+			continue
+		}
+		if commits == nil {
+			commits = p.commitments()
+		}
+		justifications = append(justifications, &Justification{
+			Dealer:      p.index,
+			Recipient:   r.Verifier,
+			Commitments: commits,
+			Share:       evalPoly(p.coeffs, r.Verifier),
+		})
+	}
+	return justifications, nil
+}
+
+// Finalize processes the justifications of all dealers and returns the result
+// of the protocol for p.
+func (p *Participant) Finalize(justifications []*Justification) (*Result, error) {
+	if p.phase != finalizePhase {
+		return nil, errPhase
+	}
+	p.phase = donePhase
+
+	byDealer := make(map[int][]*Justification)
+	for _, j := range justifications {
+		byDealer[j.Dealer] = append(byDealer[j.Dealer], j)
+	}
+
+	res := &Result{Share: &tbls.Share{Index: p.index, Value: new(big.Int)}}
+	for dealer := 1; dealer <= p.cfg.N; dealer++ {
+		commits, share, ok := p.qualify(dealer, byDealer[dealer])
+		if !ok || len(commits) != p.cfg.T || !p.wellFormed(commits) {
+			continue
+		}
+		res.Qualified = append(res.Qualified, dealer)
+
+		res.Share.Value.Add(res.Share.Value, share)
+		res.Share.Value.Mod(res.Share.Value, bn256.Order)
+		if res.Commitments == nil {
+			res.Commitments = commits
+			continue
+		}
+		sum := make([]Point, len(commits))
+		for k := range commits {
+			sum[k] = res.Commitments[k].Add(commits[k])
+		}
+		res.Commitments = sum
+	}
+
+	if len(res.Qualified) < p.cfg.T {
+		return nil, errQual
+	}
+	return res, nil
+}
+
+// qualify decides whether dealer is qualified given the responses about it and
+// its justifications. If it is, qualify returns its commitments and the share
+// of p.
+func (p *Participant) qualify(dealer int, justifications []*Justification) ([]Point, *big.Int, bool) {
+	// All approvals must be of the same commitments.
+	var want []byte
+	for _, r := range p.responses[dealer] {
+		if r.Complaint {
+			continue
+		}
+		if want == nil {
+			want = r.Digest
+		} else if !bytes.Equal(want, r.Digest) {
+			return nil, nil, false
+		}
+	}
+
+	var commits []Point
+	var share *big.Int
+	if d := p.deals[dealer]; d != nil {
+		commits, share = d.Commitments, d.Share
+	}
+
+	// Every complaint must be answered by a valid share for the same
+	// commitments.
+	for _, r := range p.responses[dealer] {
+		if !r.Complaint {
+			continue
+		}
+		var just *Justification
+		for _, j := range justifications {
+			if j.Recipient == r.Verifier {
+				just = j
+				break
+			}
+		}
+		if just == nil || !p.verifyShare(just.Commitments, r.Verifier, just.Share) {
+			return nil, nil, false
+		}
+		d := digest(just.Commitments)
+		if want == nil {
+			want = d
+		} else if !bytes.Equal(want, d) {
+			return nil, nil, false
+		}
+		if r.Verifier == p.index {
+			commits, share = just.Commitments, just.Share
+		}
+	}
+
+	if !p.wellFormed(commits) || !bytes.Equal(want, digest(commits)) {
+		return nil, nil, false
+	}
+	return commits, share, true
+}
+
+// commitments returns the Feldman commitments to the sharing polynomial of p.
+func (p *Participant) commitments() []Point {
+	ret := make([]Point, len(p.coeffs))
+	for k, a := range p.coeffs {
+		ret[k] = p.cfg.Group.ScalarBaseMult(a)
+	}
+	return ret
+}
+
+// verifyShare returns true iff share is the value at index of the polynomial
+// committed to by commits.
+func (p *Participant) verifyShare(commits []Point, index int, share *big.Int) bool {
+	if len(commits) != p.cfg.T || share == nil || share.Sign() < 0 || share.Cmp(bn256.Order) >= 0 {
+		return false
+	}
+	if !p.wellFormed(commits) {
+		return false
+	}
+
+	// Σ indexᵏ·Cₖ, by Horner's method.
+	x := big.NewInt(int64(index))
+	want := commits[len(commits)-1]
+	for k := len(commits) - 2; k >= 0; k-- {
+		want = want.ScalarMult(x).Add(commits[k])
+	}
+	got := p.cfg.Group.ScalarBaseMult(share)
+	return bytes.Equal(got.Marshal(), want.Marshal())
+}
+
+// wellFormed returns true iff commits is non-empty and each of its elements is
+// a well-formed point of the configured group, so that its methods can be
+// called safely.
+func (p *Participant) wellFormed(commits []Point) bool {
+	if len(commits) == 0 {
+		return false
+	}
+	for _, c := range commits {
+		if !p.cfg.Group.Contains(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// evalPoly returns the value of the polynomial with the given coefficients at
+// x.
+func evalPoly(coeffs []*big.Int, x int) *big.Int {
+	ret, bx := new(big.Int), big.NewInt(int64(x))
+	for k := len(coeffs) - 1; k >= 0; k-- {
+		ret.Mul(ret, bx)
+		ret.Add(ret, coeffs[k])
+		ret.Mod(ret, bn256.Order)
+	}
+	return ret
+}
+
+// digest returns a hash identifying a list of commitments.
+func digest(commits []Point) []byte {
+	h := sha256.New()
+	for _, c := range commits {
+		h.Write(c.Marshal())
+	}
+	return h.Sum(nil)
+}
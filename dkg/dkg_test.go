@@ -0,0 +1,391 @@
+package dkg
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/tbls"
+)
+
+// network runs the protocol between participants, passing every message
+// through the tampering functions if they are set.
+type network struct {
+	deals          func([]*Deal) []*Deal
+	justifications func([]*Justification) []*Justification
+}
+
+func (net *network) run(t *testing.T, cfg *Config) []*Result {
+	t.Helper()
+
+	ps := make([]*Participant, cfg.N)
+	for i := range ps {
+		p, err := NewParticipant(cfg, i+1, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ps[i] = p
+	}
+
+	var deals []*Deal
+	for _, p := range ps {
+		d, err := p.Deals()
+		if err != nil {
+			t.Fatal(err)
+		}
+		deals = append(deals, d...)
+	}
+	if net.deals != nil {
+		deals = net.deals(deals)
+	}
+
+	var responses []*Response
+	for _, p := range ps {
+		r, err := p.Respond(deals)
+		if err != nil {
+			t.Fatal(err)
+		}
+		responses = append(responses, r...)
+	}
+
+	var justifications []*Justification
+	for _, p := range ps {
+		j, err := p.Justify(responses)
+		if err != nil {
+			t.Fatal(err)
+		}
+		justifications = append(justifications, j...)
+	}
+	if net.justifications != nil {
+		justifications = net.justifications(justifications)
+	}
+
+	results := make([]*Result, cfg.N)
+	for i, p := range ps {
+		res, err := p.Finalize(justifications)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results[i] = res
+	}
+	return results
+}
+
+// checkResults checks that the results of the honest participants agree, that
+// their shares are consistent with the public key and that the qualified
+// dealers are as expected.
+func checkResults(t *testing.T, cfg *Config, results []*Result, honest []int, qualified []int) {
+	t.Helper()
+
+	first := results[honest[0]-1]
+	if !reflect.DeepEqual(first.Qualified, qualified) {
+		t.Fatalf("qualified dealers are %v, want %v", first.Qualified, qualified)
+	}
+	for _, i := range honest {
+		res := results[i-1]
+		if !reflect.DeepEqual(res.Qualified, first.Qualified) {
+			t.Fatalf("participants %d and %d disagree on qualified dealers", i, honest[0])
+		}
+		if len(res.Commitments) != cfg.T {
+			t.Fatalf("participant %d has %d commitments", i, len(res.Commitments))
+		}
+		for k := range res.Commitments {
+			if !bytes.Equal(res.Commitments[k].Marshal(), first.Commitments[k].Marshal()) {
+				t.Fatalf("participants %d and %d disagree on commitments", i, honest[0])
+			}
+		}
+	}
+
+	// Any t honest shares recover the secret key of the public key.
+	shares := make([]*tbls.Share, 0, len(honest))
+	for _, i := range honest {
+		shares = append(shares, results[i-1].Share)
+	}
+	for _, set := range [][]*tbls.Share{shares[:cfg.T], shares[len(shares)-cfg.T:]} {
+		secret, err := tbls.Recover(set, cfg.T)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(cfg.Group.ScalarBaseMult(secret).Marshal(), first.PublicKey().Marshal()) {
+			t.Fatal("shares do not match the public key")
+		}
+	}
+}
+
+func TestHonest(t *testing.T) {
+	for _, g := range []Group{G1Group, G2Group} {
+		cfg := &Config{Group: g, N: 5, T: 3}
+		results := new(network).run(t, cfg)
+		checkResults(t, cfg, results, []int{1, 2, 3, 4, 5}, []int{1, 2, 3, 4, 5})
+	}
+}
+
+// TestThresholdSignature runs the protocol in G₂ and uses the result as a
+// threshold BLS key.
+func TestThresholdSignature(t *testing.T) {
+	cfg := &Config{Group: G2Group, N: 4, T: 3}
+	results := new(network).run(t, cfg)
+
+	commits := make(tbls.Commitments, cfg.T)
+	for k, c := range results[0].Commitments {
+		commits[k] = c.(*G2Point).P
+	}
+
+	msg := []byte("hello")
+	var partials []*tbls.PartialSignature
+	for _, res := range results[1:] {
+		if !commits.VerifyShare(res.Share) {
+			t.Fatalf("share %d does not match commitments", res.Share.Index)
+		}
+		p := tbls.Sign(res.Share, msg)
+		if !tbls.VerifyPartial(commits, p, msg) {
+			t.Fatalf("partial signature %d rejected", p.Index)
+		}
+		partials = append(partials, p)
+	}
+
+	sig, err := tbls.Combine(partials, cfg.T)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tbls.Verify(commits.PublicKey(), msg, sig) {
+		t.Fatal("signature rejected")
+	}
+}
+
+// TestBadShareJustified checks that a dealer that sends a bad share but then
+// justifies it stays qualified, and that the complainer gets a good share.
+func TestBadShareJustified(t *testing.T) {
+	cfg := &Config{Group: G1Group, N: 5, T: 3}
+	net := &network{
+		deals: func(deals []*Deal) []*Deal {
+			for _, d := range deals {
+				if d.Dealer == 2 && d.Recipient == 4 {
+					d.Share = new(big.Int).Add(d.Share, big.NewInt(1))
+				}
+			}
+			return deals
+		},
+	}
+	results := net.run(t, cfg)
+	checkResults(t, cfg, results, []int{1, 2, 3, 4, 5}, []int{1, 2, 3, 4, 5})
+}
+
+// TestBadShareUnjustified checks that a dealer that sends a bad share and does
+// not justify it is disqualified.
+func TestBadShareUnjustified(t *testing.T) {
+	cfg := &Config{Group: G1Group, N: 5, T: 3}
+	net := &network{
+		deals: func(deals []*Deal) []*Deal {
+			for _, d := range deals {
+				if d.Dealer == 3 && d.Recipient == 1 {
+					d.Share = new(big.Int).Add(d.Share, big.NewInt(1))
+				}
+			}
+			return deals
+		},
+		justifications: func(js []*Justification) []*Justification {
+			var ret []*Justification
+			for _, j := range js {
+				if j.Dealer != 3 {
+					ret = append(ret, j)
+				}
+			}
+			return ret
+		},
+	}
+	results := net.run(t, cfg)
+	checkResults(t, cfg, results, []int{1, 2, 4, 5}, []int{1, 2, 4, 5})
+}
+
+// TestMissingDealBadJustification checks that a dealer that withholds a deal
+// and then justifies with a wrong share is disqualified.
+func TestMissingDealBadJustification(t *testing.T) {
+	cfg := &Config{Group: G2Group, N: 4, T: 2}
+	net := &network{
+		deals: func(deals []*Deal) []*Deal {
+			var ret []*Deal
+			for _, d := range deals {
+				if d.Dealer != 4 || d.Recipient != 2 {
+					ret = append(ret, d)
+				}
+			}
+			return ret
+		},
+		justifications: func(js []*Justification) []*Justification {
+			for _, j := range js {
+				if j.Dealer == 4 {
+					j.Share = new(big.Int).Add(j.Share, big.NewInt(1))
+				}
+			}
+			return js
+		},
+	}
+	results := net.run(t, cfg)
+	checkResults(t, cfg, results, []int{1, 2, 3}, []int{1, 2, 3})
+}
+
+// TestMalformedCommitments checks that a dealer whose commitments are not
+// well-formed points of the group is disqualified, rather than crashing the
+// other participants.
+func TestMalformedCommitments(t *testing.T) {
+	cfg := &Config{Group: G1Group, N: 5, T: 3}
+	for name, bad := range map[string]Point{
+		"wrong group":   G2Group.ScalarBaseMult(big.NewInt(1)),
+		"nil P":         &G1Point{},
+		"zero-value P":  &G1Point{P: new(bn256.G1)},
+		"typed nil":     (*G1Point)(nil),
+		"interface nil": nil,
+	} {
+		malformed := func(commits []Point) []Point {
+			ret := append([]Point(nil), commits...)
+			ret[1] = bad
+			return ret
+		}
+		net := &network{
+			deals: func(deals []*Deal) []*Deal {
+				for _, d := range deals {
+					if d.Dealer == 2 {
+						d.Commitments = malformed(d.Commitments)
+					}
+				}
+				return deals
+			},
+			justifications: func(js []*Justification) []*Justification {
+				for _, j := range js {
+					if j.Dealer == 2 {
+						j.Commitments = malformed(j.Commitments)
+					}
+				}
+				return js
+			},
+		}
+		t.Run(name, func(t *testing.T) {
+			results := net.run(t, cfg)
+			checkResults(t, cfg, results, []int{1, 3, 4, 5}, []int{1, 3, 4, 5})
+		})
+	}
+}
+
+func TestAddWrongGroup(t *testing.T) {
+	a := G1Group.ScalarBaseMult(big.NewInt(1))
+	b := G2Group.ScalarBaseMult(big.NewInt(1))
+	if a.Add(b) != nil || b.Add(a) != nil {
+		t.Fatal("added points of different groups")
+	}
+	if a.Add(&G1Point{}) != nil || b.Add((*G2Point)(nil)) != nil ||
+		a.Add(&G1Point{new(bn256.G1)}) != nil || b.Add(&G2Point{new(bn256.G2)}) != nil {
+		t.Fatal("added a malformed point")
+	}
+}
+
+// TestEquivocation checks that a dealer that deals consistent shares of
+// different polynomials to different participants is disqualified.
+func TestEquivocation(t *testing.T) {
+	cfg := &Config{Group: G1Group, N: 4, T: 2}
+	twin, err := NewParticipant(cfg, 1, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := twin.Deals()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	net := &network{
+		deals: func(deals []*Deal) []*Deal {
+			for i, d := range deals {
+				if d.Dealer == 1 && d.Recipient == 3 {
+					deals[i] = other[2]
+				}
+			}
+			return deals
+		},
+	}
+	results := net.run(t, cfg)
+	checkResults(t, cfg, results, []int{2, 3, 4}, []int{2, 3, 4})
+}
+
+// TestDuplicateDeal checks that a dealer that sends two deals to the same
+// participant gets a complaint.
+func TestDuplicateDeal(t *testing.T) {
+	cfg := &Config{Group: G1Group, N: 3, T: 2}
+	p, _ := NewParticipant(cfg, 1, rand.Reader)
+	q, _ := NewParticipant(cfg, 2, rand.Reader)
+
+	deals, _ := p.Deals()
+	q.Deals()
+	responses, err := q.Respond([]*Deal{deals[1], deals[1]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !responses[0].Complaint {
+		t.Fatal("no complaint about duplicate deals")
+	}
+	if !responses[2].Complaint {
+		t.Fatal("no complaint about missing deal")
+	}
+}
+
+func TestPhases(t *testing.T) {
+	cfg := &Config{Group: G1Group, N: 3, T: 2}
+	p, err := NewParticipant(cfg, 1, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Respond(nil); err == nil {
+		t.Fatal("response before deal")
+	}
+	if _, err := p.Deals(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Deals(); err == nil {
+		t.Fatal("dealt twice")
+	}
+	if _, err := p.Finalize(nil); err == nil {
+		t.Fatal("finalized before responses")
+	}
+
+	for _, c := range []*Config{
+		{Group: G1Group, N: 3, T: 4},
+		{Group: G1Group, N: 3, T: 0},
+		{N: 3, T: 2},
+	} {
+		if _, err := NewParticipant(c, 1, rand.Reader); err == nil {
+			t.Fatalf("invalid configuration %+v accepted", c)
+		}
+	}
+	if _, err := NewParticipant(cfg, 4, rand.Reader); err == nil {
+		t.Fatal("index out of range accepted")
+	}
+}
+
+// TestTooFewQualified checks that the protocol fails when too many dealers are
+// disqualified.
+func TestTooFewQualified(t *testing.T) {
+	cfg := &Config{Group: G1Group, N: 3, T: 3}
+	ps := make([]*Participant, cfg.N)
+	var deals []*Deal
+	for i := range ps {
+		ps[i], _ = NewParticipant(cfg, i+1, rand.Reader)
+		d, _ := ps[i].Deals()
+		if i == 0 {
+			deals = append(deals, d...)
+		}
+	}
+
+	var responses []*Response
+	for _, p := range ps {
+		r, _ := p.Respond(deals)
+		responses = append(responses, r...)
+	}
+	for _, p := range ps {
+		p.Justify(responses)
+	}
+	if _, err := ps[0].Finalize(nil); err == nil {
+		t.Fatal("finalized with too few qualified dealers")
+	}
+}
@@ -0,0 +1,102 @@
+package dkg
+
+import (
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// Group is a group that commitments and public keys can live in. G1Group and
+// G2Group are the implementations provided.
+type Group interface {
+	// ScalarBaseMult returns k times the generator of the group.
+	ScalarBaseMult(k *big.Int) Point
+	// Contains reports whether p is a well-formed point of the group: one
+	// that has been set, and lies in its prime-order subgroup. Points
+	// received from other participants are checked with Contains before any
+	// of their methods are called.
+	Contains(p Point) bool
+}
+
+// Point is an element of a Group. Its methods do not modify their receiver.
+type Point interface {
+	// Add returns the sum of the receiver and b, or nil if b is not a point
+	// of the same group.
+	Add(b Point) Point
+	// ScalarMult returns k times the receiver.
+	ScalarMult(k *big.Int) Point
+	// Marshal returns the encoding of the point used by bn256.
+	Marshal() []byte
+}
+
+var (
+	// G1Group runs the protocol in G₁.
+	G1Group Group = g1Group{}
+	// G2Group runs the protocol in G₂, which gives public keys suitable for
+	// BLS signatures in G₁.
+	G2Group Group = g2Group{}
+)
+
+type g1Group struct{}
+
+func (g1Group) ScalarBaseMult(k *big.Int) Point {
+	return &G1Point{new(bn256.G1).ScalarBaseMult(k)}
+}
+
+// G1Point is a Point of G1Group.
+type G1Point struct {
+	P *bn256.G1
+}
+
+func (g1Group) Contains(p Point) bool {
+	a, ok := p.(*G1Point)
+	return ok && a != nil && a.P != nil && *a.P != (bn256.G1{}) && a.P.IsInSubgroup()
+}
+
+func (a *G1Point) Add(b Point) Point {
+	c, ok := b.(*G1Point)
+	if !ok || !G1Group.Contains(c) {
+		return nil
+	}
+	return &G1Point{new(bn256.G1).Add(a.P, c.P)}
+}
+
+func (a *G1Point) ScalarMult(k *big.Int) Point {
+	return &G1Point{new(bn256.G1).ScalarMult(a.P, k)}
+}
+
+func (a *G1Point) Marshal() []byte {
+	return a.P.Marshal()
+}
+
+type g2Group struct{}
+
+func (g2Group) ScalarBaseMult(k *big.Int) Point {
+	return &G2Point{new(bn256.G2).ScalarBaseMult(k)}
+}
+
+// G2Point is a Point of G2Group.
+type G2Point struct {
+	P *bn256.G2
+}
+
+func (g2Group) Contains(p Point) bool {
+	a, ok := p.(*G2Point)
+	return ok && a != nil && a.P != nil && *a.P != (bn256.G2{}) && a.P.IsInSubgroup()
+}
+
+func (a *G2Point) Add(b Point) Point {
+	c, ok := b.(*G2Point)
+	if !ok || !G2Group.Contains(c) {
+		return nil
+	}
+	return &G2Point{new(bn256.G2).Add(a.P, c.P)}
+}
+
+func (a *G2Point) ScalarMult(k *big.Int) Point {
+	return &G2Point{new(bn256.G2).ScalarMult(a.P, k)}
+}
+
+func (a *G2Point) Marshal() []byte {
+	return a.P.Marshal()
+}
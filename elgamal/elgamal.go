@@ -0,0 +1,208 @@
+// Package elgamal implements ElGamal encryption over G₁.
+//
+// A message M ∈ G₁ is encrypted under the public key PK = x·G as
+//
+//	(C1, C2) = (r·G, M + r·PK)
+//
+// for a random r, where G is the generator of G₁. Ciphertexts are additively
+// homomorphic: the sum of two ciphertexts is an encryption of the sum of
+// their messages. Lifted ElGamal encodes a small integer m as the point m·G,
+// so that sums of ciphertexts decrypt to sums of integers; decryption then
+// needs a discrete logarithm, which a DlogTable computes for bounded m.
+//
+// Decryption can be accompanied by a Chaum-Pedersen proof that it was carried
+// out with the private key matching PK, so that anyone can check a decrypted
+// result without learning the key.
+package elgamal
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// PublicKey is an ElGamal public key.
+type PublicKey struct {
+	P *bn256.G1
+}
+
+// PrivateKey is an ElGamal private key.
+type PrivateKey struct {
+	PublicKey
+	X *big.Int
+}
+
+// GenerateKey returns a new key pair using randomness from r.
+func GenerateKey(r io.Reader) (*PrivateKey, error) {
+	x, P, err := bn256.RandomG1(r)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{PublicKey{P}, x}, nil
+}
+
+// Ciphertext is an ElGamal ciphertext. The zero value is suitable for use as
+// the output of an operation, but cannot be used as an input.
+type Ciphertext struct {
+	C1, C2 *bn256.G1
+}
+
+// Encrypt returns an encryption of m under pub using randomness from r.
+func Encrypt(r io.Reader, pub *PublicKey, m *bn256.G1) (*Ciphertext, error) {
+	k, err := rand.Int(r, bn256.Order)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptWithNonce(pub, m, k), nil
+}
+
+// EncryptWithNonce returns the encryption of m under pub with the nonce k.
+// Reusing a nonce reveals the difference between the messages, so k must be
+// secret and random; it is exposed for protocols that prove statements about
+// the nonce.
+func EncryptWithNonce(pub *PublicKey, m *bn256.G1, k *big.Int) *Ciphertext {
+	c2 := new(bn256.G1).ScalarMult(pub.P, k)
+	return &Ciphertext{
+		C1: new(bn256.G1).ScalarBaseMult(k),
+		C2: c2.Add(c2, m),
+	}
+}
+
+// EncryptInt returns a lifted encryption of m, that is an encryption of m·G,
+// under pub using randomness from r.
+func EncryptInt(r io.Reader, pub *PublicKey, m *big.Int) (*Ciphertext, error) {
+	return Encrypt(r, pub, new(bn256.G1).ScalarBaseMult(m))
+}
+
+// Decrypt returns the message encrypted in c.
+func (priv *PrivateKey) Decrypt(c *Ciphertext) *bn256.G1 {
+	return new(bn256.G1).Add(c.C2, new(bn256.G1).Neg(priv.share(c)))
+}
+
+// DecryptInt returns the integer m such that c is a lifted encryption of m,
+// using table to compute the discrete logarithm. It returns an error if m is
+// outside the range of table.
+func (priv *PrivateKey) DecryptInt(c *Ciphertext, table *DlogTable) (uint64, error) {
+	return table.Solve(priv.Decrypt(c))
+}
+
+// share returns x·C1, the value that masks the message of c.
+func (priv *PrivateKey) share(c *Ciphertext) *bn256.G1 {
+	return new(bn256.G1).ScalarMult(c.C1, priv.X)
+}
+
+// Add sets c to a+b, which is an encryption of the sum of the messages of a
+// and b, and then returns c.
+func (c *Ciphertext) Add(a, b *Ciphertext) *Ciphertext {
+	if c.C1 == nil {
+		c.C1, c.C2 = new(bn256.G1), new(bn256.G1)
+	}
+	c.C1.Add(a.C1, b.C1)
+	c.C2.Add(a.C2, b.C2)
+	return c
+}
+
+// Neg sets c to -a, which is an encryption of the negated message of a, and
+// then returns c.
+func (c *Ciphertext) Neg(a *Ciphertext) *Ciphertext {
+	if c.C1 == nil {
+		c.C1, c.C2 = new(bn256.G1), new(bn256.G1)
+	}
+	c.C1.Neg(a.C1)
+	c.C2.Neg(a.C2)
+	return c
+}
+
+// ScalarMult sets c to k·a, which is an encryption of the message of a
+// multiplied by k, and then returns c.
+func (c *Ciphertext) ScalarMult(a *Ciphertext, k *big.Int) *Ciphertext {
+	if c.C1 == nil {
+		c.C1, c.C2 = new(bn256.G1), new(bn256.G1)
+	}
+	c.C1.ScalarMult(a.C1, k)
+	c.C2.ScalarMult(a.C2, k)
+	return c
+}
+
+// Rerandomize returns a fresh encryption under pub of the message of c, using
+// randomness from r. The result cannot be linked to c without the private key.
+func Rerandomize(r io.Reader, pub *PublicKey, c *Ciphertext) (*Ciphertext, error) {
+	zero := new(bn256.G1).ScalarBaseMult(new(big.Int))
+	d, err := Encrypt(r, pub, zero)
+	if err != nil {
+		return nil, err
+	}
+	return d.Add(d, c), nil
+}
+
+// Marshal converts c into a byte slice.
+func (c *Ciphertext) Marshal() []byte {
+	return append(c.C1.Marshal(), c.C2.Marshal()...)
+}
+
+// Unmarshal sets c to the result of converting the output of Marshal back into
+// a ciphertext and then returns the remainder of m.
+func (c *Ciphertext) Unmarshal(m []byte) ([]byte, error) {
+	c1, c2 := new(bn256.G1), new(bn256.G1)
+	m, err := c1.Unmarshal(m)
+	if err != nil {
+		return nil, err
+	}
+	if m, err = c2.Unmarshal(m); err != nil {
+		return nil, err
+	}
+	c.C1, c.C2 = c1, c2
+	return m, nil
+}
+
+var errOutOfRange = errors.New("elgamal: discrete logarithm out of range")
+
+// DlogTable computes discrete logarithms to the base G in [0, max] with the
+// baby-step giant-step algorithm. It takes O(√max) time and memory.
+type DlogTable struct {
+	max   uint64
+	steps uint64
+	baby  map[string]uint64
+	giant *bn256.G1
+}
+
+// NewDlogTable returns a table for discrete logarithms in [0, max].
+func NewDlogTable(max uint64) *DlogTable {
+	steps := uint64(1)
+	for steps*steps <= max && steps < 1<<32 {
+		steps++
+	}
+
+	t := &DlogTable{
+		max:   max,
+		steps: steps,
+		baby:  make(map[string]uint64, steps),
+	}
+	g := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	p := new(bn256.G1).ScalarBaseMult(new(big.Int))
+	for j := uint64(0); j < steps; j++ {
+		t.baby[string(p.Marshal())] = j
+		p.Add(p, g)
+	}
+	t.giant = new(bn256.G1).Neg(p)
+	return t
+}
+
+// Solve returns m such that p = m·G. It returns an error if there is no such
+// m in the range of t.
+func (t *DlogTable) Solve(p *bn256.G1) (uint64, error) {
+	q := new(bn256.G1).Set(p)
+	for i := uint64(0); i*t.steps <= t.max; i++ {
+		if j, ok := t.baby[string(q.Marshal())]; ok {
+			if m := i*t.steps + j; m <= t.max {
+				return m, nil
+			}
+			return 0, errOutOfRange
+		}
+		q.Add(q, t.giant)
+	}
+	return 0, errOutOfRange
+}
@@ -0,0 +1,171 @@
+package elgamal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	priv, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, m, _ := bn256.RandomG1(rand.Reader)
+
+	c, err := Encrypt(rand.Reader, &priv.PublicKey, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(priv.Decrypt(c).Marshal(), m.Marshal()) {
+		t.Fatal("decryption differs from message")
+	}
+
+	other, _ := GenerateKey(rand.Reader)
+	if bytes.Equal(other.Decrypt(c).Marshal(), m.Marshal()) {
+		t.Fatal("decryption with the wrong key recovered the message")
+	}
+
+	d := new(Ciphertext)
+	if _, err := d.Unmarshal(c.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(priv.Decrypt(d).Marshal(), m.Marshal()) {
+		t.Fatal("ciphertext changed after round trip")
+	}
+}
+
+func TestHomomorphicTally(t *testing.T) {
+	priv, _ := GenerateKey(rand.Reader)
+	table := NewDlogTable(100)
+
+	votes := []int64{1, 0, 1, 1, 0, 1, 0, 0, 1, 1}
+	var tally *Ciphertext
+	for _, v := range votes {
+		c, err := EncryptInt(rand.Reader, &priv.PublicKey, big.NewInt(v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tally == nil {
+			tally = c
+		} else {
+			tally.Add(tally, c)
+		}
+	}
+
+	got, err := priv.DecryptInt(tally, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 6 {
+		t.Fatalf("got tally %d, want 6", got)
+	}
+
+	tripled := new(Ciphertext).ScalarMult(tally, big.NewInt(3))
+	if got, _ := priv.DecryptInt(tripled, table); got != 18 {
+		t.Fatalf("got %d, want 18", got)
+	}
+	c, _ := EncryptInt(rand.Reader, &priv.PublicKey, big.NewInt(20))
+	diff := new(Ciphertext).Add(c, new(Ciphertext).Neg(tally))
+	if got, _ := priv.DecryptInt(diff, table); got != 14 {
+		t.Fatalf("got %d, want 14", got)
+	}
+}
+
+func TestDlogTable(t *testing.T) {
+	for _, max := range []uint64{0, 1, 15, 16, 17, 1000} {
+		table := NewDlogTable(max)
+		for _, m := range []uint64{0, max / 2, max} {
+			got, err := table.Solve(new(bn256.G1).ScalarBaseMult(new(big.Int).SetUint64(m)))
+			if err != nil {
+				t.Fatalf("max=%d, m=%d: %v", max, m, err)
+			}
+			if got != m {
+				t.Fatalf("max=%d: got %d, want %d", max, got, m)
+			}
+		}
+
+		p := new(bn256.G1).ScalarBaseMult(new(big.Int).SetUint64(max + 1))
+		if _, err := table.Solve(p); err == nil {
+			t.Fatalf("max=%d: solved logarithm out of range", max)
+		}
+		p.ScalarBaseMult(big.NewInt(-1))
+		if _, err := table.Solve(p); err == nil {
+			t.Fatalf("max=%d: solved negative logarithm", max)
+		}
+	}
+}
+
+func TestRerandomize(t *testing.T) {
+	priv, _ := GenerateKey(rand.Reader)
+	_, m, _ := bn256.RandomG1(rand.Reader)
+	c, _ := Encrypt(rand.Reader, &priv.PublicKey, m)
+
+	d, err := Rerandomize(rand.Reader, &priv.PublicKey, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(c.Marshal(), d.Marshal()) {
+		t.Fatal("ciphertext unchanged by rerandomization")
+	}
+	if !bytes.Equal(priv.Decrypt(d).Marshal(), m.Marshal()) {
+		t.Fatal("rerandomization changed the message")
+	}
+}
+
+func TestDecryptionProof(t *testing.T) {
+	priv, _ := GenerateKey(rand.Reader)
+	_, m, _ := bn256.RandomG1(rand.Reader)
+	c, _ := Encrypt(rand.Reader, &priv.PublicKey, m)
+
+	got, proof, err := priv.DecryptWithProof(rand.Reader, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Marshal(), m.Marshal()) {
+		t.Fatal("decryption differs from message")
+	}
+	if !priv.VerifyDecryption(c, got, proof) {
+		t.Fatal("valid proof rejected")
+	}
+
+	decoded := new(DecryptionProof)
+	if _, err := decoded.Unmarshal(proof.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if !priv.VerifyDecryption(c, got, decoded) {
+		t.Fatal("proof rejected after round trip")
+	}
+
+	_, wrong, _ := bn256.RandomG1(rand.Reader)
+	if priv.VerifyDecryption(c, wrong, proof) {
+		t.Fatal("proof accepted for the wrong message")
+	}
+	other, _ := GenerateKey(rand.Reader)
+	if other.VerifyDecryption(c, got, proof) {
+		t.Fatal("proof accepted for the wrong key")
+	}
+	d, _ := Rerandomize(rand.Reader, &priv.PublicKey, c)
+	if priv.VerifyDecryption(d, got, proof) {
+		t.Fatal("proof accepted for another ciphertext")
+	}
+
+	// A key holder cannot prove a false decryption.
+	_, lie, _ := other.DecryptWithProof(rand.Reader, c)
+	if priv.VerifyDecryption(c, priv.Decrypt(c), lie) {
+		t.Fatal("proof made with the wrong key accepted")
+	}
+}
+
+func BenchmarkDlogTableSolve(b *testing.B) {
+	table := NewDlogTable(1 << 20)
+	p := new(bn256.G1).ScalarBaseMult(big.NewInt(1<<20 - 1))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		table.Solve(p)
+	}
+}
@@ -0,0 +1,98 @@
+package elgamal
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// DecryptionProof is a Chaum-Pedersen proof that a decryption was carried out
+// with the private key matching a public key: it shows that
+// log_G(PK) = log_C1(C2 - M) without revealing the key.
+type DecryptionProof struct {
+	E, S *big.Int
+}
+
+// DecryptWithProof returns the message encrypted in c and a proof that it is
+// the correct decryption, using randomness from r.
+func (priv *PrivateKey) DecryptWithProof(r io.Reader, c *Ciphertext) (*bn256.G1, *DecryptionProof, error) {
+	k, err := rand.Int(r, bn256.Order)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	D := priv.share(c)
+	A1 := new(bn256.G1).ScalarBaseMult(k)
+	A2 := new(bn256.G1).ScalarMult(c.C1, k)
+	e := challenge(&priv.PublicKey, c, D, A1, A2)
+
+	s := new(big.Int).Mul(e, priv.X)
+	s.Add(s, k)
+	s.Mod(s, bn256.Order)
+
+	m := new(bn256.G1).Add(c.C2, new(bn256.G1).Neg(D))
+	return m, &DecryptionProof{e, s}, nil
+}
+
+// VerifyDecryption returns true iff proof shows that m is the decryption of c
+// under the private key matching pub.
+func (pub *PublicKey) VerifyDecryption(c *Ciphertext, m *bn256.G1, proof *DecryptionProof) bool {
+	if proof.E == nil || proof.S == nil {
+		return false
+	}
+	D := new(bn256.G1).Add(c.C2, new(bn256.G1).Neg(m))
+	negE := new(big.Int).Neg(proof.E)
+
+	// A1 = s·G - e·PK and A2 = s·C1 - e·D
+	A1 := new(bn256.G1).MultiScalarMult(
+		[]*bn256.G1{new(bn256.G1).ScalarBaseMult(big.NewInt(1)), pub.P},
+		[]*big.Int{proof.S, negE},
+	)
+	A2 := new(bn256.G1).MultiScalarMult([]*bn256.G1{c.C1, D}, []*big.Int{proof.S, negE})
+	return challenge(pub, c, D, A1, A2).Cmp(proof.E) == 0
+}
+
+// challenge returns the Fiat-Shamir challenge for a decryption proof. 64 bytes
+// of hash output are reduced modulo Order so that the result is close to
+// uniform.
+func challenge(pub *PublicKey, c *Ciphertext, D, A1, A2 *bn256.G1) *big.Int {
+	var buf []byte
+	for i := byte(0); i < 2; i++ {
+		h := sha256.New()
+		h.Write([]byte("bn256/elgamal/decryption"))
+		h.Write([]byte{i})
+		for _, p := range []*bn256.G1{pub.P, c.C1, c.C2, D, A1, A2} {
+			h.Write(p.Marshal())
+		}
+		buf = h.Sum(buf)
+	}
+	e := new(big.Int).SetBytes(buf)
+	return e.Mod(e, bn256.Order)
+}
+
+// Marshal converts proof into a byte slice.
+func (proof *DecryptionProof) Marshal() []byte {
+	ret := make([]byte, 64)
+	e, s := proof.E.Bytes(), proof.S.Bytes()
+	copy(ret[32-len(e):], e)
+	copy(ret[64-len(s):], s)
+	return ret
+}
+
+// Unmarshal sets proof to the result of converting the output of Marshal back
+// into a proof and then returns the remainder of m.
+func (proof *DecryptionProof) Unmarshal(m []byte) ([]byte, error) {
+	if len(m) < 64 {
+		return nil, errors.New("elgamal: not enough data")
+	}
+	e, s := new(big.Int).SetBytes(m[:32]), new(big.Int).SetBytes(m[32:64])
+	if e.Cmp(bn256.Order) >= 0 || s.Cmp(bn256.Order) >= 0 {
+		return nil, errors.New("elgamal: malformed proof")
+	}
+	proof.E, proof.S = e, s
+	return m[64:], nil
+}
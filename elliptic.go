@@ -0,0 +1,80 @@
+package bn256
+
+import (
+	"crypto/elliptic"
+	"math/big"
+)
+
+// g1Curve implements elliptic.Curve on top of curvePoint. The methods of
+// elliptic.CurveParams cannot be used, as they assume that a = -3.
+type g1Curve struct {
+	params *elliptic.CurveParams
+}
+
+var curveParams = &elliptic.CurveParams{
+	P:       new(big.Int).Set(P),
+	N:       new(big.Int).Set(Order),
+	B:       big.NewInt(3),
+	Gx:      big.NewInt(1),
+	Gy:      big.NewInt(2),
+	BitSize: P.BitLen(),
+	Name:    "bn256",
+}
+
+// Curve returns G₁ as an elliptic.Curve, for use with packages such as
+// crypto/ecdsa or with elliptic.Marshal. As in those packages, the identity
+// is represented by (0, 0), and the methods panic if given a point that is
+// not on the curve.
+func Curve() elliptic.Curve {
+	return g1Curve{curveParams}
+}
+
+func (c g1Curve) Params() *elliptic.CurveParams {
+	return c.params
+}
+
+// IsOnCurve reports whether (x, y) is on the curve. It returns false for
+// (0, 0), which is only a stand-in for the identity.
+func (c g1Curve) IsOnCurve(x, y *big.Int) bool {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return false
+	}
+	return new(G1).SetCoordinates(x, y) == nil
+}
+
+func (c g1Curve) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	a, b := c.point("Add", x1, y1), c.point("Add", x2, y2)
+	return a.Add(a, b).Coordinates()
+}
+
+func (c g1Curve) Double(x1, y1 *big.Int) (x, y *big.Int) {
+	a := c.point("Double", x1, y1)
+	a.p.Double(a.p)
+	return a.Coordinates()
+}
+
+func (c g1Curve) ScalarMult(x1, y1 *big.Int, k []byte) (x, y *big.Int) {
+	a := c.point("ScalarMult", x1, y1)
+	return a.ScalarMult(a, c.scalar(k)).Coordinates()
+}
+
+func (c g1Curve) ScalarBaseMult(k []byte) (x, y *big.Int) {
+	return new(G1).ScalarBaseMult(c.scalar(k)).Coordinates()
+}
+
+// point returns (x, y) as an element of G₁, or panics with a message naming
+// the method op if it is not on the curve.
+func (c g1Curve) point(op string, x, y *big.Int) *G1 {
+	e := new(G1)
+	if err := e.SetCoordinates(x, y); err != nil {
+		panic("bn256: " + op + " was called on an invalid point")
+	}
+	return e
+}
+
+// scalar returns the big-endian integer k reduced modulo Order, since callers
+// such as crypto/ecdsa may pass values up to the byte length of Order.
+func (c g1Curve) scalar(k []byte) *big.Int {
+	s := new(big.Int).SetBytes(k)
+	return s.Mod(s, Order)
+}
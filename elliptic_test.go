@@ -0,0 +1,81 @@
+package bn256
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+func TestCurve(t *testing.T) {
+	c := Curve()
+	params := c.Params()
+	if !c.IsOnCurve(params.Gx, params.Gy) || c.IsOnCurve(big.NewInt(1), big.NewInt(3)) {
+		t.Fatal("IsOnCurve misclassifies points")
+	}
+	if c.IsOnCurve(new(big.Int), new(big.Int)) {
+		t.Error("the identity is reported to be on the curve")
+	}
+
+	k, g1, _ := RandomG1(rand.Reader)
+	wantX, wantY := g1.Coordinates()
+
+	if x, y := c.ScalarBaseMult(k.Bytes()); x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+		t.Error("ScalarBaseMult differs from G1")
+	}
+	// Scalars are reduced modulo Order.
+	kn := new(big.Int).Add(k, Order)
+	if x, y := c.ScalarMult(params.Gx, params.Gy, kn.Bytes()); x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+		t.Error("ScalarMult differs from G1")
+	}
+
+	x2, y2 := c.Double(wantX, wantY)
+	if x, y := c.Add(wantX, wantY, wantX, wantY); x.Cmp(x2) != 0 || y.Cmp(y2) != 0 {
+		t.Error("Add(p, p) != Double(p)")
+	}
+	if x, y := c.ScalarMult(wantX, wantY, []byte{2}); x.Cmp(x2) != 0 || y.Cmp(y2) != 0 {
+		t.Error("2p != Double(p)")
+	}
+
+	// (0, 0) is the identity, as in crypto/elliptic.
+	negY := new(big.Int).Sub(P, wantY)
+	if x, y := c.Add(wantX, wantY, wantX, negY); x.Sign() != 0 || y.Sign() != 0 {
+		t.Error("p + -p is not (0, 0)")
+	}
+	if x, y := c.Add(wantX, wantY, new(big.Int), new(big.Int)); x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+		t.Error("p + 0 != p")
+	}
+
+	x, y := elliptic.Unmarshal(c, elliptic.Marshal(c, wantX, wantY))
+	if x == nil || x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+		t.Error("elliptic.Marshal round trip failed")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Add accepted a point off the curve")
+		}
+	}()
+	c.Add(wantX, wantY, big.NewInt(1), big.NewInt(3))
+}
+
+func TestCurveECDSA(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(Curve(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256([]byte("bn256"))
+	r, s, err := ecdsa.Sign(rand.Reader, priv, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ecdsa.Verify(&priv.PublicKey, hash[:], r, s) {
+		t.Error("signature does not verify")
+	}
+	hash[0] ^= 1
+	if ecdsa.Verify(&priv.PublicKey, hash[:], r, s) {
+		t.Error("signature verifies for another message")
+	}
+}
@@ -0,0 +1,484 @@
+// Package encoding converts points of G₁ and G₂, and scalars, to and from the
+// wire formats of other BN254 libraries.
+//
+// The same point is written differently across ecosystems. This package
+// (bn256.G1.Marshal) and the Ethereum precompiles use big-endian coordinates;
+// gnark uses big-endian coordinates with two flag bits in the first byte;
+// arkworks uses little-endian coordinates with two flag bits in the last byte;
+// and snarkjs writes little-endian coordinates in Montgomery form in its
+// binary files. Each is a Format, and every Format can marshal and unmarshal
+// G1, G2 and scalars:
+//
+//	b := encoding.GnarkCompressed.MarshalG1(p)
+//	q, err := encoding.GnarkCompressed.UnmarshalG1(b)
+//
+// Unmarshalling checks that coordinates are reduced, that points are on the
+// curve and in the group, and that the input has exactly the expected length.
+package encoding
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// Format is a wire format for points and scalars.
+type Format int
+
+const (
+	// Ethereum is the format of bn256.G1.Marshal and of the EIP-196 and
+	// EIP-197 precompiles: coordinates are 32 bytes big-endian, an element
+	// a₀+a₁·i of GF(p²) is a₁‖a₀, a point is x‖y, and the identity is all
+	// zeros. Scalars are 32 bytes big-endian.
+	Ethereum Format = iota
+
+	// GnarkCompressed is the format of gnark-crypto's Bytes: x as in
+	// Ethereum, with the top two bits of the first byte set to 10 if y is
+	// the smaller of its two possible values, 11 if it is the larger, and
+	// 01 for the identity. Scalars are as in Ethereum.
+	GnarkCompressed
+
+	// GnarkUncompressed is the format of gnark-crypto's RawBytes, which is
+	// the same as Ethereum.
+	GnarkUncompressed
+
+	// ArkworksCompressed is the compressed format of arkworks'
+	// CanonicalSerialize: x is little-endian, an element a₀+a₁·i of GF(p²)
+	// is a₀‖a₁, and bit 7 of the last byte is set if y is the larger of its
+	// two possible values, bit 6 for the identity. Scalars are 32 bytes
+	// little-endian.
+	ArkworksCompressed
+
+	// ArkworksUncompressed is the uncompressed format of arkworks'
+	// CanonicalSerialize: x‖y, little-endian as in ArkworksCompressed, with
+	// the flags in the last byte of y.
+	ArkworksUncompressed
+
+	// SnarkjsBinary is the format of points in snarkjs' binary files such as
+	// .zkey: coordinates are 32 bytes little-endian in Montgomery form, that
+	// is multiplied by 2²⁵⁶ mod p, an element a₀+a₁·i of GF(p²) is a₀‖a₁,
+	// and the identity is all zeros. Scalars are 32 bytes little-endian in
+	// Montgomery form modulo the group order.
+	SnarkjsBinary
+)
+
+// Formats lists every Format.
+var Formats = []Format{Ethereum, GnarkCompressed, GnarkUncompressed, ArkworksCompressed, ArkworksUncompressed, SnarkjsBinary}
+
+var (
+	errFormat     = errors.New("encoding: unknown format")
+	errLength     = errors.New("encoding: wrong length")
+	errCoordinate = errors.New("encoding: coordinate is not reduced")
+	errScalar     = errors.New("encoding: scalar is not reduced")
+	errFlags      = errors.New("encoding: invalid flags")
+	errIdentity   = errors.New("encoding: identity with non-zero coordinates")
+	errNoPoint    = errors.New("encoding: no point with this x coordinate")
+)
+
+// numBytes is the size of an element of GF(p) or a scalar.
+const numBytes = 32
+
+// Flag bits of the compressed formats.
+const (
+	gnarkMask        = 0xc0
+	gnarkSmallest    = 0x80
+	gnarkLargest     = 0xc0
+	gnarkInfinity    = 0x40
+	arkworksMask     = 0xc0
+	arkworksLargest  = 0x80
+	arkworksInfinity = 0x40
+)
+
+// montR is the Montgomery constant 2²⁵⁶ used by snarkjs.
+var montR = new(big.Int).Lsh(big.NewInt(1), 256)
+
+func (f Format) String() string {
+	switch f {
+	case Ethereum:
+		return "ethereum"
+	case GnarkCompressed:
+		return "gnark-compressed"
+	case GnarkUncompressed:
+		return "gnark-uncompressed"
+	case ArkworksCompressed:
+		return "arkworks-compressed"
+	case ArkworksUncompressed:
+		return "arkworks-uncompressed"
+	case SnarkjsBinary:
+		return "snarkjs"
+	}
+	return "unknown"
+}
+
+func (f Format) compressed() bool {
+	return f == GnarkCompressed || f == ArkworksCompressed
+}
+
+// pointSize returns the size of a point whose coordinates have degree
+// coefficients.
+func (f Format) pointSize(degree int) int {
+	if f.compressed() {
+		return degree * numBytes
+	}
+	return 2 * degree * numBytes
+}
+
+// G1Size returns the size of an encoded point of G₁.
+func (f Format) G1Size() int {
+	return f.pointSize(1)
+}
+
+// G2Size returns the size of an encoded point of G₂.
+func (f Format) G2Size() int {
+	return f.pointSize(2)
+}
+
+// ScalarSize returns the size of an encoded scalar.
+func (f Format) ScalarSize() int {
+	return numBytes
+}
+
+// MarshalG1 returns the encoding of p.
+func (f Format) MarshalG1(p *bn256.G1) []byte {
+	m := p.Marshal()
+	x, y := elementFromEthereum(m[:numBytes]), elementFromEthereum(m[numBytes:])
+	return f.marshalPoint(x, y, isZero(m))
+}
+
+// UnmarshalG1 decodes a point of G₁ from data, which must hold exactly one
+// encoded point.
+func (f Format) UnmarshalG1(data []byte) (*bn256.G1, error) {
+	x, y, inf, err := f.unmarshalPoint(data, 1, rootG1)
+	if err != nil {
+		return nil, err
+	}
+
+	p := new(bn256.G1)
+	if err := p.UnmarshalBinary(Ethereum.marshalPoint(x, y, inf)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// MarshalG2 returns the encoding of p.
+func (f Format) MarshalG2(p *bn256.G2) []byte {
+	m := p.Marshal()
+	x, y := elementFromEthereum(m[:2*numBytes]), elementFromEthereum(m[2*numBytes:])
+	return f.marshalPoint(x, y, isZero(m))
+}
+
+// UnmarshalG2 decodes a point of G₂ from data, which must hold exactly one
+// encoded point.
+func (f Format) UnmarshalG2(data []byte) (*bn256.G2, error) {
+	x, y, inf, err := f.unmarshalPoint(data, 2, rootG2)
+	if err != nil {
+		return nil, err
+	}
+
+	p := new(bn256.G2)
+	if err := p.UnmarshalBinary(Ethereum.marshalPoint(x, y, inf)); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// MarshalScalar returns the encoding of k mod bn256.Order.
+func (f Format) MarshalScalar(k *big.Int) []byte {
+	k = new(big.Int).Mod(k, bn256.Order)
+	switch f {
+	case Ethereum, GnarkCompressed, GnarkUncompressed:
+		return bigEndian(k)
+	case ArkworksCompressed, ArkworksUncompressed:
+		return reverse(bigEndian(k))
+	case SnarkjsBinary:
+		k.Mul(k, montR).Mod(k, bn256.Order)
+		return reverse(bigEndian(k))
+	}
+	panic(errFormat)
+}
+
+// UnmarshalScalar decodes a scalar from data, which must hold exactly one
+// encoded scalar less than bn256.Order.
+func (f Format) UnmarshalScalar(data []byte) (*big.Int, error) {
+	if len(data) != numBytes {
+		return nil, errLength
+	}
+
+	var k *big.Int
+	switch f {
+	case Ethereum, GnarkCompressed, GnarkUncompressed:
+		k = new(big.Int).SetBytes(data)
+	case ArkworksCompressed, ArkworksUncompressed, SnarkjsBinary:
+		k = new(big.Int).SetBytes(reverse(data))
+	default:
+		return nil, errFormat
+	}
+	if k.Cmp(bn256.Order) >= 0 {
+		return nil, errScalar
+	}
+
+	if f == SnarkjsBinary {
+		k.Mul(k, new(big.Int).ModInverse(montR, bn256.Order)).Mod(k, bn256.Order)
+	}
+	return k, nil
+}
+
+// element is an element of GF(p) or GF(p²), as its coefficients from the
+// constant term up, each in [0, p).
+type element []*big.Int
+
+// elementFromEthereum reads an element written highest degree first in
+// big-endian.
+func elementFromEthereum(b []byte) element {
+	e := make(element, len(b)/numBytes)
+	for i := range e {
+		j := len(e) - 1 - i
+		e[i] = new(big.Int).SetBytes(b[j*numBytes : (j+1)*numBytes])
+	}
+	return e
+}
+
+func zeroElement(degree int) element {
+	e := make(element, degree)
+	for i := range e {
+		e[i] = new(big.Int)
+	}
+	return e
+}
+
+func (e element) neg() element {
+	ret := make(element, len(e))
+	for i, c := range e {
+		ret[i] = new(big.Int).Sub(bn256.P, c)
+		ret[i].Mod(ret[i], bn256.P)
+	}
+	return ret
+}
+
+// largest reports whether e is lexicographically larger than -e, comparing the
+// highest-degree non-zero coefficient, as both gnark and arkworks do.
+func (e element) largest() bool {
+	half := new(big.Int).Rsh(bn256.P, 1)
+	for i := len(e) - 1; i >= 0; i-- {
+		if e[i].Sign() != 0 {
+			return e[i].Cmp(half) > 0
+		}
+	}
+	return false
+}
+
+// marshal appends the encoding of e in format f to b.
+func (f Format) marshal(b []byte, e element) []byte {
+	switch f {
+	case Ethereum, GnarkCompressed, GnarkUncompressed:
+		for i := len(e) - 1; i >= 0; i-- {
+			b = append(b, bigEndian(e[i])...)
+		}
+	case ArkworksCompressed, ArkworksUncompressed:
+		for _, c := range e {
+			b = append(b, reverse(bigEndian(c))...)
+		}
+	case SnarkjsBinary:
+		for _, c := range e {
+			m := new(big.Int).Mul(c, montR)
+			b = append(b, reverse(bigEndian(m.Mod(m, bn256.P)))...)
+		}
+	default:
+		panic(errFormat)
+	}
+	return b
+}
+
+// unmarshal decodes an element of the given degree from b, which must be of
+// the right length and have its flag bits cleared.
+func (f Format) unmarshal(b []byte, degree int) (element, error) {
+	e := make(element, degree)
+	for i := range e {
+		var c []byte
+		switch f {
+		case Ethereum, GnarkCompressed, GnarkUncompressed:
+			j := degree - 1 - i
+			c = b[j*numBytes : (j+1)*numBytes]
+		case ArkworksCompressed, ArkworksUncompressed, SnarkjsBinary:
+			c = reverse(b[i*numBytes : (i+1)*numBytes])
+		default:
+			return nil, errFormat
+		}
+
+		e[i] = new(big.Int).SetBytes(c)
+		if e[i].Cmp(bn256.P) >= 0 {
+			return nil, errCoordinate
+		}
+		if f == SnarkjsBinary {
+			e[i].Mul(e[i], new(big.Int).ModInverse(montR, bn256.P)).Mod(e[i], bn256.P)
+		}
+	}
+	return e, nil
+}
+
+func (f Format) marshalPoint(x, y element, inf bool) []byte {
+	size := len(x) * numBytes
+	if inf {
+		ret := make([]byte, f.pointSize(len(x)))
+		switch f {
+		case GnarkCompressed:
+			ret[0] = gnarkInfinity
+		case ArkworksCompressed, ArkworksUncompressed:
+			ret[len(ret)-1] = arkworksInfinity
+		}
+		return ret
+	}
+
+	switch f {
+	case GnarkCompressed:
+		ret := f.marshal(nil, x)
+		if y.largest() {
+			ret[0] |= gnarkLargest
+		} else {
+			ret[0] |= gnarkSmallest
+		}
+		return ret
+	case ArkworksCompressed:
+		ret := f.marshal(nil, x)
+		if y.largest() {
+			ret[size-1] |= arkworksLargest
+		}
+		return ret
+	case ArkworksUncompressed:
+		ret := f.marshal(f.marshal(nil, x), y)
+		if y.largest() {
+			ret[2*size-1] |= arkworksLargest
+		}
+		return ret
+	}
+	return f.marshal(f.marshal(nil, x), y)
+}
+
+// unmarshalPoint decodes the affine coordinates of a point whose coordinates
+// have the given degree. For compressed formats, root returns a square root of
+// x³+b, or nil if there is none.
+func (f Format) unmarshalPoint(data []byte, degree int, root func(x element) element) (x, y element, inf bool, err error) {
+	if f < Ethereum || f > SnarkjsBinary {
+		return nil, nil, false, errFormat
+	}
+	if len(data) != f.pointSize(degree) {
+		return nil, nil, false, errLength
+	}
+	size := degree * numBytes
+
+	b := append([]byte(nil), data...)
+	var flags byte
+	switch f {
+	case GnarkCompressed:
+		flags = b[0] & gnarkMask
+		b[0] &^= gnarkMask
+	case ArkworksCompressed, ArkworksUncompressed:
+		flags = b[len(b)-1] & arkworksMask
+		b[len(b)-1] &^= arkworksMask
+	}
+
+	switch f {
+	case GnarkCompressed:
+		if flags == gnarkInfinity {
+			if !isZero(b) {
+				return nil, nil, false, errIdentity
+			}
+			return zeroElement(degree), zeroElement(degree), true, nil
+		}
+		if flags != gnarkSmallest && flags != gnarkLargest {
+			return nil, nil, false, errFlags
+		}
+	case ArkworksCompressed, ArkworksUncompressed:
+		if flags&arkworksInfinity != 0 {
+			if flags != arkworksInfinity || !isZero(b) {
+				return nil, nil, false, errIdentity
+			}
+			return zeroElement(degree), zeroElement(degree), true, nil
+		}
+	}
+
+	if x, err = f.unmarshal(b[:size], degree); err != nil {
+		return nil, nil, false, err
+	}
+	if !f.compressed() {
+		if y, err = f.unmarshal(b[size:], degree); err != nil {
+			return nil, nil, false, err
+		}
+		// Only the identity is all zeros, except in arkworks, which flags it.
+		if isZero(b) && f == ArkworksUncompressed {
+			return nil, nil, false, errNoPoint
+		}
+		return x, y, isZero(b), nil
+	}
+
+	if y = root(x); y == nil {
+		return nil, nil, false, errNoPoint
+	}
+	largest := flags == gnarkLargest
+	if f == ArkworksCompressed {
+		largest = flags == arkworksLargest
+	}
+	if y.largest() != largest {
+		y = y.neg()
+	}
+	return x, y, false, nil
+}
+
+// rootG1 returns a square root of x³+3 in GF(p), or nil.
+func rootG1(x element) element {
+	a := new(bn256.Fp).SetBig(x[0])
+	rhs := new(bn256.Fp).Square(a)
+	rhs.Mul(rhs, a).Add(rhs, new(bn256.Fp).SetInt64(3))
+
+	y := new(bn256.Fp).Sqrt(rhs)
+	if y == nil {
+		return nil
+	}
+	return element{y.Big()}
+}
+
+// twistB is 3/ξ, the constant of the twist y² = x³+3/ξ over GF(p²).
+var twistB = func() *bn256.Fp2 {
+	xi := new(bn256.Fp2).SetBig(big.NewInt(9), big.NewInt(1))
+	b := new(bn256.Fp2).SetBig(big.NewInt(3), new(big.Int))
+	return b.Mul(b, xi.Invert(xi))
+}()
+
+// rootG2 returns a square root of x³+3/ξ in GF(p²), or nil.
+func rootG2(x element) element {
+	a := new(bn256.Fp2).SetBig(x[0], x[1])
+	rhs := new(bn256.Fp2).Square(a)
+	rhs.Mul(rhs, a).Add(rhs, twistB)
+
+	y := new(bn256.Fp2).Sqrt(rhs)
+	if y == nil {
+		return nil
+	}
+	re, im := y.Big()
+	return element{re, im}
+}
+
+// bigEndian returns k, which is less than 2²⁵⁶, as 32 bytes big-endian.
+func bigEndian(k *big.Int) []byte {
+	b := k.Bytes()
+	ret := make([]byte, numBytes)
+	copy(ret[numBytes-len(b):], b)
+	return ret
+}
+
+func reverse(b []byte) []byte {
+	ret := make([]byte, len(b))
+	for i, c := range b {
+		ret[len(b)-1-i] = c
+	}
+	return ret
+}
+
+func isZero(b []byte) bool {
+	var acc byte
+	for _, c := range b {
+		acc |= c
+	}
+	return acc == 0
+}
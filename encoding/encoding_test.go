@@ -0,0 +1,215 @@
+package encoding
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+// testdata holds k·g₁, k·g₂ and k for several k in the formats of each
+// library, in a file named after it and written by that library through the
+// generator in testdata/gen-<library>: gnark.json by gnark-crypto's Bytes and
+// RawBytes, arkworks.json by ark-serialize and snarkjs.json by ffjavascript's
+// toRprLEM, which snarkjs uses to write .zkey files.
+type fixture struct {
+	K      string            `json:"k"`
+	G1     map[string]string `json:"g1"`
+	G2     map[string]string `json:"g2"`
+	Scalar map[string]string `json:"scalar"`
+}
+
+// loadFixtures returns the fixtures of library, or skips the test if they
+// have not been generated.
+func loadFixtures(t *testing.T, library string) []fixture {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", library+".json"))
+	if os.IsNotExist(err) {
+		t.Skipf("testdata/%s.json has not been generated; run make fixtures", library)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fixtures []fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		t.Fatal(err)
+	}
+	return fixtures
+}
+
+// fixtureKey returns the name of the entry for f; Ethereum is identical to
+// gnark's uncompressed format, and scalars only depend on the library, which
+// is also the name of the entry for a scalar.
+func fixtureKey(f Format, scalar bool) string {
+	if f == Ethereum {
+		f = GnarkUncompressed
+	}
+	if scalar {
+		return strings.Split(f.String(), "-")[0]
+	}
+	return f.String()
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestFixtures(t *testing.T) {
+	for _, library := range []string{"gnark", "arkworks", "snarkjs"} {
+		t.Run(library, func(t *testing.T) {
+			testFixtures(t, library)
+		})
+	}
+}
+
+func testFixtures(t *testing.T, library string) {
+	for _, fx := range loadFixtures(t, library) {
+		k, _ := new(big.Int).SetString(fx.K, 10)
+		g1 := new(bn256.G1).ScalarBaseMult(k)
+		g2 := new(bn256.G2).ScalarBaseMult(k)
+
+		for _, f := range Formats {
+			if fixtureKey(f, true) != library {
+				continue
+			}
+			want1 := decodeHex(t, fx.G1[fixtureKey(f, false)])
+			want2 := decodeHex(t, fx.G2[fixtureKey(f, false)])
+			wantK := decodeHex(t, fx.Scalar[fixtureKey(f, true)])
+
+			if got := f.MarshalG1(g1); !bytes.Equal(got, want1) {
+				t.Errorf("%v, k=%s: G1 encodes as %x, want %x", f, fx.K, got, want1)
+			}
+			if got := f.MarshalG2(g2); !bytes.Equal(got, want2) {
+				t.Errorf("%v, k=%s: G2 encodes as %x, want %x", f, fx.K, got, want2)
+			}
+			if got := f.MarshalScalar(k); !bytes.Equal(got, wantK) {
+				t.Errorf("%v, k=%s: scalar encodes as %x, want %x", f, fx.K, got, wantK)
+			}
+
+			p1, err := f.UnmarshalG1(want1)
+			if err != nil || !bytes.Equal(p1.Marshal(), g1.Marshal()) {
+				t.Errorf("%v, k=%s: G1 fixture decodes wrongly: %v", f, fx.K, err)
+			}
+			p2, err := f.UnmarshalG2(want2)
+			if err != nil || !bytes.Equal(p2.Marshal(), g2.Marshal()) {
+				t.Errorf("%v, k=%s: G2 fixture decodes wrongly: %v", f, fx.K, err)
+			}
+			s, err := f.UnmarshalScalar(wantK)
+			if err != nil || s.Cmp(k) != 0 {
+				t.Errorf("%v, k=%s: scalar fixture decodes to %v: %v", f, fx.K, s, err)
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		k, g1, _ := bn256.RandomG1(rand.Reader)
+		g2 := new(bn256.G2).ScalarBaseMult(k)
+
+		for _, f := range Formats {
+			b1, b2 := f.MarshalG1(g1), f.MarshalG2(g2)
+			if len(b1) != f.G1Size() || len(b2) != f.G2Size() {
+				t.Fatalf("%v: wrong sizes %d and %d", f, len(b1), len(b2))
+			}
+			p1, err := f.UnmarshalG1(b1)
+			if err != nil || !bytes.Equal(p1.Marshal(), g1.Marshal()) {
+				t.Fatalf("%v: G1 round trip failed: %v", f, err)
+			}
+			p2, err := f.UnmarshalG2(b2)
+			if err != nil || !bytes.Equal(p2.Marshal(), g2.Marshal()) {
+				t.Fatalf("%v: G2 round trip failed: %v", f, err)
+			}
+			if s, err := f.UnmarshalScalar(f.MarshalScalar(k)); err != nil || s.Cmp(k) != 0 {
+				t.Fatalf("%v: scalar round trip failed: %v", f, err)
+			}
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	g1 := new(bn256.G1).ScalarBaseMult(big.NewInt(5))
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(5))
+
+	for _, f := range Formats {
+		b1 := f.MarshalG1(g1)
+		if _, err := f.UnmarshalG1(b1[1:]); err == nil {
+			t.Errorf("%v: short G1 point accepted", f)
+		}
+		if _, err := f.UnmarshalG1(append(b1, 0)); err == nil {
+			t.Errorf("%v: long G1 point accepted", f)
+		}
+		if _, err := f.UnmarshalG2(f.MarshalG2(g2)[1:]); err == nil {
+			t.Errorf("%v: short G2 point accepted", f)
+		}
+		if _, err := f.UnmarshalScalar(bytes.Repeat([]byte{0xff}, 32)); err == nil {
+			t.Errorf("%v: unreduced scalar accepted", f)
+		}
+	}
+
+	// x = 0 is not on the curve, since 3 is not a square mod p.
+	zero := make([]byte, 32)
+	for _, tc := range []struct {
+		f    Format
+		data []byte
+	}{
+		{GnarkCompressed, append([]byte{0x80}, zero[1:]...)},
+		{GnarkCompressed, append([]byte{0x00}, zero[1:]...)},                                // uncompressed flag
+		{GnarkCompressed, append([]byte{0x40}, append(zero[1:31], 1)...)},                   // identity with data
+		{ArkworksCompressed, append(zero[:31], 0xc0)},                                       // both flags
+		{ArkworksUncompressed, make([]byte, 64)},                                            // (0, 0) without the flag
+		{Ethereum, append(bn256.P.Bytes(), decodeHex(t, strings.Repeat("00", 31)+"02")...)}, // x = p
+		{GnarkUncompressed, append(decodeHex(t, strings.Repeat("00", 31)+"01"), decodeHex(t, strings.Repeat("00", 31)+"03")...)},
+	} {
+		if _, err := tc.f.UnmarshalG1(tc.data); err == nil {
+			t.Errorf("%v: %x accepted", tc.f, tc.data)
+		}
+	}
+
+	// A point on the twist outside G₂ is rejected by every format.
+	for x := int64(1); ; x++ {
+		a := new(bn256.Fp2).SetBig(big.NewInt(x), new(big.Int))
+		y := rootG2(element{big.NewInt(x), new(big.Int)})
+		if y == nil {
+			continue
+		}
+		b := append(a.Bytes(), new(bn256.Fp2).SetBig(y[0], y[1]).Bytes()...)
+		if _, err := new(bn256.G2).Unmarshal(b); err == nil {
+			continue // in the group after all
+		}
+		for _, f := range Formats {
+			enc := f.marshalPoint(element{big.NewInt(x), new(big.Int)}, y, false)
+			if _, err := f.UnmarshalG2(enc); err == nil {
+				t.Errorf("%v: point outside G₂ accepted", f)
+			}
+		}
+		break
+	}
+}
+
+func BenchmarkUnmarshalG1(b *testing.B) {
+	_, g1, _ := bn256.RandomG1(rand.Reader)
+	for _, f := range []Format{Ethereum, GnarkCompressed} {
+		data := f.MarshalG1(g1)
+		b.Run(f.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				f.UnmarshalG1(data)
+			}
+		})
+	}
+}
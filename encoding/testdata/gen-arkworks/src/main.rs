@@ -0,0 +1,68 @@
+//! Writes arkworks.json: k·g₁, k·g₂ and k for the same k as the other
+//! generators under testdata, as serialized by ark-serialize.
+
+use std::str::FromStr;
+
+use ark_bn254::{Fr, G1Projective, G2Projective};
+use ark_ec::{CurveGroup, Group};
+use ark_serialize::CanonicalSerialize;
+
+const KS: [&str; 7] = [
+    "0",
+    "1",
+    "2",
+    "7",
+    "21888242871839275222246405745257275088548364400416034343698204186575808495616",
+    "9876543210987654321098765432109876543210",
+    "1234567",
+];
+
+fn hex(b: &[u8]) -> String {
+    b.iter().map(|x| format!("{:02x}", x)).collect()
+}
+
+fn compressed<T: CanonicalSerialize>(t: &T) -> String {
+    let mut b = Vec::new();
+    t.serialize_compressed(&mut b).unwrap();
+    hex(&b)
+}
+
+fn uncompressed<T: CanonicalSerialize>(t: &T) -> String {
+    let mut b = Vec::new();
+    t.serialize_uncompressed(&mut b).unwrap();
+    hex(&b)
+}
+
+fn main() {
+    let mut entries = Vec::new();
+    for k in KS {
+        let s = Fr::from_str(k).unwrap();
+        let p1 = (G1Projective::generator() * s).into_affine();
+        let p2 = (G2Projective::generator() * s).into_affine();
+        entries.push(format!(
+            concat!(
+                "\t{{\n",
+                "\t\t\"k\": \"{}\",\n",
+                "\t\t\"g1\": {{\n",
+                "\t\t\t\"arkworks-compressed\": \"{}\",\n",
+                "\t\t\t\"arkworks-uncompressed\": \"{}\"\n",
+                "\t\t}},\n",
+                "\t\t\"g2\": {{\n",
+                "\t\t\t\"arkworks-compressed\": \"{}\",\n",
+                "\t\t\t\"arkworks-uncompressed\": \"{}\"\n",
+                "\t\t}},\n",
+                "\t\t\"scalar\": {{\n",
+                "\t\t\t\"arkworks\": \"{}\"\n",
+                "\t\t}}\n",
+                "\t}}"
+            ),
+            k,
+            compressed(&p1),
+            uncompressed(&p1),
+            compressed(&p2),
+            uncompressed(&p2),
+            compressed(&s),
+        ));
+    }
+    println!("[\n{}\n]", entries.join(",\n"));
+}
@@ -0,0 +1,71 @@
+// Command gen-gnark writes gnark.json. It needs
+// github.com/consensys/gnark-crypto v0.12.1, so it is kept out of the module
+// under testdata; run it from a scratch module that requires that version:
+//
+//	go run . > ../gnark.json
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// fixture is one entry of the output: k·g₁, k·g₂ and k, keyed by format. The
+// other generators under testdata write the same structure for the same k.
+type fixture struct {
+	K      string            `json:"k"`
+	G1     map[string]string `json:"g1"`
+	G2     map[string]string `json:"g2"`
+	Scalar map[string]string `json:"scalar"`
+}
+
+var ks = []string{
+	"0",
+	"1",
+	"2",
+	"7",
+	"21888242871839275222246405745257275088548364400416034343698204186575808495616",
+	"9876543210987654321098765432109876543210",
+	"1234567",
+}
+
+func main() {
+	_, _, g1, g2 := bn254.Generators()
+	var out []fixture
+	for _, ks := range ks {
+		k, _ := new(big.Int).SetString(ks, 10)
+		var p1 bn254.G1Affine
+		var p2 bn254.G2Affine
+		p1.ScalarMultiplication(&g1, k)
+		p2.ScalarMultiplication(&g2, k)
+		var s fr.Element
+		s.SetBigInt(k)
+
+		c1, u1 := p1.Bytes(), p1.RawBytes()
+		c2, u2 := p2.Bytes(), p2.RawBytes()
+		sb := s.Bytes()
+		out = append(out, fixture{
+			K: ks,
+			G1: map[string]string{
+				"gnark-compressed":   hex.EncodeToString(c1[:]),
+				"gnark-uncompressed": hex.EncodeToString(u1[:]),
+			},
+			G2: map[string]string{
+				"gnark-compressed":   hex.EncodeToString(c2[:]),
+				"gnark-uncompressed": hex.EncodeToString(u2[:]),
+			},
+			Scalar: map[string]string{
+				"gnark": hex.EncodeToString(sb[:]),
+			},
+		})
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	enc.Encode(out)
+}
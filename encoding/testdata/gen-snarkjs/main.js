@@ -0,0 +1,55 @@
+// Writes snarkjs.json: k·g₁, k·g₂ and k for the same k as the other generators
+// under testdata, in the representation snarkjs writes to .zkey files, which
+// is that of toRprLEM in ffjavascript.
+//
+//	npm install && node main.js > ../snarkjs.json
+
+const { buildBn128 } = require("ffjavascript");
+
+const ks = [
+    "0",
+    "1",
+    "2",
+    "7",
+    "21888242871839275222246405745257275088548364400416034343698204186575808495616",
+    "9876543210987654321098765432109876543210",
+    "1234567",
+];
+
+function hex(buff) {
+    return Buffer.from(buff).toString("hex");
+}
+
+async function main() {
+    const curve = await buildBn128(true);
+    const Fr = curve.Fr;
+
+    const out = [];
+    for (const k of ks) {
+        const s = Fr.e(k);
+        const p1 = curve.G1.timesFr(curve.G1.g, s);
+        const p2 = curve.G2.timesFr(curve.G2.g, s);
+
+        const b1 = new Uint8Array(curve.G1.F.n8 * 2);
+        curve.G1.toRprLEM(b1, 0, p1);
+        const b2 = new Uint8Array(curve.G2.F.n8 * 2);
+        curve.G2.toRprLEM(b2, 0, p2);
+        const bs = new Uint8Array(Fr.n8);
+        Fr.toRprLEM(bs, 0, s);
+
+        out.push({
+            k: k,
+            g1: { snarkjs: hex(b1) },
+            g2: { snarkjs: hex(b2) },
+            scalar: { snarkjs: hex(bs) },
+        });
+    }
+    await curve.terminate();
+
+    process.stdout.write(JSON.stringify(out, null, "\t") + "\n");
+}
+
+main().catch((err) => {
+    console.error(err);
+    process.exit(1);
+});
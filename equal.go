@@ -0,0 +1,237 @@
+package bn256
+
+// gfpCTEqual returns 1 if a == b and 0 otherwise, in constant time.
+func gfpCTEqual(a, b *gfP) int {
+	var d uint64
+	for i := range a {
+		d |= a[i] ^ b[i]
+	}
+	return int(((d | -d) >> 63) ^ 1)
+}
+
+func gfp2CTEqual(a, b *gfP2) int {
+	return gfpCTEqual(&a.x, &b.x) & gfpCTEqual(&a.y, &b.y)
+}
+
+func gfp6CTEqual(a, b *gfP6) int {
+	return gfp2CTEqual(&a.x, &b.x) & gfp2CTEqual(&a.y, &b.y) & gfp2CTEqual(&a.z, &b.z)
+}
+
+func gfp12CTEqual(a, b *gfP12) int {
+	return gfp6CTEqual(&a.x, &b.x) & gfp6CTEqual(&a.y, &b.y)
+}
+
+// equal returns 1 if c and a are the same point and 0 otherwise, in constant
+// time. Jacobian coordinates are compared without normalizing: (x₁, y₁, z₁)
+// and (x₂, y₂, z₂) are equal iff x₁z₂² = x₂z₁² and y₁z₂³ = y₂z₁³, or both
+// points are at infinity.
+func (c *curvePoint) equal(a *curvePoint) int {
+	z1z1, z2z2 := &gfP{}, &gfP{}
+	gfpMul(z1z1, &c.z, &c.z)
+	gfpMul(z2z2, &a.z, &a.z)
+
+	u1, u2, s1, s2 := &gfP{}, &gfP{}, &gfP{}, &gfP{}
+	gfpMul(u1, &c.x, z2z2)
+	gfpMul(u2, &a.x, z1z1)
+	gfpMul(s1, &c.y, z2z2)
+	gfpMul(s1, s1, &a.z)
+	gfpMul(s2, &a.y, z1z1)
+	gfpMul(s2, s2, &c.z)
+
+	zero := &gfP{}
+	inf1, inf2 := gfpCTEqual(&c.z, zero), gfpCTEqual(&a.z, zero)
+	same := gfpCTEqual(u1, u2) & gfpCTEqual(s1, s2)
+	return (inf1 & inf2) | ((inf1|inf2)^1)&same
+}
+
+// equal is the analogue of curvePoint.equal for the twist.
+func (c *twistPoint) equal(a *twistPoint) int {
+	z1z1, z2z2 := &gfP2{}, &gfP2{}
+	z1z1.Square(&c.z)
+	z2z2.Square(&a.z)
+
+	u1, u2, s1, s2 := &gfP2{}, &gfP2{}, &gfP2{}, &gfP2{}
+	u1.Mul(&c.x, z2z2)
+	u2.Mul(&a.x, z1z1)
+	s1.Mul(&c.y, z2z2).Mul(s1, &a.z)
+	s2.Mul(&a.y, z1z1).Mul(s2, &c.z)
+
+	zero := &gfP2{}
+	inf1, inf2 := gfp2CTEqual(&c.z, zero), gfp2CTEqual(&a.z, zero)
+	same := gfp2CTEqual(u1, u2) & gfp2CTEqual(s1, s2)
+	return (inf1 & inf2) | ((inf1|inf2)^1)&same
+}
+
+// point returns e.p, or the identity if e is the zero value.
+func (e *G1) point() *curvePoint {
+	if e.p == nil {
+		return &curvePoint{}
+	}
+	return e.p
+}
+
+// Equal reports whether e and a are the same element of G₁. It does not run in
+// constant time; see ConstantTimeEqual.
+func (e *G1) Equal(a *G1) bool {
+	p, q := e.point(), a.point()
+	if p.IsInfinity() || q.IsInfinity() {
+		return p.IsInfinity() && q.IsInfinity()
+	}
+	return p.equal(q) == 1
+}
+
+// ConstantTimeEqual returns 1 if e and a are the same element of G₁ and 0
+// otherwise. Its running time does not depend on the points.
+func (e *G1) ConstantTimeEqual(a *G1) int {
+	return e.point().equal(a.point())
+}
+
+// IsIdentity reports whether e is the identity of G₁.
+func (e *G1) IsIdentity() bool {
+	return e.point().IsInfinity()
+}
+
+// ConstantTimeIsIdentity returns 1 if e is the identity of G₁ and 0 otherwise,
+// in constant time.
+func (e *G1) ConstantTimeIsIdentity() int {
+	return gfpCTEqual(&e.point().z, &gfP{})
+}
+
+// SetIdentity sets e to the identity of G₁ and then returns e.
+func (e *G1) SetIdentity() *G1 {
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.SetInfinity()
+	return e
+}
+
+// IsOnCurve reports whether e is on the curve y²=x³+3.
+func (e *G1) IsOnCurve() bool {
+	return e.point().IsOnCurve()
+}
+
+// IsInSubgroup reports whether e is in G₁. As the curve has prime order, this
+// is the same as IsOnCurve.
+func (e *G1) IsInSubgroup() bool {
+	return e.point().IsOnCurve()
+}
+
+// point returns e.p, or the identity if e is the zero value.
+func (e *G2) point() *twistPoint {
+	if e.p == nil {
+		return &twistPoint{}
+	}
+	return e.p
+}
+
+// Equal reports whether e and a are the same element of G₂. It does not run in
+// constant time; see ConstantTimeEqual.
+func (e *G2) Equal(a *G2) bool {
+	p, q := e.point(), a.point()
+	if p.IsInfinity() || q.IsInfinity() {
+		return p.IsInfinity() && q.IsInfinity()
+	}
+	return p.equal(q) == 1
+}
+
+// ConstantTimeEqual returns 1 if e and a are the same element of G₂ and 0
+// otherwise. Its running time does not depend on the points.
+func (e *G2) ConstantTimeEqual(a *G2) int {
+	return e.point().equal(a.point())
+}
+
+// IsIdentity reports whether e is the identity of G₂.
+func (e *G2) IsIdentity() bool {
+	return e.point().IsInfinity()
+}
+
+// ConstantTimeIsIdentity returns 1 if e is the identity of G₂ and 0 otherwise,
+// in constant time.
+func (e *G2) ConstantTimeIsIdentity() int {
+	return gfp2CTEqual(&e.point().z, &gfP2{})
+}
+
+// SetIdentity sets e to the identity of G₂ and then returns e.
+func (e *G2) SetIdentity() *G2 {
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.SetInfinity()
+	return e
+}
+
+// IsOnCurve reports whether e is on the twist y²=x³+3/ξ. Unlike on G₁, this
+// does not imply that e is in G₂; see IsInSubgroup.
+func (e *G2) IsOnCurve() bool {
+	return e.point().isOnTwist()
+}
+
+// IsInSubgroup reports whether e is on the twist and has order dividing
+// Order, which is the check Unmarshal applies.
+func (e *G2) IsInSubgroup() bool {
+	return e.point().IsOnCurve()
+}
+
+// point returns e.p, or the identity if e is the zero value.
+func (e *GT) point() *gfP12 {
+	if e.p == nil {
+		return (&gfP12{}).SetOne()
+	}
+	return e.p
+}
+
+// Equal reports whether e and a are the same element of GT. It does not run in
+// constant time; see ConstantTimeEqual.
+func (e *GT) Equal(a *GT) bool {
+	return *e.point() == *a.point()
+}
+
+// ConstantTimeEqual returns 1 if e and a are the same element of GT and 0
+// otherwise, in constant time.
+func (e *GT) ConstantTimeEqual(a *GT) int {
+	return gfp12CTEqual(e.point(), a.point())
+}
+
+// IsIdentity reports whether e is the identity of GT.
+func (e *GT) IsIdentity() bool {
+	return e.point().IsOne()
+}
+
+// ConstantTimeIsIdentity returns 1 if e is the identity of GT and 0 otherwise,
+// in constant time.
+func (e *GT) ConstantTimeIsIdentity() int {
+	return gfp12CTEqual(e.point(), (&gfP12{}).SetOne())
+}
+
+// SetIdentity sets e to the identity of GT and then returns e.
+func (e *GT) SetIdentity() *GT {
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.SetOne()
+	return e
+}
+
+// IsOnCurve reports whether e is in the cyclotomic subgroup of GF(p¹²)*, the
+// elements of order dividing p⁴-p²+1, which is where the final
+// exponentiation lands. It is the analogue for GT of the curve check on G₁
+// and G₂, and is much cheaper than IsInSubgroup.
+func (e *GT) IsOnCurve() bool {
+	// e^(p⁴-p²+1) = 1 iff e^(p⁴)·e = e^(p²).
+	p := e.point()
+	a, b := &gfP12{}, &gfP12{}
+	a.FrobeniusP4(p).Mul(a, p)
+	b.FrobeniusP2(p)
+	return *a == *b && !p.IsZero()
+}
+
+// IsInSubgroup reports whether e is in GT, the subgroup of order Order.
+func (e *GT) IsInSubgroup() bool {
+	if !e.IsOnCurve() {
+		return false
+	}
+	a := &gfP12{}
+	a.Exp(e.point(), Order)
+	return a.IsOne()
+}
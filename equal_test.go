@@ -0,0 +1,202 @@
+package bn256
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestG1Equal(t *testing.T) {
+	k, a, err := RandomG1(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// b is a in different Jacobian coordinates, and c is affine.
+	km1 := new(big.Int).Sub(k, big.NewInt(1))
+	b := new(G1).Add(new(G1).ScalarBaseMult(km1), new(G1).ScalarBaseMult(big.NewInt(1)))
+	c := new(G1)
+	if _, err := c.Unmarshal(a.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if b.p.z == c.p.z {
+		t.Fatal("test points share a z coordinate")
+	}
+	before := *b.p
+
+	for _, p := range []*G1{a, b, c} {
+		if !a.Equal(p) || !p.Equal(a) || a.ConstantTimeEqual(p) != 1 {
+			t.Error("equal points compare unequal")
+		}
+	}
+	if *b.p != before {
+		t.Error("Equal modified its argument")
+	}
+
+	d := new(G1).Neg(a)
+	o := new(G1).SetIdentity()
+	for _, p := range []*G1{d, o, new(G1)} {
+		if a.Equal(p) || p.Equal(a) || a.ConstantTimeEqual(p) != 0 || p.ConstantTimeEqual(a) != 0 {
+			t.Error("unequal points compare equal")
+		}
+	}
+
+	// The zero value is the identity.
+	z := new(G1).Add(a, d)
+	for _, p := range []*G1{z, new(G1)} {
+		if !p.IsIdentity() || p.ConstantTimeIsIdentity() != 1 || !p.Equal(o) || o.ConstantTimeEqual(p) != 1 {
+			t.Error("identity not recognised")
+		}
+	}
+	if a.IsIdentity() || a.ConstantTimeIsIdentity() != 0 {
+		t.Error("a is the identity")
+	}
+}
+
+func TestG2Equal(t *testing.T) {
+	k, a, err := RandomG2(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	km1 := new(big.Int).Sub(k, big.NewInt(1))
+	b := new(G2).Add(new(G2).ScalarBaseMult(km1), new(G2).ScalarBaseMult(big.NewInt(1)))
+	c := new(G2)
+	if _, err := c.Unmarshal(a.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	before := *b.p
+
+	for _, p := range []*G2{a, b, c} {
+		if !a.Equal(p) || !p.Equal(a) || a.ConstantTimeEqual(p) != 1 {
+			t.Error("equal points compare unequal")
+		}
+	}
+	if *b.p != before {
+		t.Error("Equal modified its argument")
+	}
+
+	d := new(G2).Neg(a)
+	o := new(G2).SetIdentity()
+	for _, p := range []*G2{d, o, new(G2)} {
+		if a.Equal(p) || p.Equal(a) || a.ConstantTimeEqual(p) != 0 || p.ConstantTimeEqual(a) != 0 {
+			t.Error("unequal points compare equal")
+		}
+	}
+
+	// The zero value is the identity.
+	z := new(G2).Add(a, d)
+	for _, p := range []*G2{z, new(G2)} {
+		if !p.IsIdentity() || p.ConstantTimeIsIdentity() != 1 || !p.Equal(o) || o.ConstantTimeEqual(p) != 1 {
+			t.Error("identity not recognised")
+		}
+	}
+	if a.IsIdentity() || a.ConstantTimeIsIdentity() != 0 {
+		t.Error("a is the identity")
+	}
+}
+
+func TestGTEqual(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+
+	a := Pair(g1, g2)
+	b := new(GT).Set(a)
+	if !a.Equal(b) || a.ConstantTimeEqual(b) != 1 {
+		t.Error("equal elements compare unequal")
+	}
+	b.Neg(a)
+	if a.Equal(b) || a.ConstantTimeEqual(b) != 0 {
+		t.Error("unequal elements compare equal")
+	}
+
+	// The zero value is the identity.
+	o := new(GT).SetIdentity()
+	for _, p := range []*GT{o, new(GT).Add(a, b), new(GT)} {
+		if !p.IsIdentity() || p.ConstantTimeIsIdentity() != 1 || !p.Equal(o) || o.ConstantTimeEqual(p) != 1 {
+			t.Error("identity not recognised")
+		}
+	}
+	if a.Equal(new(GT)) || new(GT).ConstantTimeEqual(a) != 0 {
+		t.Error("unequal elements compare equal")
+	}
+	if a.IsIdentity() || a.ConstantTimeIsIdentity() != 0 {
+		t.Error("a is the identity")
+	}
+}
+
+func TestIsInSubgroup(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+
+	// (1, 3) is not on y²=x³+3, but (λ²x, λ³y, λ) is a Jacobian form of g1.
+	g1.p.MakeAffine()
+	lambda := newGFp(7)
+	x, y := &gfP{}, &gfP{}
+	gfpMul(x, &g1.p.x, lambda)
+	gfpMul(x, x, lambda)
+	gfpMul(y, &g1.p.y, lambda)
+	gfpMul(y, y, lambda)
+	gfpMul(y, y, lambda)
+	scaled := &G1{&curvePoint{x: *x, y: *y, z: *lambda}}
+	off := &G1{&curvePoint{x: *newGFp(1), y: *newGFp(3), z: *newGFp(1)}}
+	if !scaled.IsOnCurve() || !scaled.IsInSubgroup() || !scaled.Equal(g1) {
+		t.Error("Jacobian point rejected")
+	}
+	if off.IsOnCurve() || off.IsInSubgroup() {
+		t.Error("point off the curve accepted")
+	}
+	if !new(G1).IsOnCurve() || !new(G1).IsInSubgroup() {
+		t.Error("zero value of G1 rejected")
+	}
+
+	if !g2.IsOnCurve() || !g2.IsInSubgroup() || !new(G2).IsInSubgroup() {
+		t.Error("point in G₂ rejected")
+	}
+	// Almost every point of the twist lies outside G₂.
+	tw := &twistPoint{}
+	for i := int64(1); ; i++ {
+		tw.x = gfP2{*newGFp(0), *newGFp(i)}
+		y2 := (&gfP2{}).Square(&tw.x)
+		y2.Mul(y2, &tw.x).Add(y2, twistB)
+		if tw.y.Sqrt(y2) {
+			break
+		}
+	}
+	tw.z.SetOne()
+	tw.t.SetOne()
+	outside := &G2{tw}
+	if !outside.IsOnCurve() || outside.IsInSubgroup() {
+		t.Error("point of the twist outside G₂ misclassified")
+	}
+	tw.y.Add(&tw.y, &tw.z)
+	if outside.IsOnCurve() {
+		t.Error("point off the twist accepted")
+	}
+
+	gt := Pair(g1, g2)
+	if !gt.IsOnCurve() || !gt.IsInSubgroup() || !new(GT).SetIdentity().IsInSubgroup() ||
+		!new(GT).IsOnCurve() || !new(GT).IsInSubgroup() {
+		t.Error("element of GT rejected")
+	}
+	// The Miller loop output is not yet in the cyclotomic subgroup.
+	m := Miller(g1, g2)
+	if m.IsOnCurve() || m.IsInSubgroup() {
+		t.Error("Miller loop output accepted")
+	}
+}
+
+func BenchmarkG1Equal(b *testing.B) {
+	_, a, _ := RandomG1(rand.Reader)
+	c := new(G1).Set(a)
+	b.Run("Equal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			a.Equal(c)
+		}
+	})
+	b.Run("Marshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = string(a.Marshal()) == string(c.Marshal())
+		}
+	})
+}
@@ -0,0 +1,663 @@
+package bn256
+
+import (
+	"errors"
+	"math/big"
+)
+
+// This file exports the tower of fields over which the groups are defined:
+//
+//	Fp   = GF(p)
+//	Fp2  = Fp[i]/(i²+1)
+//	Fp6  = Fp2[τ]/(τ³-ξ), ξ = i+9
+//	Fp12 = Fp6[ω]/(ω²-τ)
+//
+// Elements are kept in Montgomery form internally; the conversions to and
+// from bytes and big.Int values take care of it. The zero value of each type
+// is zero. Like big.Int, operations set the receiver to their result and
+// return it, and their operands may alias it.
+//
+// The canonical encoding of an element of Fp is its 32-byte big-endian value,
+// which must be less than p. Larger fields are encoded as the concatenation
+// of their coefficients, highest degree first: b‖a for a+b·i, x‖y‖z for
+// xτ²+yτ+z and x‖y for xω+y. These are the encodings used by Marshal: a G₂
+// point is the encoding of its Fp2 coordinates, and a GT element that of an
+// Fp12 element.
+
+var errFieldEncoding = errors.New("bn256: invalid field element encoding")
+
+// Fp is an element of the base field GF(p).
+type Fp struct {
+	p gfP
+}
+
+// SetBig sets e to x mod p and then returns e.
+func (e *Fp) SetBig(x *big.Int) *Fp {
+	e.p = gfPFromBig(new(big.Int).Mod(x, P))
+	montEncode(&e.p, &e.p)
+	return e
+}
+
+// SetInt64 sets e to x mod p and then returns e.
+func (e *Fp) SetInt64(x int64) *Fp {
+	e.p = *newGFp(x)
+	return e
+}
+
+// Big returns the value of e as an integer in [0, p).
+func (e *Fp) Big() *big.Int {
+	return new(big.Int).SetBytes(e.Bytes())
+}
+
+// Bytes returns the canonical 32-byte big-endian encoding of e.
+func (e *Fp) Bytes() []byte {
+	ret := make([]byte, 32)
+	t := &gfP{}
+	montDecode(t, &e.p)
+	t.Marshal(ret)
+	return ret
+}
+
+// SetBytes sets e to the element encoded by the 32 bytes in b, which must be
+// less than p, and then returns e.
+func (e *Fp) SetBytes(b []byte) (*Fp, error) {
+	if len(b) != 32 {
+		return nil, errFieldEncoding
+	}
+	t := gfP{}
+	if err := t.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	montEncode(&e.p, &t)
+	return e, nil
+}
+
+func (e *Fp) String() string {
+	return e.Big().String()
+}
+
+// Set sets e to a and then returns e.
+func (e *Fp) Set(a *Fp) *Fp {
+	e.p.Set(&a.p)
+	return e
+}
+
+// SetZero sets e to zero and then returns e.
+func (e *Fp) SetZero() *Fp {
+	e.p = gfP{}
+	return e
+}
+
+// SetOne sets e to one and then returns e.
+func (e *Fp) SetOne() *Fp {
+	e.p = *newGFp(1)
+	return e
+}
+
+// IsZero reports whether e is zero.
+func (e *Fp) IsZero() bool {
+	return e.p == gfP{}
+}
+
+// IsOne reports whether e is one.
+func (e *Fp) IsOne() bool {
+	return e.p == *newGFp(1)
+}
+
+// Equal reports whether e and a are equal.
+func (e *Fp) Equal(a *Fp) bool {
+	return e.p == a.p
+}
+
+// Add sets e to a+b and then returns e.
+func (e *Fp) Add(a, b *Fp) *Fp {
+	gfpAdd(&e.p, &a.p, &b.p)
+	return e
+}
+
+// Sub sets e to a-b and then returns e.
+func (e *Fp) Sub(a, b *Fp) *Fp {
+	gfpSub(&e.p, &a.p, &b.p)
+	return e
+}
+
+// Neg sets e to -a and then returns e.
+func (e *Fp) Neg(a *Fp) *Fp {
+	gfpNeg(&e.p, &a.p)
+	return e
+}
+
+// Mul sets e to a·b and then returns e.
+func (e *Fp) Mul(a, b *Fp) *Fp {
+	gfpMul(&e.p, &a.p, &b.p)
+	return e
+}
+
+// Square sets e to a² and then returns e.
+func (e *Fp) Square(a *Fp) *Fp {
+	gfpMul(&e.p, &a.p, &a.p)
+	return e
+}
+
+// Invert sets e to 1/a, or zero if a is zero, and then returns e.
+func (e *Fp) Invert(a *Fp) *Fp {
+	e.p.Invert(&a.p)
+	return e
+}
+
+// Exp sets e to aᵏ and then returns e. Negative k invert a. The running time
+// depends on k.
+func (e *Fp) Exp(a *Fp, k *big.Int) *Fp {
+	sum, power := *newGFp(1), a.p
+	abs := new(big.Int).Abs(k)
+	for i := abs.BitLen() - 1; i >= 0; i-- {
+		gfpMul(&sum, &sum, &sum)
+		if abs.Bit(i) != 0 {
+			gfpMul(&sum, &sum, &power)
+		}
+	}
+	if k.Sign() < 0 {
+		sum.Invert(&sum)
+	}
+	e.p = sum
+	return e
+}
+
+// Sqrt sets e to a square root of a and then returns e. If a is not a square,
+// Sqrt returns nil and leaves e unchanged. Of the two roots, the one returned
+// is the one that is a square itself. It runs in constant time.
+func (e *Fp) Sqrt(a *Fp) *Fp {
+	r := &gfP{}
+	if !r.Sqrt(&a.p) {
+		return nil
+	}
+	e.p = *r
+	return e
+}
+
+// Legendre returns the Legendre symbol of e: 1 if e is a non-zero square, -1
+// if it is not a square and 0 if it is zero.
+func (e *Fp) Legendre() int {
+	return e.p.Legendre()
+}
+
+// Fp2 is an element a+b·i of GF(p²).
+type Fp2 struct {
+	p gfP2
+}
+
+// SetBig sets e to re + im·i, with re and im reduced mod p, and then returns
+// e.
+func (e *Fp2) SetBig(re, im *big.Int) *Fp2 {
+	var a, b Fp
+	return e.SetParts(a.SetBig(re), b.SetBig(im))
+}
+
+// Big returns the real and imaginary parts of e as integers in [0, p).
+func (e *Fp2) Big() (re, im *big.Int) {
+	return e.Real().Big(), e.Imag().Big()
+}
+
+// SetParts sets e to re + im·i and then returns e.
+func (e *Fp2) SetParts(re, im *Fp) *Fp2 {
+	e.p.y, e.p.x = re.p, im.p
+	return e
+}
+
+// Real returns the real part of e.
+func (e *Fp2) Real() *Fp {
+	return &Fp{e.p.y}
+}
+
+// Imag returns the imaginary part of e.
+func (e *Fp2) Imag() *Fp {
+	return &Fp{e.p.x}
+}
+
+// Bytes returns the canonical 64-byte encoding of e: the imaginary part
+// followed by the real part.
+func (e *Fp2) Bytes() []byte {
+	return append(e.Imag().Bytes(), e.Real().Bytes()...)
+}
+
+// SetBytes sets e to the element encoded by the 64 bytes in b and then
+// returns e.
+func (e *Fp2) SetBytes(b []byte) (*Fp2, error) {
+	if len(b) != 64 {
+		return nil, errFieldEncoding
+	}
+	var re, im Fp
+	if _, err := im.SetBytes(b[:32]); err != nil {
+		return nil, err
+	}
+	if _, err := re.SetBytes(b[32:]); err != nil {
+		return nil, err
+	}
+	return e.SetParts(&re, &im), nil
+}
+
+func (e *Fp2) String() string {
+	return "(" + e.Real().String() + " + " + e.Imag().String() + "·i)"
+}
+
+// Set sets e to a and then returns e.
+func (e *Fp2) Set(a *Fp2) *Fp2 {
+	e.p.Set(&a.p)
+	return e
+}
+
+// SetZero sets e to zero and then returns e.
+func (e *Fp2) SetZero() *Fp2 {
+	e.p.SetZero()
+	return e
+}
+
+// SetOne sets e to one and then returns e.
+func (e *Fp2) SetOne() *Fp2 {
+	e.p.SetOne()
+	return e
+}
+
+// IsZero reports whether e is zero.
+func (e *Fp2) IsZero() bool {
+	return e.p.IsZero()
+}
+
+// IsOne reports whether e is one.
+func (e *Fp2) IsOne() bool {
+	return e.p.IsOne()
+}
+
+// Equal reports whether e and a are equal.
+func (e *Fp2) Equal(a *Fp2) bool {
+	return e.p == a.p
+}
+
+// Add sets e to a+b and then returns e.
+func (e *Fp2) Add(a, b *Fp2) *Fp2 {
+	e.p.Add(&a.p, &b.p)
+	return e
+}
+
+// Sub sets e to a-b and then returns e.
+func (e *Fp2) Sub(a, b *Fp2) *Fp2 {
+	e.p.Sub(&a.p, &b.p)
+	return e
+}
+
+// Neg sets e to -a and then returns e.
+func (e *Fp2) Neg(a *Fp2) *Fp2 {
+	e.p.Neg(&a.p)
+	return e
+}
+
+// Mul sets e to a·b and then returns e.
+func (e *Fp2) Mul(a, b *Fp2) *Fp2 {
+	e.p.Mul(&a.p, &b.p)
+	return e
+}
+
+// MulFp sets e to a·b and then returns e.
+func (e *Fp2) MulFp(a *Fp2, b *Fp) *Fp2 {
+	e.p.MulScalar(&a.p, &b.p)
+	return e
+}
+
+// MulXi sets e to ξ·a, where ξ = i+9, and then returns e.
+func (e *Fp2) MulXi(a *Fp2) *Fp2 {
+	e.p.MulXi(&a.p)
+	return e
+}
+
+// Square sets e to a² and then returns e.
+func (e *Fp2) Square(a *Fp2) *Fp2 {
+	e.p.Square(&a.p)
+	return e
+}
+
+// Invert sets e to 1/a, or zero if a is zero, and then returns e.
+func (e *Fp2) Invert(a *Fp2) *Fp2 {
+	e.p.Invert(&a.p)
+	return e
+}
+
+// Conjugate sets e to the conjugate a-b·i of a = a+b·i and then returns e.
+func (e *Fp2) Conjugate(a *Fp2) *Fp2 {
+	e.p.Conjugate(&a.p)
+	return e
+}
+
+// Frobenius sets e to aᵖ, which is the conjugate of a, and then returns e.
+func (e *Fp2) Frobenius(a *Fp2) *Fp2 {
+	return e.Conjugate(a)
+}
+
+// Exp sets e to aᵏ and then returns e. Negative k invert a. The running time
+// depends on k.
+func (e *Fp2) Exp(a *Fp2, k *big.Int) *Fp2 {
+	sum, power := (&gfP2{}).SetOne(), (&gfP2{}).Set(&a.p)
+	abs := new(big.Int).Abs(k)
+	for i := abs.BitLen() - 1; i >= 0; i-- {
+		sum.Square(sum)
+		if abs.Bit(i) != 0 {
+			sum.Mul(sum, power)
+		}
+	}
+	if k.Sign() < 0 {
+		sum.Invert(sum)
+	}
+	e.p.Set(sum)
+	return e
+}
+
+// Sqrt sets e to a square root of a and then returns e. If a is not a square,
+// Sqrt returns nil and leaves e unchanged. Its running time depends on a.
+func (e *Fp2) Sqrt(a *Fp2) *Fp2 {
+	if !e.p.Sqrt(&a.p) {
+		return nil
+	}
+	return e
+}
+
+// Legendre returns 1 if e is a non-zero square, -1 if it is not a square and
+// 0 if it is zero.
+func (e *Fp2) Legendre() int {
+	return e.p.Legendre()
+}
+
+// Fp6 is an element xτ²+yτ+z of GF(p⁶).
+type Fp6 struct {
+	p gfP6
+}
+
+// SetParts sets e to xτ²+yτ+z and then returns e.
+func (e *Fp6) SetParts(x, y, z *Fp2) *Fp6 {
+	e.p.x, e.p.y, e.p.z = x.p, y.p, z.p
+	return e
+}
+
+// Parts returns the coefficients x, y and z of e = xτ²+yτ+z.
+func (e *Fp6) Parts() (x, y, z *Fp2) {
+	return &Fp2{e.p.x}, &Fp2{e.p.y}, &Fp2{e.p.z}
+}
+
+// Bytes returns the canonical 192-byte encoding of e.
+func (e *Fp6) Bytes() []byte {
+	x, y, z := e.Parts()
+	ret := append(x.Bytes(), y.Bytes()...)
+	return append(ret, z.Bytes()...)
+}
+
+// SetBytes sets e to the element encoded by the 192 bytes in b and then
+// returns e.
+func (e *Fp6) SetBytes(b []byte) (*Fp6, error) {
+	if len(b) != 192 {
+		return nil, errFieldEncoding
+	}
+	var x, y, z Fp2
+	for i, c := range []*Fp2{&x, &y, &z} {
+		if _, err := c.SetBytes(b[64*i : 64*(i+1)]); err != nil {
+			return nil, err
+		}
+	}
+	return e.SetParts(&x, &y, &z), nil
+}
+
+func (e *Fp6) String() string {
+	x, y, z := e.Parts()
+	return "(" + x.String() + "·τ² + " + y.String() + "·τ + " + z.String() + ")"
+}
+
+// Set sets e to a and then returns e.
+func (e *Fp6) Set(a *Fp6) *Fp6 {
+	e.p.Set(&a.p)
+	return e
+}
+
+// SetZero sets e to zero and then returns e.
+func (e *Fp6) SetZero() *Fp6 {
+	e.p.SetZero()
+	return e
+}
+
+// SetOne sets e to one and then returns e.
+func (e *Fp6) SetOne() *Fp6 {
+	e.p.SetOne()
+	return e
+}
+
+// IsZero reports whether e is zero.
+func (e *Fp6) IsZero() bool {
+	return e.p.IsZero()
+}
+
+// IsOne reports whether e is one.
+func (e *Fp6) IsOne() bool {
+	return e.p.IsOne()
+}
+
+// Equal reports whether e and a are equal.
+func (e *Fp6) Equal(a *Fp6) bool {
+	return e.p == a.p
+}
+
+// Add sets e to a+b and then returns e.
+func (e *Fp6) Add(a, b *Fp6) *Fp6 {
+	e.p.Add(&a.p, &b.p)
+	return e
+}
+
+// Sub sets e to a-b and then returns e.
+func (e *Fp6) Sub(a, b *Fp6) *Fp6 {
+	e.p.Sub(&a.p, &b.p)
+	return e
+}
+
+// Neg sets e to -a and then returns e.
+func (e *Fp6) Neg(a *Fp6) *Fp6 {
+	e.p.Neg(&a.p)
+	return e
+}
+
+// Mul sets e to a·b and then returns e.
+func (e *Fp6) Mul(a, b *Fp6) *Fp6 {
+	e.p.Mul(&a.p, &b.p)
+	return e
+}
+
+// MulFp2 sets e to a·b and then returns e.
+func (e *Fp6) MulFp2(a *Fp6, b *Fp2) *Fp6 {
+	e.p.MulScalar(&a.p, &b.p)
+	return e
+}
+
+// MulTau sets e to τ·a and then returns e.
+func (e *Fp6) MulTau(a *Fp6) *Fp6 {
+	e.p.MulTau(&a.p)
+	return e
+}
+
+// Square sets e to a² and then returns e.
+func (e *Fp6) Square(a *Fp6) *Fp6 {
+	e.p.Square(&a.p)
+	return e
+}
+
+// Invert sets e to 1/a, or zero if a is zero, and then returns e.
+func (e *Fp6) Invert(a *Fp6) *Fp6 {
+	e.p.Invert(&a.p)
+	return e
+}
+
+// Frobenius sets e to aᵖ and then returns e.
+func (e *Fp6) Frobenius(a *Fp6) *Fp6 {
+	e.p.Frobenius(&a.p)
+	return e
+}
+
+// FrobeniusP2 sets e to aᵖ² and then returns e.
+func (e *Fp6) FrobeniusP2(a *Fp6) *Fp6 {
+	e.p.FrobeniusP2(&a.p)
+	return e
+}
+
+// Exp sets e to aᵏ and then returns e. Negative k invert a. The running time
+// depends on k.
+func (e *Fp6) Exp(a *Fp6, k *big.Int) *Fp6 {
+	sum, power := (&gfP6{}).SetOne(), (&gfP6{}).Set(&a.p)
+	abs := new(big.Int).Abs(k)
+	for i := abs.BitLen() - 1; i >= 0; i-- {
+		sum.Square(sum)
+		if abs.Bit(i) != 0 {
+			sum.Mul(sum, power)
+		}
+	}
+	if k.Sign() < 0 {
+		sum.Invert(sum)
+	}
+	e.p.Set(sum)
+	return e
+}
+
+// Fp12 is an element xω+y of GF(p¹²). GT is a subgroup of its multiplicative
+// group, and the encoding of an element of GT by GT.Marshal is its encoding
+// as an element of Fp12.
+type Fp12 struct {
+	p gfP12
+}
+
+// SetParts sets e to xω+y and then returns e.
+func (e *Fp12) SetParts(x, y *Fp6) *Fp12 {
+	e.p.x, e.p.y = x.p, y.p
+	return e
+}
+
+// Parts returns the coefficients x and y of e = xω+y.
+func (e *Fp12) Parts() (x, y *Fp6) {
+	return &Fp6{e.p.x}, &Fp6{e.p.y}
+}
+
+// Bytes returns the canonical 384-byte encoding of e.
+func (e *Fp12) Bytes() []byte {
+	x, y := e.Parts()
+	return append(x.Bytes(), y.Bytes()...)
+}
+
+// SetBytes sets e to the element encoded by the 384 bytes in b and then
+// returns e.
+func (e *Fp12) SetBytes(b []byte) (*Fp12, error) {
+	if len(b) != 384 {
+		return nil, errFieldEncoding
+	}
+	var x, y Fp6
+	if _, err := x.SetBytes(b[:192]); err != nil {
+		return nil, err
+	}
+	if _, err := y.SetBytes(b[192:]); err != nil {
+		return nil, err
+	}
+	return e.SetParts(&x, &y), nil
+}
+
+func (e *Fp12) String() string {
+	x, y := e.Parts()
+	return "(" + x.String() + "·ω + " + y.String() + ")"
+}
+
+// Set sets e to a and then returns e.
+func (e *Fp12) Set(a *Fp12) *Fp12 {
+	e.p.Set(&a.p)
+	return e
+}
+
+// SetZero sets e to zero and then returns e.
+func (e *Fp12) SetZero() *Fp12 {
+	e.p.SetZero()
+	return e
+}
+
+// SetOne sets e to one and then returns e.
+func (e *Fp12) SetOne() *Fp12 {
+	e.p.SetOne()
+	return e
+}
+
+// IsZero reports whether e is zero.
+func (e *Fp12) IsZero() bool {
+	return e.p.IsZero()
+}
+
+// IsOne reports whether e is one.
+func (e *Fp12) IsOne() bool {
+	return e.p.IsOne()
+}
+
+// Equal reports whether e and a are equal.
+func (e *Fp12) Equal(a *Fp12) bool {
+	return e.p == a.p
+}
+
+// Add sets e to a+b and then returns e.
+func (e *Fp12) Add(a, b *Fp12) *Fp12 {
+	e.p.Add(&a.p, &b.p)
+	return e
+}
+
+// Sub sets e to a-b and then returns e.
+func (e *Fp12) Sub(a, b *Fp12) *Fp12 {
+	e.p.Sub(&a.p, &b.p)
+	return e
+}
+
+// Neg sets e to -a and then returns e.
+func (e *Fp12) Neg(a *Fp12) *Fp12 {
+	e.p.Neg(&a.p)
+	return e
+}
+
+// Mul sets e to a·b and then returns e.
+func (e *Fp12) Mul(a, b *Fp12) *Fp12 {
+	e.p.Mul(&a.p, &b.p)
+	return e
+}
+
+// Square sets e to a² and then returns e.
+func (e *Fp12) Square(a *Fp12) *Fp12 {
+	e.p.Square(&a.p)
+	return e
+}
+
+// Invert sets e to 1/a, or zero if a is zero, and then returns e.
+func (e *Fp12) Invert(a *Fp12) *Fp12 {
+	e.p.Invert(&a.p)
+	return e
+}
+
+// Conjugate sets e to -xω+y for a = xω+y, which is aᵖ⁶, and then returns e.
+// For elements of GT it is the inverse.
+func (e *Fp12) Conjugate(a *Fp12) *Fp12 {
+	e.p.Conjugate(&a.p)
+	return e
+}
+
+// Frobenius sets e to aᵖ and then returns e.
+func (e *Fp12) Frobenius(a *Fp12) *Fp12 {
+	e.p.Frobenius(&a.p)
+	return e
+}
+
+// FrobeniusP2 sets e to aᵖ² and then returns e.
+func (e *Fp12) FrobeniusP2(a *Fp12) *Fp12 {
+	e.p.FrobeniusP2(&a.p)
+	return e
+}
+
+// Exp sets e to aᵏ and then returns e. Negative k invert a. The running time
+// depends on k.
+func (e *Fp12) Exp(a *Fp12, k *big.Int) *Fp12 {
+	e.p.Exp(&a.p, new(big.Int).Abs(k))
+	if k.Sign() < 0 {
+		e.p.Invert(&e.p)
+	}
+	return e
+}
@@ -0,0 +1,238 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func randomFp(t *testing.T) *Fp {
+	t.Helper()
+
+	k, err := rand.Int(rand.Reader, P)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return new(Fp).SetBig(k)
+}
+
+func randomFp2(t *testing.T) *Fp2 {
+	return new(Fp2).SetParts(randomFp(t), randomFp(t))
+}
+
+func randomFp6(t *testing.T) *Fp6 {
+	return new(Fp6).SetParts(randomFp2(t), randomFp2(t), randomFp2(t))
+}
+
+func randomFp12(t *testing.T) *Fp12 {
+	return new(Fp12).SetParts(randomFp6(t), randomFp6(t))
+}
+
+func TestFpBig(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		a, b := randomFp(t), randomFp(t)
+		x, y := a.Big(), b.Big()
+
+		for _, tc := range []struct {
+			name string
+			got  *Fp
+			want *big.Int
+		}{
+			{"add", new(Fp).Add(a, b), new(big.Int).Add(x, y)},
+			{"sub", new(Fp).Sub(a, b), new(big.Int).Sub(x, y)},
+			{"neg", new(Fp).Neg(a), new(big.Int).Neg(x)},
+			{"mul", new(Fp).Mul(a, b), new(big.Int).Mul(x, y)},
+			{"square", new(Fp).Square(a), new(big.Int).Mul(x, x)},
+			{"invert", new(Fp).Invert(a), new(big.Int).ModInverse(x, P)},
+			{"exp", new(Fp).Exp(a, y), new(big.Int).Exp(x, y, P)},
+		} {
+			if tc.got.Big().Cmp(tc.want.Mod(tc.want, P)) != 0 {
+				t.Fatalf("%s: got %v, want %v", tc.name, tc.got, tc.want)
+			}
+		}
+	}
+
+	// SetBig reduces its input.
+	if !new(Fp).SetBig(new(big.Int).Add(P, big.NewInt(5))).Equal(new(Fp).SetInt64(5)) {
+		t.Fatal("SetBig does not reduce")
+	}
+	if new(Fp).SetInt64(-1).Big().Cmp(new(big.Int).Sub(P, big.NewInt(1))) != 0 {
+		t.Fatal("SetInt64(-1) is not p-1")
+	}
+	if !new(Fp).Exp(randomFp(t), new(big.Int)).IsOne() {
+		t.Fatal("a⁰ is not one")
+	}
+}
+
+func TestFpBytes(t *testing.T) {
+	a := randomFp(t)
+	b, err := new(Fp).SetBytes(a.Bytes())
+	if err != nil || !b.Equal(a) {
+		t.Fatal("round trip failed")
+	}
+
+	want := a.Big().Bytes()
+	want = append(make([]byte, 32-len(want)), want...)
+	if !bytes.Equal(a.Bytes(), want) {
+		t.Fatal("encoding is not big-endian")
+	}
+
+	pb := P.Bytes()
+	if _, err := new(Fp).SetBytes(pb); err == nil {
+		t.Fatal("p accepted")
+	}
+	if _, err := new(Fp).SetBytes(pb[1:]); err == nil {
+		t.Fatal("short encoding accepted")
+	}
+}
+
+func TestFpSqrt(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		a := randomFp(t)
+		sq := new(Fp).Square(a)
+		root := new(Fp).Sqrt(sq)
+		if root == nil || !new(Fp).Square(root).Equal(sq) {
+			t.Fatal("no root of a square")
+		}
+		if new(Fp).Sqrt(new(Fp).Neg(sq)) != nil {
+			t.Fatal("root of a non-square")
+		}
+	}
+}
+
+// TestFp2 checks the arithmetic of Fp2 against the definition over big.Int.
+func TestFp2(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		a, b := randomFp2(t), randomFp2(t)
+		a0, a1 := a.Big()
+		b0, b1 := b.Big()
+
+		// (a₀+a₁i)(b₀+b₁i) = a₀b₀-a₁b₁ + (a₀b₁+a₁b₀)i
+		re := new(big.Int).Sub(new(big.Int).Mul(a0, b0), new(big.Int).Mul(a1, b1))
+		im := new(big.Int).Add(new(big.Int).Mul(a0, b1), new(big.Int).Mul(a1, b0))
+		if !new(Fp2).Mul(a, b).Equal(new(Fp2).SetBig(re, im)) {
+			t.Fatal("mul mismatch")
+		}
+		if !new(Fp2).Square(a).Equal(new(Fp2).Mul(a, a)) {
+			t.Fatal("square mismatch")
+		}
+		if !new(Fp2).Mul(a, new(Fp2).Invert(a)).IsOne() {
+			t.Fatal("a·a⁻¹ is not one")
+		}
+		if !new(Fp2).Frobenius(a).Equal(new(Fp2).Exp(a, P)) {
+			t.Fatal("Frobenius is not the p-th power")
+		}
+
+		sq := new(Fp2).Square(a)
+		root := new(Fp2).Sqrt(sq)
+		if root == nil || !new(Fp2).Square(root).Equal(sq) {
+			t.Fatal("no root of a square")
+		}
+		// ξ is not a square, so neither is ξ·a².
+		if new(Fp2).Sqrt(new(Fp2).MulXi(sq)) != nil {
+			t.Fatal("root of a non-square")
+		}
+
+		c, err := new(Fp2).SetBytes(a.Bytes())
+		if err != nil || !c.Equal(a) {
+			t.Fatal("round trip failed")
+		}
+	}
+
+	// Roots of elements of Fp.
+	for _, x := range []int64{0, 4, -4, 3, -3} {
+		a := new(Fp2).SetParts(new(Fp).SetInt64(x), new(Fp))
+		root := new(Fp2).Sqrt(a)
+		if root == nil || !new(Fp2).Square(root).Equal(a) {
+			t.Fatalf("no root of %d", x)
+		}
+	}
+}
+
+func TestFp6Fp12(t *testing.T) {
+	a, b := randomFp6(t), randomFp6(t)
+	if !new(Fp6).Mul(a, new(Fp6).Invert(a)).IsOne() {
+		t.Fatal("a·a⁻¹ is not one in Fp6")
+	}
+	if !new(Fp6).Square(a).Equal(new(Fp6).Mul(a, a)) {
+		t.Fatal("square mismatch in Fp6")
+	}
+	if !new(Fp6).Frobenius(a).Equal(new(Fp6).Exp(a, P)) {
+		t.Fatal("Frobenius is not the p-th power in Fp6")
+	}
+	pSquared := new(big.Int).Mul(P, P)
+	if !new(Fp6).FrobeniusP2(a).Equal(new(Fp6).Frobenius(new(Fp6).Frobenius(a))) {
+		t.Fatal("FrobeniusP2 mismatch in Fp6")
+	}
+	// The Frobenius map is a field homomorphism.
+	ab := new(Fp6).Mul(a, b)
+	if !new(Fp6).Frobenius(ab).Equal(new(Fp6).Mul(new(Fp6).Frobenius(a), new(Fp6).Frobenius(b))) {
+		t.Fatal("Frobenius is not multiplicative in Fp6")
+	}
+
+	c := randomFp12(t)
+	if !new(Fp12).Mul(c, new(Fp12).Invert(c)).IsOne() {
+		t.Fatal("c·c⁻¹ is not one in Fp12")
+	}
+	if !new(Fp12).Frobenius(c).Equal(new(Fp12).Exp(c, P)) {
+		t.Fatal("Frobenius is not the p-th power in Fp12")
+	}
+	if !new(Fp12).FrobeniusP2(c).Equal(new(Fp12).Exp(c, pSquared)) {
+		t.Fatal("FrobeniusP2 is not the p²-th power in Fp12")
+	}
+	if !new(Fp12).Mul(new(Fp12).Exp(c, big.NewInt(-3)), new(Fp12).Exp(c, big.NewInt(3))).IsOne() {
+		t.Fatal("negative exponent is not the inverse")
+	}
+
+	d, err := new(Fp12).SetBytes(c.Bytes())
+	if err != nil || !d.Equal(c) {
+		t.Fatal("round trip failed")
+	}
+}
+
+// TestFp12GT checks that elements of GT encode as elements of Fp12.
+func TestFp12GT(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	_, g2, _ := RandomG2(rand.Reader)
+	gt := Pair(g1, g2)
+
+	e, err := new(Fp12).SetBytes(gt.Marshal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(e.Bytes(), gt.Marshal()) {
+		t.Fatal("encodings differ")
+	}
+	if !new(Fp12).Exp(e, Order).IsOne() {
+		t.Fatal("element of GT does not have order dividing Order")
+	}
+	inv := new(GT).Neg(gt)
+	if !bytes.Equal(new(Fp12).Conjugate(e).Bytes(), inv.Marshal()) {
+		t.Fatal("conjugate is not the inverse in GT")
+	}
+}
+
+// TestFp2G2 checks that G₂ points encode as pairs of elements of Fp2 on the
+// twist y² = x³ + 3/ξ.
+func TestFp2G2(t *testing.T) {
+	_, g2, _ := RandomG2(rand.Reader)
+	m := g2.Marshal()
+	x, err := new(Fp2).SetBytes(m[:64])
+	if err != nil {
+		t.Fatal(err)
+	}
+	y, err := new(Fp2).SetBytes(m[64:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xi := new(Fp2).SetParts(new(Fp).SetInt64(9), new(Fp).SetInt64(1))
+	b := new(Fp2).SetParts(new(Fp).SetInt64(3), new(Fp))
+	b.Mul(b, new(Fp2).Invert(xi))
+	rhs := new(Fp2).Square(x)
+	rhs.Mul(rhs, x).Add(rhs, b)
+	if !new(Fp2).Square(y).Equal(rhs) {
+		t.Fatal("G₂ point is not on the twist")
+	}
+}
@@ -1,6 +1,8 @@
 package bn256
 
 import (
+	"crypto/rand"
+	"math/big"
 	"testing"
 )
 
@@ -58,3 +60,54 @@ func TestGFpMul(t *testing.T) {
 		t.Errorf("multiplication mismatch: have %#x, want %#x", *h, *w)
 	}
 }
+
+// Tests that the safegcd inversion agrees with exponentiation to the power p-2.
+func TestGFpInvert(t *testing.T) {
+	var values []*big.Int
+	for i := int64(0); i < 64; i++ {
+		values = append(values, big.NewInt(i), new(big.Int).Sub(P, big.NewInt(i+1)))
+		values = append(values, new(big.Int).Lsh(big.NewInt(1), uint(4*i)))
+	}
+	for i := 0; i < 10000; i++ {
+		k, err := rand.Int(rand.Reader, P)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, k)
+	}
+
+	for _, x := range values {
+		a := gfPFromBig(x)
+		have, want := &gfP{}, &gfP{}
+		have.invertSafegcd(&a)
+		want.invertFermat(&a)
+		if *have != *want {
+			t.Fatalf("inversion mismatch for %v: have %v, want %v", x, have, want)
+		}
+	}
+
+	// The receiver may alias the argument.
+	a := gfPFromBig(big.NewInt(7))
+	want := &gfP{}
+	want.invertFermat(&a)
+	a.invertSafegcd(&a)
+	if a != *want {
+		t.Errorf("aliased inversion mismatch: have %v, want %v", &a, want)
+	}
+}
+
+func BenchmarkGFpInvert(b *testing.B) {
+	x, _ := rand.Int(rand.Reader, P)
+	a, e := gfPFromBig(x), &gfP{}
+
+	b.Run("safegcd", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.invertSafegcd(&a)
+		}
+	})
+	b.Run("fermat", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			e.invertFermat(&a)
+		}
+	})
+}
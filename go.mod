@@ -4,5 +4,6 @@ go 1.12
 
 require (
 	github.com/stretchr/testify v1.3.0
+	golang.org/x/crypto v0.0.0-20190513172903-22d7a77e9e5f
 	golang.org/x/sys v0.0.0-20190516110030-61b9204099cb
 )
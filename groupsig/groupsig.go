@@ -0,0 +1,383 @@
+// Package groupsig implements the short group signatures of Boneh, Boyen and
+// Shacham (BBS04).
+//
+// A member of a group can sign messages on behalf of the group. Anybody can
+// check a signature against the group public key, but only the group manager,
+// who holds the opening key, can tell which member produced it.
+//
+// The group public key is (h, u, v, w) with h, u, v ∈ G₁ and w = γ·g₂. The
+// issuer holds γ and gives each member a key (A, x) with A = (1/(γ+x))·g₁. The
+// opening key is (ξ₁, ξ₂) with ξ₁·u = ξ₂·v = h, so that a signature, which
+// contains the linear encryption
+//
+//	T₁ = α·u, T₂ = β·v, T₃ = A + (α+β)·h
+//
+// of A, can be opened by computing A = T₃ - ξ₁·T₁ - ξ₂·T₂. The rest of the
+// signature proves knowledge of x and of the α and β used, and that T₃
+// encrypts a valid member key.
+//
+// See Boneh, Boyen and Shacham, "Short Group Signatures",
+// https://crypto.stanford.edu/~dabo/pubs/papers/groupsigs.pdf.
+package groupsig
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/transcript"
+)
+
+// challengeDomain labels the Fiat-Shamir transcript of a signature.
+const challengeDomain = "bn256/groupsig/challenge"
+
+var (
+	errInvalid   = errors.New("groupsig: invalid signature")
+	errMalformed = errors.New("groupsig: malformed input")
+	errKey       = errors.New("groupsig: invalid member key")
+)
+
+// PublicKey is a group public key.
+type PublicKey struct {
+	H, U, V *bn256.G1
+	W       *bn256.G2
+}
+
+// IssuerKey is the secret key that is used to issue member keys.
+type IssuerKey struct {
+	Gamma *big.Int
+}
+
+// OpeningKey is the secret key that is used to open signatures.
+type OpeningKey struct {
+	Xi1, Xi2 *big.Int
+}
+
+// MemberKey is the secret key of a group member. A identifies the member:
+// it is what Open returns for signatures made with this key.
+type MemberKey struct {
+	A *bn256.G1
+	X *big.Int
+}
+
+// GenerateGroup returns the public key of a new group and the keys of its
+// issuer and manager, using randomness from r.
+func GenerateGroup(r io.Reader) (*PublicKey, *IssuerKey, *OpeningKey, error) {
+	ks, err := randomNonZero(r, 4)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	h, xi1, xi2, gamma := ks[0], ks[1], ks[2], ks[3]
+
+	u := new(big.Int).ModInverse(xi1, bn256.Order)
+	v := new(big.Int).ModInverse(xi2, bn256.Order)
+	pk := &PublicKey{
+		H: new(bn256.G1).ScalarBaseMult(h),
+		U: new(bn256.G1).ScalarBaseMult(mul(h, u)),
+		V: new(bn256.G1).ScalarBaseMult(mul(h, v)),
+		W: new(bn256.G2).ScalarBaseMult(gamma),
+	}
+	return pk, &IssuerKey{gamma}, &OpeningKey{xi1, xi2}, nil
+}
+
+// Issue returns a new member key using randomness from r.
+func (ik *IssuerKey) Issue(r io.Reader) (*MemberKey, error) {
+	for {
+		x, err := rand.Int(r, bn256.Order)
+		if err != nil {
+			return nil, err
+		}
+		e := add(ik.Gamma, x)
+		if e.Sign() == 0 {
+			continue
+		}
+		A := new(bn256.G1).ScalarBaseMult(e.ModInverse(e, bn256.Order))
+		return &MemberKey{A, x}, nil
+	}
+}
+
+// Verify returns nil iff mk is a member key of the group with public key pk.
+func (mk *MemberKey) Verify(pk *PublicKey) error {
+	if mk.A == nil || mk.X == nil || isIdentity(mk.A.Marshal()) {
+		return errKey
+	}
+
+	// e(A, w + x·g₂) = e(g₁, g₂)
+	g1 := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	wx := new(bn256.G2).ScalarBaseMult(mk.X)
+	wx.Add(wx, pk.W)
+	if !bn256.PairingCheck([]*bn256.G1{mk.A, g1.Neg(g1)}, []*bn256.G2{wx, new(bn256.G2).ScalarBaseMult(big.NewInt(1))}) {
+		return errKey
+	}
+	return nil
+}
+
+// Signature is a group signature.
+type Signature struct {
+	T1, T2, T3                          *bn256.G1
+	C                                   *big.Int
+	SAlpha, SBeta, SX, SDelta1, SDelta2 *big.Int
+}
+
+// Sign returns a signature of msg by the member with key mk of the group with
+// public key pk, using randomness from r.
+func Sign(r io.Reader, pk *PublicKey, mk *MemberKey, msg []byte) (*Signature, error) {
+	ks, err := randomNonZero(r, 7)
+	if err != nil {
+		return nil, err
+	}
+	alpha, beta := ks[0], ks[1]
+	rAlpha, rBeta, rX, rDelta1, rDelta2 := ks[2], ks[3], ks[4], ks[5], ks[6]
+	delta1, delta2 := mul(mk.X, alpha), mul(mk.X, beta)
+
+	T1 := new(bn256.G1).ScalarMult(pk.U, alpha)
+	T2 := new(bn256.G1).ScalarMult(pk.V, beta)
+	T3 := new(bn256.G1).ScalarMult(pk.H, add(alpha, beta))
+	T3.Add(T3, mk.A)
+
+	R1 := new(bn256.G1).ScalarMult(pk.U, rAlpha)
+	R2 := new(bn256.G1).ScalarMult(pk.V, rBeta)
+	R3 := computeR3(pk, T3, new(big.Int), rAlpha, rBeta, rX, rDelta1, rDelta2)
+	R4 := new(bn256.G1).MultiScalarMult([]*bn256.G1{T1, pk.U}, []*big.Int{rX, neg(rDelta1)})
+	R5 := new(bn256.G1).MultiScalarMult([]*bn256.G1{T2, pk.V}, []*big.Int{rX, neg(rDelta2)})
+
+	c := challenge(pk, msg, T1, T2, T3, R1, R2, R3, R4, R5)
+	return &Signature{
+		T1: T1, T2: T2, T3: T3,
+		C:       c,
+		SAlpha:  add(rAlpha, mul(c, alpha)),
+		SBeta:   add(rBeta, mul(c, beta)),
+		SX:      add(rX, mul(c, mk.X)),
+		SDelta1: add(rDelta1, mul(c, delta1)),
+		SDelta2: add(rDelta2, mul(c, delta2)),
+	}, nil
+}
+
+// Verify returns nil iff sig is a signature of msg by a member of the group
+// with public key pk.
+func Verify(pk *PublicKey, sig *Signature, msg []byte) error {
+	if sig.T1 == nil || sig.T2 == nil || sig.T3 == nil {
+		return errMalformed
+	}
+	for _, k := range []*big.Int{sig.C, sig.SAlpha, sig.SBeta, sig.SX, sig.SDelta1, sig.SDelta2} {
+		if k == nil {
+			return errMalformed
+		}
+	}
+	negC := neg(sig.C)
+
+	R1 := new(bn256.G1).MultiScalarMult([]*bn256.G1{pk.U, sig.T1}, []*big.Int{sig.SAlpha, negC})
+	R2 := new(bn256.G1).MultiScalarMult([]*bn256.G1{pk.V, sig.T2}, []*big.Int{sig.SBeta, negC})
+	R3 := computeR3(pk, sig.T3, sig.C, sig.SAlpha, sig.SBeta, sig.SX, sig.SDelta1, sig.SDelta2)
+	R4 := new(bn256.G1).MultiScalarMult([]*bn256.G1{sig.T1, pk.U}, []*big.Int{sig.SX, neg(sig.SDelta1)})
+	R5 := new(bn256.G1).MultiScalarMult([]*bn256.G1{sig.T2, pk.V}, []*big.Int{sig.SX, neg(sig.SDelta2)})
+
+	if challenge(pk, msg, sig.T1, sig.T2, sig.T3, R1, R2, R3, R4, R5).Cmp(sig.C) != 0 {
+		return errInvalid
+	}
+	return nil
+}
+
+// Open returns the A of the member key that produced sig, after checking that
+// sig is a valid signature of msg.
+func Open(pk *PublicKey, ok *OpeningKey, sig *Signature, msg []byte) (*bn256.G1, error) {
+	if err := Verify(pk, sig, msg); err != nil {
+		return nil, err
+	}
+	// A = T₃ - ξ₁·T₁ - ξ₂·T₂
+	return new(bn256.G1).MultiScalarMult(
+		[]*bn256.G1{sig.T3, sig.T1, sig.T2},
+		[]*big.Int{big.NewInt(1), neg(ok.Xi1), neg(ok.Xi2)},
+	), nil
+}
+
+// computeR3 returns
+//
+//	e(T₃, g₂)^sx · e(h, w)^-(sα+sβ) · e(h, g₂)^-(sδ₁+sδ₂) · (e(T₃, w) / e(g₁, g₂))^c
+//
+// as
+//
+//	e(T₃, sx·g₂ + c·w) · e(h, -(sα+sβ)·w - (sδ₁+sδ₂)·g₂) · e(g₁, g₂)^-c
+//
+// The signer computes R₃ from its blinding values with c = 0.
+func computeR3(pk *PublicKey, T3 *bn256.G1, c, sAlpha, sBeta, sX, sDelta1, sDelta2 *big.Int) *bn256.GT {
+	a := new(bn256.G2).ScalarBaseMult(sX)
+	a.Add(a, new(bn256.G2).ScalarMult(pk.W, c))
+	b := new(bn256.G2).ScalarMult(pk.W, neg(add(sAlpha, sBeta)))
+	b.Add(b, new(bn256.G2).ScalarBaseMult(neg(add(sDelta1, sDelta2))))
+
+	R3 := bn256.Pair(T3, a)
+	R3.Add(R3, bn256.Pair(pk.H, b))
+	if c.Sign() != 0 {
+		g1 := new(bn256.G1).ScalarBaseMult(neg(c))
+		R3.Add(R3, bn256.Pair(g1, new(bn256.G2).ScalarBaseMult(big.NewInt(1))))
+	}
+	return R3
+}
+
+// challenge returns the Fiat-Shamir challenge of a signature.
+func challenge(pk *PublicKey, msg []byte, T1, T2, T3, R1, R2 *bn256.G1, R3 *bn256.GT, R4, R5 *bn256.G1) *big.Int {
+	t := transcript.New(challengeDomain)
+	t.AppendG1("h", pk.H)
+	t.AppendG1("u", pk.U)
+	t.AppendG1("v", pk.V)
+	t.AppendG2("w", pk.W)
+	t.AppendMessage("msg", msg)
+	t.AppendG1("T1", T1)
+	t.AppendG1("T2", T2)
+	t.AppendG1("T3", T3)
+	t.AppendG1("R1", R1)
+	t.AppendG1("R2", R2)
+	t.AppendGT("R3", R3)
+	t.AppendG1("R4", R4)
+	t.AppendG1("R5", R5)
+	return t.ChallengeScalar("c")
+}
+
+// Marshal converts pk into a byte slice: h, u and v followed by w.
+func (pk *PublicKey) Marshal() []byte {
+	ret := append(pk.H.Marshal(), pk.U.Marshal()...)
+	ret = append(ret, pk.V.Marshal()...)
+	return append(ret, pk.W.Marshal()...)
+}
+
+// Unmarshal sets pk to the result of converting the output of Marshal back
+// into a public key and then returns the remainder of m.
+func (pk *PublicKey) Unmarshal(m []byte) ([]byte, error) {
+	points, m, err := readG1(m, 3)
+	if err != nil {
+		return nil, err
+	}
+	W := new(bn256.G2)
+	if m, err = W.Unmarshal(m); err != nil {
+		return nil, err
+	}
+	pk.H, pk.U, pk.V, pk.W = points[0], points[1], points[2], W
+	return m, nil
+}
+
+// Marshal converts mk into a byte slice.
+func (mk *MemberKey) Marshal() []byte {
+	return appendScalar(mk.A.Marshal(), mk.X)
+}
+
+// Unmarshal sets mk to the result of converting the output of Marshal back
+// into a member key and then returns the remainder of m.
+func (mk *MemberKey) Unmarshal(m []byte) ([]byte, error) {
+	points, m, err := readG1(m, 1)
+	if err != nil {
+		return nil, err
+	}
+	x, m, err := readScalar(m)
+	if err != nil {
+		return nil, err
+	}
+	mk.A, mk.X = points[0], x
+	return m, nil
+}
+
+// Marshal converts sig into a byte slice: T₁, T₂ and T₃ followed by c, sα,
+// sβ, sx, sδ₁ and sδ₂.
+func (sig *Signature) Marshal() []byte {
+	var ret []byte
+	for _, p := range []*bn256.G1{sig.T1, sig.T2, sig.T3} {
+		ret = append(ret, p.Marshal()...)
+	}
+	for _, k := range []*big.Int{sig.C, sig.SAlpha, sig.SBeta, sig.SX, sig.SDelta1, sig.SDelta2} {
+		ret = appendScalar(ret, k)
+	}
+	return ret
+}
+
+// Unmarshal sets sig to the result of converting the output of Marshal back
+// into a signature and then returns the remainder of m.
+func (sig *Signature) Unmarshal(m []byte) ([]byte, error) {
+	points, m, err := readG1(m, 3)
+	if err != nil {
+		return nil, err
+	}
+	scalars := make([]*big.Int, 6)
+	for i := range scalars {
+		if scalars[i], m, err = readScalar(m); err != nil {
+			return nil, err
+		}
+	}
+	sig.T1, sig.T2, sig.T3 = points[0], points[1], points[2]
+	sig.C, sig.SAlpha, sig.SBeta, sig.SX, sig.SDelta1, sig.SDelta2 =
+		scalars[0], scalars[1], scalars[2], scalars[3], scalars[4], scalars[5]
+	return m, nil
+}
+
+// readG1 reads n points of G₁ from m and returns them and the rest of m.
+func readG1(m []byte, n int) ([]*bn256.G1, []byte, error) {
+	points := make([]*bn256.G1, n)
+	var err error
+	for i := range points {
+		points[i] = new(bn256.G1)
+		if m, err = points[i].Unmarshal(m); err != nil {
+			return nil, nil, err
+		}
+	}
+	return points, m, nil
+}
+
+// randomNonZero returns n random non-zero scalars read from r.
+func randomNonZero(r io.Reader, n int) ([]*big.Int, error) {
+	ret := make([]*big.Int, 0, n)
+	for len(ret) < n {
+		k, err := rand.Int(r, bn256.Order)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() != 0 {
+			ret = append(ret, k)
+		}
+	}
+	return ret, nil
+}
+
+// appendScalar appends the 32-byte big-endian encoding of k mod Order to b.
+func appendScalar(b []byte, k *big.Int) []byte {
+	r := new(big.Int).Mod(k, bn256.Order).Bytes()
+	b = append(b, make([]byte, 32-len(r))...)
+	return append(b, r...)
+}
+
+// readScalar reads a canonical scalar from m and returns it and the rest of m.
+func readScalar(m []byte) (*big.Int, []byte, error) {
+	if len(m) < 32 {
+		return nil, nil, errMalformed
+	}
+	k := new(big.Int).SetBytes(m[:32])
+	if k.Cmp(bn256.Order) >= 0 {
+		return nil, nil, errMalformed
+	}
+	return k, m[32:], nil
+}
+
+// isIdentity returns true iff m is the encoding of the identity.
+func isIdentity(m []byte) bool {
+	for _, b := range m {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func add(a, b *big.Int) *big.Int {
+	c := new(big.Int).Add(a, b)
+	return c.Mod(c, bn256.Order)
+}
+
+func mul(a, b *big.Int) *big.Int {
+	c := new(big.Int).Mul(a, b)
+	return c.Mod(c, bn256.Order)
+}
+
+func neg(a *big.Int) *big.Int {
+	c := new(big.Int).Neg(a)
+	return c.Mod(c, bn256.Order)
+}
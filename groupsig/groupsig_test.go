@@ -0,0 +1,161 @@
+package groupsig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+type group struct {
+	pk      *PublicKey
+	ok      *OpeningKey
+	members []*MemberKey
+}
+
+func newGroup(t *testing.T, n int) *group {
+	t.Helper()
+
+	pk, ik, ok, err := GenerateGroup(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &group{pk: pk, ok: ok}
+	for i := 0; i < n; i++ {
+		mk, err := ik.Issue(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := mk.Verify(pk); err != nil {
+			t.Fatal(err)
+		}
+		g.members = append(g.members, mk)
+	}
+	return g
+}
+
+func TestSignVerifyOpen(t *testing.T) {
+	g := newGroup(t, 3)
+	msg := []byte("report #42")
+
+	for i, mk := range g.members {
+		sig, err := Sign(rand.Reader, g.pk, mk, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(g.pk, sig, msg); err != nil {
+			t.Fatalf("member %d: %v", i, err)
+		}
+		if err := Verify(g.pk, sig, []byte("report #43")); err == nil {
+			t.Fatal("signature accepted for another message")
+		}
+
+		A, err := Open(g.pk, g.ok, sig, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, other := range g.members {
+			if bytes.Equal(A.Marshal(), other.A.Marshal()) != (i == j) {
+				t.Fatalf("signature of member %d opened as member %d", i, j)
+			}
+		}
+	}
+
+	other := newGroup(t, 0)
+	sig, _ := Sign(rand.Reader, g.pk, g.members[0], msg)
+	if err := Verify(other.pk, sig, msg); err == nil {
+		t.Fatal("signature accepted under another group")
+	}
+}
+
+func TestUnlinkable(t *testing.T) {
+	g := newGroup(t, 1)
+	a, _ := Sign(rand.Reader, g.pk, g.members[0], nil)
+	b, _ := Sign(rand.Reader, g.pk, g.members[0], nil)
+	for i, p := range [][2]*bn256.G1{{a.T1, b.T1}, {a.T2, b.T2}, {a.T3, b.T3}} {
+		if bytes.Equal(p[0].Marshal(), p[1].Marshal()) {
+			t.Fatalf("signatures share T%d", i+1)
+		}
+	}
+}
+
+func TestInvalidMemberKey(t *testing.T) {
+	g := newGroup(t, 1)
+	mk := g.members[0]
+	forged := &MemberKey{mk.A, add(mk.X, big.NewInt(1))}
+	if err := forged.Verify(g.pk); err == nil {
+		t.Fatal("forged member key accepted")
+	}
+
+	// A signature with a key that was not issued does not verify.
+	sig, _ := Sign(rand.Reader, g.pk, forged, nil)
+	if err := Verify(g.pk, sig, nil); err == nil {
+		t.Fatal("signature with forged key accepted")
+	}
+}
+
+func TestTampered(t *testing.T) {
+	g := newGroup(t, 1)
+	sig, _ := Sign(rand.Reader, g.pk, g.members[0], nil)
+	one := big.NewInt(1)
+	g1 := new(bn256.G1).ScalarBaseMult(one)
+
+	for i, f := range []func(s *Signature){
+		func(s *Signature) { s.T1 = new(bn256.G1).Add(s.T1, g1) },
+		func(s *Signature) { s.T2 = new(bn256.G1).Add(s.T2, g1) },
+		func(s *Signature) { s.T3 = new(bn256.G1).Add(s.T3, g1) },
+		func(s *Signature) { s.C = add(s.C, one) },
+		func(s *Signature) { s.SAlpha = add(s.SAlpha, one) },
+		func(s *Signature) { s.SBeta = add(s.SBeta, one) },
+		func(s *Signature) { s.SX = add(s.SX, one) },
+		func(s *Signature) { s.SDelta1 = add(s.SDelta1, one) },
+		func(s *Signature) { s.SDelta2 = add(s.SDelta2, one) },
+	} {
+		s := new(Signature)
+		if _, err := s.Unmarshal(sig.Marshal()); err != nil {
+			t.Fatal(err)
+		}
+		f(s)
+		if err := Verify(g.pk, s, nil); err == nil {
+			t.Errorf("tampered signature %d accepted", i)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	g := newGroup(t, 1)
+	msg := []byte("hello")
+
+	pk := new(PublicKey)
+	if rest, err := pk.Unmarshal(g.pk.Marshal()); err != nil || len(rest) != 0 {
+		t.Fatal("public key round trip failed")
+	}
+	mk := new(MemberKey)
+	if rest, err := mk.Unmarshal(g.members[0].Marshal()); err != nil || len(rest) != 0 {
+		t.Fatal("member key round trip failed")
+	}
+	sig, _ := Sign(rand.Reader, pk, mk, msg)
+	decoded := new(Signature)
+	if rest, err := decoded.Unmarshal(sig.Marshal()); err != nil || len(rest) != 0 {
+		t.Fatal("signature round trip failed")
+	}
+	if err := Verify(g.pk, decoded, msg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decoded.Unmarshal(sig.Marshal()[:3*64+5*32]); err == nil {
+		t.Fatal("truncated signature accepted")
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	pk, ik, _, _ := GenerateGroup(rand.Reader)
+	mk, _ := ik.Issue(rand.Reader)
+	sig, _ := Sign(rand.Reader, pk, mk, nil)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Verify(pk, sig, nil)
+	}
+}
@@ -0,0 +1,85 @@
+package bn256
+
+import (
+	"crypto/sha256"
+	"math/big"
+)
+
+// HashToG1 hashes msg to a point of G₁ using the try-and-increment method.
+// The domain separation tag dst allows different protocols to derive
+// independent points from the same messages; it should be unique to each use.
+//
+// Candidate x-coordinates are derived from SHA-256 digests of dst, msg and a
+// counter until one lies on the curve, so the time taken depends on msg. The
+// discrete logarithm of the result with respect to any other point is unknown,
+// which makes it suitable for deriving independent generators.
+func HashToG1(msg, dst []byte) *G1 {
+	if len(dst) > 255 {
+		panic("bn256: domain separation tag too long")
+	}
+
+	xb := new(big.Int)
+	x, y, rhs := &gfP{}, &gfP{}, &gfP{}
+	for ctr := 0; ; ctr++ {
+		digest := hashToFieldDigest(msg, dst, ctr)
+
+		xb.SetBytes(digest[:64])
+		*x = gfPFromBig(xb.Mod(xb, P))
+		montEncode(x, x)
+
+		// rhs = x³+3
+		gfpMul(rhs, x, x)
+		gfpMul(rhs, rhs, x)
+		gfpAdd(rhs, rhs, curveB)
+		if !y.Sqrt(rhs) {
+			continue
+		}
+
+		yd := &gfP{}
+		montDecode(yd, y)
+		if yd[0]&1 != uint64(digest[64]&1) {
+			gfpNeg(y, y)
+		}
+		return newG1(x, y)
+	}
+}
+
+// hashToFieldDigest returns 65 bytes derived from msg, dst and ctr. The first
+// 64 bytes are reduced to give a uniform candidate x-coordinate and the last
+// selects the sign of y.
+func hashToFieldDigest(msg, dst []byte, ctr int) []byte {
+	out := make([]byte, 0, 3*sha256.Size)
+	for i := 0; i < 3; i++ {
+		h := sha256.New()
+		h.Write([]byte{byte(len(dst))})
+		h.Write(dst)
+		h.Write([]byte{byte(ctr >> 24), byte(ctr >> 16), byte(ctr >> 8), byte(ctr), byte(i)})
+		h.Write(msg)
+		out = h.Sum(out)
+	}
+	return out[:65]
+}
+
+// newG1 returns the point (x, y), which must be on the curve and in
+// Montgomery form.
+func newG1(x, y *gfP) *G1 {
+	e := &G1{&curvePoint{x: *x, y: *y}}
+	e.p.z = *newGFp(1)
+	e.p.t = *newGFp(1)
+	return e
+}
+
+// gfPFromBig returns x, which must be less than P, as an element of gfP. The
+// result is not in Montgomery form.
+func gfPFromBig(x *big.Int) gfP {
+	// Each value is a 256-bit number.
+	const numBytes = 256 / 8
+
+	buf := make([]byte, numBytes)
+	b := x.Bytes()
+	copy(buf[numBytes-len(b):], b)
+
+	out := gfP{}
+	out.Unmarshal(buf)
+	return out
+}
@@ -0,0 +1,33 @@
+package bn256
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHashToG1(t *testing.T) {
+	dst := []byte("bn256 test")
+	seen := make(map[string]bool)
+
+	for i := 0; i < 32; i++ {
+		msg := []byte{byte(i)}
+		h := HashToG1(msg, dst)
+		if !h.p.IsOnCurve() {
+			t.Fatalf("%d: point not on curve", i)
+		}
+		m := h.Marshal()
+		if !bytes.Equal(m, HashToG1(msg, dst).Marshal()) {
+			t.Fatalf("%d: hash is not deterministic", i)
+		}
+		if seen[string(m)] {
+			t.Fatalf("%d: collision", i)
+		}
+		seen[string(m)] = true
+	}
+
+	a := HashToG1([]byte("msg"), []byte("a")).Marshal()
+	b := HashToG1([]byte("msg"), []byte("b")).Marshal()
+	if bytes.Equal(a, b) {
+		t.Fatal("domain separation tag ignored")
+	}
+}
@@ -0,0 +1,229 @@
+// Package ibe implements the Boneh-Franklin identity-based encryption scheme.
+//
+// A private key generator holds a master secret s and publishes P = s·g₂. The
+// private key of an identity ID is d = s·Q, where Q = H(ID) ∈ G₁, so anyone
+// can encrypt to ID knowing only P, and only the holder of d can decrypt:
+//
+//	e(Q, P)ʳ = e(d, r·g₂)
+//
+// EncryptBasic and DecryptBasic implement BasicIdent, which is only secure
+// against chosen-plaintext attacks. Encrypt and Decrypt implement FullIdent,
+// which applies the Fujisaki-Okamoto transform to make it secure against
+// adaptive chosen-ciphertext attacks; it should be preferred.
+//
+// See Boneh and Franklin, "Identity-Based Encryption from the Weil Pairing",
+// https://crypto.stanford.edu/~dabo/papers/bfibe.pdf.
+package ibe
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// sigmaSize is the length in bytes of the random value σ of FullIdent.
+const sigmaSize = 32
+
+// Domain separation tags for the hash functions of the scheme.
+var (
+	identityDomain = []byte("bn256/ibe/identity")
+	maskLabel      = []byte("bn256/ibe/mask")
+	nonceLabel     = []byte("bn256/ibe/nonce")
+	messageLabel   = []byte("bn256/ibe/message")
+)
+
+var (
+	errMalformed = errors.New("ibe: malformed ciphertext")
+	errDecrypt   = errors.New("ibe: decryption failed")
+)
+
+// MasterPublicKey is the public key of a private key generator.
+type MasterPublicKey struct {
+	P *bn256.G2
+}
+
+// MasterKey is the master secret of a private key generator.
+type MasterKey struct {
+	MasterPublicKey
+	S *big.Int
+}
+
+// PrivateKey is the private key of an identity.
+type PrivateKey struct {
+	D *bn256.G1
+}
+
+// GenerateMasterKey returns a new master key using randomness from r.
+func GenerateMasterKey(r io.Reader) (*MasterKey, error) {
+	s, P, err := bn256.RandomG2(r)
+	if err != nil {
+		return nil, err
+	}
+	return &MasterKey{MasterPublicKey{P}, s}, nil
+}
+
+// HashIdentity maps id to the point of G₁ that represents it.
+func HashIdentity(id []byte) *bn256.G1 {
+	return bn256.HashToG1(id, identityDomain)
+}
+
+// Extract returns the private key of id.
+func (msk *MasterKey) Extract(id []byte) *PrivateKey {
+	return &PrivateKey{new(bn256.G1).ScalarMult(HashIdentity(id), msk.S)}
+}
+
+// VerifyPrivateKey returns true iff sk is the private key of id, which lets the
+// holder of a key check that the private key generator issued it correctly.
+func (mpk *MasterPublicKey) VerifyPrivateKey(id []byte, sk *PrivateKey) bool {
+	// e(d, g₂) = e(Q, P)
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	negQ := new(bn256.G1).Neg(HashIdentity(id))
+	return bn256.PairingCheck([]*bn256.G1{sk.D, negQ}, []*bn256.G2{g2, mpk.P})
+}
+
+// BasicCiphertext is a BasicIdent ciphertext.
+type BasicCiphertext struct {
+	U *bn256.G2
+	V []byte
+}
+
+// EncryptBasic encrypts msg to id with BasicIdent using randomness from r.
+func EncryptBasic(r io.Reader, mpk *MasterPublicKey, id, msg []byte) (*BasicCiphertext, error) {
+	k, err := rand.Int(r, bn256.Order)
+	if err != nil {
+		return nil, err
+	}
+	U, g := mpk.encapsulate(id, k)
+	return &BasicCiphertext{U, xor(msg, kdf(maskLabel, g.Marshal(), len(msg)))}, nil
+}
+
+// DecryptBasic decrypts a BasicIdent ciphertext. It cannot detect whether c was
+// encrypted to the identity of sk or tampered with.
+func (sk *PrivateKey) DecryptBasic(c *BasicCiphertext) []byte {
+	g := bn256.Pair(sk.D, c.U)
+	return xor(c.V, kdf(maskLabel, g.Marshal(), len(c.V)))
+}
+
+// Marshal converts c into a byte slice.
+func (c *BasicCiphertext) Marshal() []byte {
+	return append(c.U.Marshal(), c.V...)
+}
+
+// Unmarshal sets c to the result of converting the output of Marshal back into
+// a ciphertext. It consumes all of m.
+func (c *BasicCiphertext) Unmarshal(m []byte) error {
+	U := new(bn256.G2)
+	rest, err := U.Unmarshal(m)
+	if err != nil {
+		return err
+	}
+	c.U, c.V = U, append([]byte(nil), rest...)
+	return nil
+}
+
+// Ciphertext is a FullIdent ciphertext.
+type Ciphertext struct {
+	U *bn256.G2
+	V []byte // σ masked by the pairing
+	W []byte // the message masked by σ
+}
+
+// Encrypt encrypts msg to id with FullIdent using randomness from r.
+func Encrypt(r io.Reader, mpk *MasterPublicKey, id, msg []byte) (*Ciphertext, error) {
+	sigma := make([]byte, sigmaSize)
+	if _, err := io.ReadFull(r, sigma); err != nil {
+		return nil, err
+	}
+
+	U, g := mpk.encapsulate(id, nonce(sigma, msg))
+	return &Ciphertext{
+		U: U,
+		V: xor(sigma, kdf(maskLabel, g.Marshal(), sigmaSize)),
+		W: xor(msg, kdf(messageLabel, sigma, len(msg))),
+	}, nil
+}
+
+// Decrypt decrypts a FullIdent ciphertext. It returns an error if c was not
+// encrypted to the identity of sk or has been tampered with.
+func (sk *PrivateKey) Decrypt(c *Ciphertext) ([]byte, error) {
+	if c.U == nil || len(c.V) != sigmaSize {
+		return nil, errMalformed
+	}
+
+	g := bn256.Pair(sk.D, c.U)
+	sigma := xor(c.V, kdf(maskLabel, g.Marshal(), sigmaSize))
+	msg := xor(c.W, kdf(messageLabel, sigma, len(c.W)))
+
+	U := new(bn256.G2).ScalarBaseMult(nonce(sigma, msg))
+	if subtle.ConstantTimeCompare(U.Marshal(), c.U.Marshal()) != 1 {
+		return nil, errDecrypt
+	}
+	return msg, nil
+}
+
+// Marshal converts c into a byte slice.
+func (c *Ciphertext) Marshal() []byte {
+	ret := append(c.U.Marshal(), c.V...)
+	return append(ret, c.W...)
+}
+
+// Unmarshal sets c to the result of converting the output of Marshal back into
+// a ciphertext. It consumes all of m.
+func (c *Ciphertext) Unmarshal(m []byte) error {
+	U := new(bn256.G2)
+	rest, err := U.Unmarshal(m)
+	if err != nil {
+		return err
+	}
+	if len(rest) < sigmaSize {
+		return errMalformed
+	}
+	c.U = U
+	c.V = append([]byte(nil), rest[:sigmaSize]...)
+	c.W = append([]byte(nil), rest[sigmaSize:]...)
+	return nil
+}
+
+// encapsulate returns U = k·g₂ and the key e(Q, P)ᵏ that it transports to id.
+func (mpk *MasterPublicKey) encapsulate(id []byte, k *big.Int) (*bn256.G2, *bn256.GT) {
+	// e(k·Q, P) = e(Q, P)ᵏ, but is cheaper to compute.
+	Q := new(bn256.G1).ScalarMult(HashIdentity(id), k)
+	return new(bn256.G2).ScalarBaseMult(k), bn256.Pair(Q, mpk.P)
+}
+
+// nonce derives the encryption nonce of FullIdent from σ and the message. 64
+// bytes of hash output are reduced modulo Order so that the result is close to
+// uniform.
+func nonce(sigma, msg []byte) *big.Int {
+	in := append(append([]byte(nil), sigma...), msg...)
+	k := new(big.Int).SetBytes(kdf(nonceLabel, in, 64))
+	return k.Mod(k, bn256.Order)
+}
+
+// kdf derives n bytes from label and secret with SHA-256 in counter mode.
+func kdf(label, secret []byte, n int) []byte {
+	ret := make([]byte, 0, n+sha256.Size)
+	for ctr := uint32(0); len(ret) < n; ctr++ {
+		h := sha256.New()
+		h.Write([]byte{byte(len(label))})
+		h.Write(label)
+		h.Write([]byte{byte(ctr >> 24), byte(ctr >> 16), byte(ctr >> 8), byte(ctr)})
+		h.Write(secret)
+		ret = h.Sum(ret)
+	}
+	return ret[:n]
+}
+
+// xor returns a ⊕ b. b must be at least as long as a.
+func xor(a, b []byte) []byte {
+	ret := make([]byte, len(a))
+	for i := range a {
+		ret[i] = a[i] ^ b[i]
+	}
+	return ret
+}
@@ -0,0 +1,134 @@
+package ibe
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func setup(t *testing.T) (*MasterKey, *PrivateKey) {
+	t.Helper()
+
+	msk, err := GenerateMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msk, msk.Extract([]byte("alice@example.com"))
+}
+
+func TestExtract(t *testing.T) {
+	msk, sk := setup(t)
+	if !msk.VerifyPrivateKey([]byte("alice@example.com"), sk) {
+		t.Fatal("valid private key rejected")
+	}
+	if msk.VerifyPrivateKey([]byte("bob@example.com"), sk) {
+		t.Fatal("private key accepted for another identity")
+	}
+
+	other, _ := GenerateMasterKey(rand.Reader)
+	if other.VerifyPrivateKey([]byte("alice@example.com"), sk) {
+		t.Fatal("private key accepted for another master key")
+	}
+}
+
+func TestBasicIdent(t *testing.T) {
+	msk, sk := setup(t)
+
+	for _, msg := range [][]byte{nil, []byte("hi"), bytes.Repeat([]byte("long message "), 10)} {
+		c, err := EncryptBasic(rand.Reader, &msk.MasterPublicKey, []byte("alice@example.com"), msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d := new(BasicCiphertext)
+		if err := d.Unmarshal(c.Marshal()); err != nil {
+			t.Fatal(err)
+		}
+		if got := sk.DecryptBasic(d); !bytes.Equal(got, msg) {
+			t.Fatalf("got %q, want %q", got, msg)
+		}
+	}
+
+	msg := []byte("for alice only")
+	c, _ := EncryptBasic(rand.Reader, &msk.MasterPublicKey, []byte("alice@example.com"), msg)
+	bob := msk.Extract([]byte("bob@example.com"))
+	if bytes.Equal(bob.DecryptBasic(c), msg) {
+		t.Fatal("another identity decrypted the message")
+	}
+}
+
+func TestFullIdent(t *testing.T) {
+	msk, sk := setup(t)
+
+	for _, msg := range [][]byte{nil, []byte("hi"), bytes.Repeat([]byte("long message "), 10)} {
+		c, err := Encrypt(rand.Reader, &msk.MasterPublicKey, []byte("alice@example.com"), msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d := new(Ciphertext)
+		if err := d.Unmarshal(c.Marshal()); err != nil {
+			t.Fatal(err)
+		}
+		got, err := sk.Decrypt(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, msg) {
+			t.Fatalf("got %q, want %q", got, msg)
+		}
+	}
+}
+
+func TestFullIdentRejects(t *testing.T) {
+	msk, sk := setup(t)
+	msg := []byte("for alice only")
+	c, _ := Encrypt(rand.Reader, &msk.MasterPublicKey, []byte("alice@example.com"), msg)
+
+	bob := msk.Extract([]byte("bob@example.com"))
+	if _, err := bob.Decrypt(c); err == nil {
+		t.Fatal("another identity decrypted the message")
+	}
+
+	tampered := []func(c *Ciphertext){
+		func(c *Ciphertext) { c.V[0] ^= 1 },
+		func(c *Ciphertext) { c.W[len(c.W)-1] ^= 1 },
+		func(c *Ciphertext) { c.W = c.W[1:] },
+		func(c *Ciphertext) { c.U.Add(c.U, c.U) },
+	}
+	for i, f := range tampered {
+		d := new(Ciphertext)
+		if err := d.Unmarshal(c.Marshal()); err != nil {
+			t.Fatal(err)
+		}
+		f(d)
+		if _, err := sk.Decrypt(d); err == nil {
+			t.Errorf("tampered ciphertext %d accepted", i)
+		}
+	}
+
+	if err := new(Ciphertext).Unmarshal(c.Marshal()[:128+sigmaSize-1]); err == nil {
+		t.Fatal("truncated ciphertext accepted")
+	}
+}
+
+func BenchmarkEncrypt(b *testing.B) {
+	msk, _ := GenerateMasterKey(rand.Reader)
+	msg := make([]byte, 32)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Encrypt(rand.Reader, &msk.MasterPublicKey, []byte("alice@example.com"), msg)
+	}
+}
+
+func BenchmarkDecrypt(b *testing.B) {
+	msk, _ := GenerateMasterKey(rand.Reader)
+	sk := msk.Extract([]byte("alice@example.com"))
+	c, _ := Encrypt(rand.Reader, &msk.MasterPublicKey, []byte("alice@example.com"), make([]byte, 32))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		sk.Decrypt(c)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !fermat
+// +build !fermat
+
+package bn256
+
+// Invert sets e to 1/f, or to zero if f is zero, in constant time. Building
+// with the fermat tag selects the slower exponentiation to the power p-2.
+func (e *gfP) Invert(f *gfP) {
+	e.invertSafegcd(f)
+}
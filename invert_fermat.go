@@ -0,0 +1,9 @@
+//go:build fermat
+// +build fermat
+
+package bn256
+
+// Invert sets e to 1/f, or to zero if f is zero, in constant time.
+func (e *gfP) Invert(f *gfP) {
+	e.invertFermat(f)
+}
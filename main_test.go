@@ -33,7 +33,7 @@ func TestPairings(t *testing.T) {
 	p1 := Pair(a1, b1)
 	pn1 := Pair(a1, bn1)
 	np1 := Pair(an1, b1)
-	if pn1.String() != np1.String() {
+	if !pn1.Equal(np1) {
 		t.Error("Pairing mismatch: e(a, -b) != e(-a, b)")
 	}
 	if !PairingCheck([]*G1{a1, an1}, []*G2{b1, b1}) {
@@ -41,23 +41,23 @@ func TestPairings(t *testing.T) {
 	}
 	p0 := new(GT).Add(p1, pn1)
 	p0_2 := Pair(a1, b0)
-	if p0.String() != p0_2.String() {
+	if !p0.Equal(p0_2) {
 		t.Error("Pairing mismatch: e(a, b) * e(a, -b) != 1")
 	}
 	p0_3 := new(GT).ScalarMult(p1, bigFromBase10("21888242871839275222246405745257275088548364400416034343698204186575808495617"))
-	if p0.String() != p0_3.String() {
+	if !p0.Equal(p0_3) {
 		t.Error("Pairing mismatch: e(a, b) has wrong order")
 	}
 	p2 := Pair(a2, b1)
 	p2_2 := Pair(a1, b2)
 	p2_3 := new(GT).ScalarMult(p1, bigFromBase10("2"))
-	if p2.String() != p2_2.String() {
+	if !p2.Equal(p2_2) {
 		t.Error("Pairing mismatch: e(a, b * 2) != e(a * 2, b)")
 	}
-	if p2.String() != p2_3.String() {
+	if !p2.Equal(p2_3) {
 		t.Error("Pairing mismatch: e(a, b * 2) != e(a, b) ** 2")
 	}
-	if p2.String() == p1.String() {
+	if p2.Equal(p1) {
 		t.Error("Pairing is degenerate!")
 	}
 	if PairingCheck([]*G1{a1, a1}, []*G2{b1, b1}) {
@@ -65,7 +65,7 @@ func TestPairings(t *testing.T) {
 	}
 	p999 := Pair(a37, b27)
 	p999_2 := Pair(a1, b999)
-	if p999.String() != p999_2.String() {
+	if !p999.Equal(p999_2) {
 		t.Error("Pairing mismatch: e(a * 37, b * 27) != e(a, b * 999)")
 	}
 }
@@ -0,0 +1,394 @@
+package bn256
+
+// This file implements the standard encoding interfaces for G1, G2 and GT, so
+// that they can be used directly with encoding/gob, encoding/json and the
+// like.
+//
+// The binary form is the output of Marshal and the text form is the same bytes
+// in hex, prefixed with "0x". JSON is a hex string by default. The types
+// DecimalG1, DecimalG2 and DecimalGT encode instead as arrays of decimal
+// strings holding projective coordinates, as snarkjs writes verification keys
+// and proofs: a point of G1 is ["x", "y", "1"], an element a₀+a₁·i of GF(p²)
+// is ["a₀", "a₁"], and the identity has z zero. Decoding accepts either JSON
+// form for both kinds of type.
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e *G1) MarshalBinary() ([]byte, error) {
+	return e.Marshal(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. Unlike Unmarshal, it
+// rejects data that does not hold exactly one point.
+func (e *G1) UnmarshalBinary(data []byte) error {
+	return unmarshalExact(e.Unmarshal, data)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e *G1) MarshalText() ([]byte, error) {
+	return marshalHex(e.Marshal()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (e *G1) UnmarshalText(text []byte) error {
+	return unmarshalHex(e.UnmarshalBinary, text)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *G1) MarshalJSON() ([]byte, error) {
+	return marshalJSONHex(e.Marshal())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *G1) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(e.UnmarshalText, e.unmarshalDecimal, data)
+}
+
+func (e *G1) marshalDecimal() ([]byte, error) {
+	a := e.point().affine()
+	if a.IsInfinity() {
+		return json.Marshal([]string{"0", "1", "0"})
+	}
+	return json.Marshal([]string{decimalGFp(&a.x), decimalGFp(&a.y), "1"})
+}
+
+func (e *G1) unmarshalDecimal(data []byte) error {
+	var v []string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v) != 3 {
+		return errors.New("bn256: G1 point needs three coordinates")
+	}
+
+	c := &curvePoint{}
+	for i, dst := range []*gfP{&c.x, &c.y, &c.z} {
+		if err := parseDecimalGFp(dst, v[i]); err != nil {
+			return err
+		}
+	}
+	c.MakeAffine()
+	if !c.IsOnCurve() {
+		return errors.New("bn256: malformed point")
+	}
+
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	e.p.Set(c)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e *G2) MarshalBinary() ([]byte, error) {
+	return e.Marshal(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. Unlike Unmarshal, it
+// rejects data that does not hold exactly one point.
+func (e *G2) UnmarshalBinary(data []byte) error {
+	return unmarshalExact(e.Unmarshal, data)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e *G2) MarshalText() ([]byte, error) {
+	return marshalHex(e.Marshal()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (e *G2) UnmarshalText(text []byte) error {
+	return unmarshalHex(e.UnmarshalBinary, text)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *G2) MarshalJSON() ([]byte, error) {
+	return marshalJSONHex(e.Marshal())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *G2) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(e.UnmarshalText, e.unmarshalDecimal, data)
+}
+
+func (e *G2) marshalDecimal() ([]byte, error) {
+	a := &twistPoint{}
+	if e.p != nil {
+		a = e.p.affine()
+	}
+	if a.IsInfinity() {
+		return json.Marshal([][]string{{"0", "0"}, {"1", "0"}, {"0", "0"}})
+	}
+	return json.Marshal([][]string{decimalGFp2(&a.x), decimalGFp2(&a.y), {"1", "0"}})
+}
+
+func (e *G2) unmarshalDecimal(data []byte) error {
+	var v [][]string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v) != 3 {
+		return errors.New("bn256: G2 point needs three coordinates")
+	}
+
+	c := &twistPoint{}
+	for i, dst := range []*gfP2{&c.x, &c.y, &c.z} {
+		if err := parseDecimalGFp2(dst, v[i]); err != nil {
+			return err
+		}
+	}
+	c.MakeAffine()
+	if !c.IsOnCurve() {
+		return errors.New("bn256: malformed point")
+	}
+
+	if e.p == nil {
+		e.p = &twistPoint{}
+	}
+	e.p.Set(c)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e *GT) MarshalBinary() ([]byte, error) {
+	return e.Marshal(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. Unlike Unmarshal, it
+// rejects data that does not hold exactly one element.
+func (e *GT) UnmarshalBinary(data []byte) error {
+	return unmarshalExact(e.Unmarshal, data)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e *GT) MarshalText() ([]byte, error) {
+	return marshalHex(e.Marshal()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (e *GT) UnmarshalText(text []byte) error {
+	return unmarshalHex(e.UnmarshalBinary, text)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *GT) MarshalJSON() ([]byte, error) {
+	return marshalJSONHex(e.Marshal())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *GT) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(e.UnmarshalText, e.unmarshalDecimal, data)
+}
+
+// gtDecimal lists the coefficients of an element of GF(p¹²) in the nesting
+// order of snarkjs, in which every extension lists its constant term first.
+func gtDecimal(e *gfP12) [2][3]*gfP2 {
+	return [2][3]*gfP2{
+		{&e.y.z, &e.y.y, &e.y.x},
+		{&e.x.z, &e.x.y, &e.x.x},
+	}
+}
+
+func (e *GT) marshalDecimal() ([]byte, error) {
+	var v [2][3][]string
+	for i, row := range gtDecimal(e.point()) {
+		for j, c := range row {
+			v[i][j] = decimalGFp2(c)
+		}
+	}
+	return json.Marshal(v)
+}
+
+func (e *GT) unmarshalDecimal(data []byte) error {
+	var v [][][]string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	p := &gfP12{}
+	coeffs := gtDecimal(p)
+	if len(v) != len(coeffs) {
+		return errors.New("bn256: GT element needs two coefficients")
+	}
+	for i, row := range coeffs {
+		if len(v[i]) != len(row) {
+			return errors.New("bn256: GT element needs three coefficients in each half")
+		}
+		for j, c := range row {
+			if err := parseDecimalGFp2(c, v[i][j]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.Set(p)
+	return nil
+}
+
+// DecimalG1 is a G1 that encodes in JSON as snarkjs does. Convert between the
+// two with (*DecimalG1)(g) and (*G1)(d).
+type DecimalG1 G1
+
+// MarshalJSON implements json.Marshaler.
+func (e *DecimalG1) MarshalJSON() ([]byte, error) {
+	return (*G1)(e).marshalDecimal()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *DecimalG1) UnmarshalJSON(data []byte) error {
+	return (*G1)(e).UnmarshalJSON(data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e *DecimalG1) MarshalBinary() ([]byte, error) {
+	return (*G1)(e).MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (e *DecimalG1) UnmarshalBinary(data []byte) error {
+	return (*G1)(e).UnmarshalBinary(data)
+}
+
+// DecimalG2 is a G2 that encodes in JSON as snarkjs does. Convert between the
+// two with (*DecimalG2)(g) and (*G2)(d).
+type DecimalG2 G2
+
+// MarshalJSON implements json.Marshaler.
+func (e *DecimalG2) MarshalJSON() ([]byte, error) {
+	return (*G2)(e).marshalDecimal()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *DecimalG2) UnmarshalJSON(data []byte) error {
+	return (*G2)(e).UnmarshalJSON(data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e *DecimalG2) MarshalBinary() ([]byte, error) {
+	return (*G2)(e).MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (e *DecimalG2) UnmarshalBinary(data []byte) error {
+	return (*G2)(e).UnmarshalBinary(data)
+}
+
+// DecimalGT is a GT that encodes in JSON as an array of decimal coefficients
+// nested in the same way as snarkjs. Convert between the two with
+// (*DecimalGT)(g) and (*GT)(d).
+type DecimalGT GT
+
+// MarshalJSON implements json.Marshaler.
+func (e *DecimalGT) MarshalJSON() ([]byte, error) {
+	return (*GT)(e).marshalDecimal()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *DecimalGT) UnmarshalJSON(data []byte) error {
+	return (*GT)(e).UnmarshalJSON(data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e *DecimalGT) MarshalBinary() ([]byte, error) {
+	return (*GT)(e).MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (e *DecimalGT) UnmarshalBinary(data []byte) error {
+	return (*GT)(e).UnmarshalBinary(data)
+}
+
+// unmarshalExact calls unmarshal and fails if it leaves any data unread.
+func unmarshalExact(unmarshal func([]byte) ([]byte, error), data []byte) error {
+	rest, err := unmarshal(data)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 0 {
+		return errors.New("bn256: trailing data")
+	}
+	return nil
+}
+
+func marshalHex(b []byte) []byte {
+	ret := make([]byte, 2+hex.EncodedLen(len(b)))
+	copy(ret, "0x")
+	hex.Encode(ret[2:], b)
+	return ret
+}
+
+func unmarshalHex(unmarshal func([]byte) error, text []byte) error {
+	if !bytes.HasPrefix(text, []byte("0x")) {
+		return errors.New("bn256: hex encoding lacks 0x prefix")
+	}
+	b := make([]byte, hex.DecodedLen(len(text)-2))
+	if _, err := hex.Decode(b, text[2:]); err != nil {
+		return err
+	}
+	return unmarshal(b)
+}
+
+func marshalJSONHex(b []byte) ([]byte, error) {
+	return json.Marshal(string(marshalHex(b)))
+}
+
+// unmarshalJSON decodes either a hex string, with unmarshalText, or an array
+// of decimal coordinates, with unmarshalDecimal. Like the standard library, it
+// treats null as a no-op.
+func unmarshalJSON(unmarshalText, unmarshalDecimal func([]byte) error, data []byte) error {
+	switch {
+	case string(data) == "null":
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return unmarshalText([]byte(s))
+	case len(data) > 0 && data[0] == '[':
+		return unmarshalDecimal(data)
+	}
+	return errors.New("bn256: JSON value is neither a string nor an array")
+}
+
+// decimalGFp returns a, which is in Montgomery form, in decimal.
+func decimalGFp(a *gfP) string {
+	return (&Fp{*a}).Big().String()
+}
+
+// decimalGFp2 returns the real and imaginary parts of a in decimal.
+func decimalGFp2(a *gfP2) []string {
+	return []string{decimalGFp(&a.y), decimalGFp(&a.x)}
+}
+
+// parseDecimalGFp sets e to the Montgomery form of the decimal number s, which
+// must be less than p.
+func parseDecimalGFp(e *gfP, s string) error {
+	k, ok := new(big.Int).SetString(s, 10)
+	if !ok || k.Sign() < 0 || k.Cmp(P) >= 0 {
+		return errors.New("bn256: coordinate is not a decimal number less than p")
+	}
+	*e = gfPFromBig(k)
+	montEncode(e, e)
+	return nil
+}
+
+// parseDecimalGFp2 sets e from its real and imaginary parts in decimal.
+func parseDecimalGFp2(e *gfP2, s []string) error {
+	if len(s) != 2 {
+		return errors.New("bn256: element of GF(p²) needs two coefficients")
+	}
+	if err := parseDecimalGFp(&e.y, s[0]); err != nil {
+		return err
+	}
+	return parseDecimalGFp(&e.x, s[1])
+}
@@ -0,0 +1,206 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+type encodedPoints struct {
+	A  *G1
+	B  *G2
+	C  *GT
+	DA *DecimalG1
+	DB *DecimalG2
+	DC *DecimalGT
+	K  *Scalar
+	DK *DecimalScalar
+}
+
+func randomEncodedPoints(t *testing.T, identity bool) *encodedPoints {
+	k, err := rand.Int(rand.Reader, Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity {
+		k.SetInt64(0)
+	}
+	a := new(G1).ScalarBaseMult(k)
+	b := new(G2).ScalarBaseMult(k)
+	c := Pair(a, b)
+	return &encodedPoints{
+		a, b, c,
+		(*DecimalG1)(new(G1).Set(a)), (*DecimalG2)(new(G2).Set(b)), (*DecimalGT)(new(GT).Set(c)),
+		NewScalar(k), (*DecimalScalar)(NewScalar(k)),
+	}
+}
+
+func (e *encodedPoints) equal(f *encodedPoints) bool {
+	return bytes.Equal(e.A.Marshal(), f.A.Marshal()) &&
+		bytes.Equal(e.B.Marshal(), f.B.Marshal()) &&
+		bytes.Equal(e.C.Marshal(), f.C.Marshal()) &&
+		bytes.Equal((*G1)(e.DA).Marshal(), (*G1)(f.DA).Marshal()) &&
+		bytes.Equal((*G2)(e.DB).Marshal(), (*G2)(f.DB).Marshal()) &&
+		bytes.Equal((*GT)(e.DC).Marshal(), (*GT)(f.DC).Marshal()) &&
+		e.K.Big().Cmp(f.K.Big()) == 0 && (*Scalar)(e.DK).Big().Cmp((*Scalar)(f.DK).Big()) == 0
+}
+
+func TestMarshalJSON(t *testing.T) {
+	// Decoding twice into the same value checks that old state is cleared.
+	got := new(encodedPoints)
+	for _, identity := range []bool{false, true, false} {
+		want := randomEncodedPoints(t, identity)
+		data, err := json.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, got); err != nil {
+			t.Fatal(err)
+		}
+		if !got.equal(want) {
+			t.Fatalf("JSON round trip failed: %s", data)
+		}
+	}
+}
+
+func TestMarshalGob(t *testing.T) {
+	var buf bytes.Buffer
+	enc, dec := gob.NewEncoder(&buf), gob.NewDecoder(&buf)
+
+	got := new(encodedPoints)
+	for _, identity := range []bool{false, true, false} {
+		want := randomEncodedPoints(t, identity)
+		if err := enc.Encode(want); err != nil {
+			t.Fatal(err)
+		}
+		if err := dec.Decode(got); err != nil {
+			t.Fatal(err)
+		}
+		if !got.equal(want) {
+			t.Fatal("gob round trip failed")
+		}
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	_, g1, _ := RandomG1(rand.Reader)
+	text, _ := g1.MarshalText()
+	if !strings.HasPrefix(string(text), "0x") || len(text) != 2+128 {
+		t.Fatalf("unexpected text form %s", text)
+	}
+	if err := new(G1).UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, bad := range []string{
+		string(text[2:]),           // no prefix
+		string(text[:len(text)-2]), // short
+		string(text) + "00",        // trailing data
+		"0x" + strings.Repeat("zz", 64),
+	} {
+		if err := new(G1).UnmarshalText([]byte(bad)); err == nil {
+			t.Errorf("%s accepted", bad)
+		}
+	}
+}
+
+// TestMarshalZeroValue checks that the zero values encode as the identity, as
+// the other methods treat them.
+func TestMarshalZeroValue(t *testing.T) {
+	for _, tc := range []struct {
+		zero, identity interface{}
+	}{
+		{new(G1), new(G1).SetIdentity()},
+		{new(G2), new(G2).SetIdentity()},
+		{new(GT), new(GT).SetIdentity()},
+		{new(DecimalG1), (*DecimalG1)(new(G1).SetIdentity())},
+		{new(DecimalG2), (*DecimalG2)(new(G2).SetIdentity())},
+		{new(DecimalGT), (*DecimalGT)(new(GT).SetIdentity())},
+	} {
+		got, err := json.Marshal(tc.zero)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := json.Marshal(tc.identity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%T: zero value encodes as %s, want %s", tc.zero, got, want)
+		}
+	}
+
+	if new(G1).String() != new(G1).SetIdentity().String() || new(GT).String() != new(GT).SetIdentity().String() {
+		t.Error("zero value does not print as the identity")
+	}
+}
+
+// TestMarshalDecimal checks the decimal JSON form against the generators as
+// snarkjs writes them.
+func TestMarshalDecimal(t *testing.T) {
+	g2 := `[["10857046999023057135944570762232829481370756359578518086990519993285655852781",` +
+		`"11559732032986387107991004021392285783925812861821192530917403151452391805634"],` +
+		`["8495653923123431417604973247489272438418190587263600148770280649306958101930",` +
+		`"4082367875863433681332203403145435568316851327593401208105741076214120093531"],` +
+		`["1","0"]]`
+
+	one := big.NewInt(1)
+	for _, tc := range []struct {
+		value json.Marshaler
+		want  string
+	}{
+		{(*DecimalG1)(new(G1).ScalarBaseMult(one)), `["1","2","1"]`},
+		{(*DecimalG1)(new(G1).ScalarBaseMult(new(big.Int))), `["0","1","0"]`},
+		{(*DecimalG2)(new(G2).ScalarBaseMult(one)), g2},
+		{(*DecimalG2)(new(G2).ScalarBaseMult(new(big.Int))), `[["0","0"],["1","0"],["0","0"]]`},
+	} {
+		got, err := tc.value.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tc.want {
+			t.Errorf("got %s, want %s", got, tc.want)
+		}
+	}
+
+	g := new(G2)
+	if err := json.Unmarshal([]byte(g2), g); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(g.Marshal(), new(G2).ScalarBaseMult(one).Marshal()) {
+		t.Error("decimal G2 generator decoded wrongly")
+	}
+
+	// Projective coordinates are Jacobian: (x, y, z) is (x/z², y/z³).
+	h := new(G1)
+	if err := json.Unmarshal([]byte(`["4","16","2"]`), h); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(h.Marshal(), new(G1).ScalarBaseMult(one).Marshal()) {
+		t.Error("projective G1 point decoded wrongly")
+	}
+
+	gt := new(GT)
+	for _, bad := range []string{
+		`["1","2"]`,
+		`["1","3","1"]`,
+		`["1","2","x"]`,
+		`["-1","2","1"]`,
+		`["` + P.String() + `","2","1"]`,
+		`17`,
+	} {
+		if err := json.Unmarshal([]byte(bad), h); err == nil {
+			t.Errorf("G1 %s accepted", bad)
+		}
+	}
+	if err := json.Unmarshal([]byte(`[["1","0"],["2","0"],["1","0"]]`), g); err == nil {
+		t.Error("G2 point off the twist accepted")
+	}
+	if err := json.Unmarshal([]byte(`[[["1","0"]]]`), gt); err == nil {
+		t.Error("short GT element accepted")
+	}
+}
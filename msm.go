@@ -0,0 +1,90 @@
+package bn256
+
+import (
+	"math/big"
+)
+
+// MultiScalarMult sets e to Σ k[i]·a[i] and then returns e. It uses
+// Pippenger's bucket method, which is considerably faster than computing each
+// product separately when there are many terms. a and k must have the same
+// length.
+func (e *G1) MultiScalarMult(a []*G1, k []*big.Int) *G1 {
+	if len(a) != len(k) {
+		panic("bn256: mismatched number of points and scalars")
+	}
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+
+	points := make([]*curvePoint, len(a))
+	for i := range a {
+		points[i] = a[i].p
+	}
+	multiScalarMult(e.p, points, k)
+	return e
+}
+
+func multiScalarMult(c *curvePoint, points []*curvePoint, scalars []*big.Int) {
+	// Each scalar is reduced to a 256-bit number.
+	const numBits = 256
+
+	digits := make([][32]byte, len(scalars))
+	k := new(big.Int)
+	for i, s := range scalars {
+		k.Mod(s, Order)
+		b := k.Bytes()
+		copy(digits[i][32-len(b):], b)
+	}
+
+	w := msmWindow(len(points))
+	buckets := make([]curvePoint, 1<<uint(w))
+	sum, running, t := &curvePoint{}, &curvePoint{}, &curvePoint{}
+	sum.SetInfinity()
+
+	for start := (numBits - 1) / w * w; start >= 0; start -= w {
+		for i := 0; i < w; i++ {
+			t.Double(sum)
+			sum.Set(t)
+		}
+
+		for i := range buckets {
+			buckets[i].SetInfinity()
+		}
+		for i, p := range points {
+			if d := window(&digits[i], start, w); d != 0 {
+				buckets[d].Add(&buckets[d], p)
+			}
+		}
+
+		// Σ d·buckets[d] = Σ_d Σ_{d'≥d} buckets[d']
+		running.SetInfinity()
+		for d := len(buckets) - 1; d > 0; d-- {
+			running.Add(running, &buckets[d])
+			sum.Add(sum, running)
+		}
+	}
+	c.Set(sum)
+}
+
+// msmWindow returns the window size in bits for a multi-scalar
+// multiplication of n terms.
+func msmWindow(n int) int {
+	w := 1
+	for 1<<uint(w+2) <= n && w < 16 {
+		w++
+	}
+	return w
+}
+
+// window returns bits start to start+w-1 of the big-endian number b.
+func window(b *[32]byte, start, w int) int {
+	d := 0
+	for i := w - 1; i >= 0; i-- {
+		bit := start + i
+		if bit >= 8*len(b) {
+			continue
+		}
+		d = d<<1 | int(b[len(b)-1-bit/8]>>uint(bit%8)&1)
+	}
+	return d
+}
@@ -0,0 +1,49 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestMultiScalarMult(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 5, 16, 70} {
+		points := make([]*G1, n)
+		scalars := make([]*big.Int, n)
+		want := new(G1).ScalarBaseMult(new(big.Int))
+
+		for i := range points {
+			_, points[i], _ = RandomG1(rand.Reader)
+			scalars[i], _ = rand.Int(rand.Reader, Order)
+			switch i {
+			case 1:
+				scalars[i].SetInt64(0)
+			case 2:
+				scalars[i].Neg(scalars[i])
+			case 3:
+				scalars[i].Add(scalars[i], Order)
+			}
+			want.Add(want, new(G1).ScalarMult(points[i], scalars[i]))
+		}
+
+		got := new(G1).MultiScalarMult(points, scalars)
+		if !bytes.Equal(got.Marshal(), want.Marshal()) {
+			t.Errorf("%d terms: result differs from sum of products", n)
+		}
+	}
+}
+
+func BenchmarkMultiScalarMult(b *testing.B) {
+	points := make([]*G1, 256)
+	scalars := make([]*big.Int, len(points))
+	for i := range points {
+		_, points[i], _ = RandomG1(rand.Reader)
+		scalars[i], _ = rand.Int(rand.Reader, Order)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		new(G1).MultiScalarMult(points, scalars)
+	}
+}
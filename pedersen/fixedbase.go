@@ -0,0 +1,79 @@
+package pedersen
+
+import (
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+const (
+	// window is the number of scalar bits consumed per precomputed power.
+	window = 4
+	// windows is the number of windows needed to cover a scalar mod Order.
+	windows = 256 / window
+)
+
+// fixedBase computes multi-scalar multiplications Σ kᵢ·Bᵢ over a fixed set of
+// bases Bᵢ. It precomputes 2^(window·j)·Bᵢ for every base and window, after
+// which a product needs no doublings: each power is added into the bucket of
+// its window's digit and the buckets are combined with running sums.
+type fixedBase struct {
+	powers [][windows]*bn256.G1
+}
+
+func newFixedBase(bases []*bn256.G1) *fixedBase {
+	f := &fixedBase{powers: make([][windows]*bn256.G1, len(bases))}
+	for i, b := range bases {
+		p := new(bn256.G1).Set(b)
+		for j := range f.powers[i] {
+			f.powers[i][j] = new(bn256.G1).Set(p)
+			for k := 0; k < window; k++ {
+				p.Add(p, p)
+			}
+		}
+	}
+	return f
+}
+
+// mul returns Σ scalars[i]·Bᵢ. There must be at most as many scalars as bases.
+func (f *fixedBase) mul(scalars []*big.Int) *bn256.G1 {
+	var buckets [1 << window]*bn256.G1
+	for d := range buckets {
+		buckets[d] = new(bn256.G1).SetIdentity()
+	}
+
+	for i, s := range scalars {
+		for j, d := range scalarDigits(s) {
+			if d != 0 {
+				buckets[d].Add(buckets[d], f.powers[i][j])
+			}
+		}
+	}
+
+	// Σ d·buckets[d] = Σ_d Σ_{e≥d} buckets[e]
+	running, sum := new(bn256.G1).SetIdentity(), new(bn256.G1).SetIdentity()
+	for d := len(buckets) - 1; d > 0; d-- {
+		running.Add(running, buckets[d])
+		sum.Add(sum, running)
+	}
+	return sum
+}
+
+// scalarDigits splits s mod Order into base 2^window digits, least significant
+// first.
+func scalarDigits(s *big.Int) [windows]uint8 {
+	var buf [32]byte
+	b := new(big.Int).Mod(s, bn256.Order).Bytes()
+	copy(buf[32-len(b):], b)
+
+	var digits [windows]uint8
+	for j := range digits {
+		by := buf[31-j/2]
+		if j%2 == 0 {
+			digits[j] = by & 0xf
+		} else {
+			digits[j] = by >> 4
+		}
+	}
+	return digits
+}
@@ -0,0 +1,154 @@
+// Package pedersen implements Pedersen vector commitments over G₁.
+//
+// A commitment to the scalars v₁, …, vₙ with blinding factor r is
+//
+//	C = v₁·G₁ + … + vₙ·Gₙ + r·H
+//
+// It is perfectly hiding and computationally binding. The generators are
+// derived deterministically with bn256.HashToG1, so nobody knows a discrete
+// logarithm relation between them. Commitments are additively homomorphic:
+// the sum of the commitments to two vectors is the commitment to their sum
+// under the sum of the blinding factors.
+package pedersen
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// domain is the domain separation tag used to derive generators.
+var domain = []byte("bn256/pedersen/generators")
+
+// Params holds the generators of a commitment scheme for vectors of up to
+// len(G) scalars.
+type Params struct {
+	G []*bn256.G1
+	H *bn256.G1
+
+	// table holds the multiples of H, G₁, …, Gₙ that Commit uses. It is set
+	// by Setup; Params built by hand fall back to G1.MultiScalarMult.
+	table *fixedBase
+}
+
+// Setup returns the parameters for committing to vectors of up to n scalars.
+// The generators are derived from label, so that different applications can
+// use independent generators. Setup is deterministic.
+func Setup(label []byte, n int) *Params {
+	pp := &Params{
+		G: make([]*bn256.G1, n),
+		H: bn256.HashToG1(generatorInput(label, 'H', 0), domain),
+	}
+	for i := range pp.G {
+		pp.G[i] = bn256.HashToG1(generatorInput(label, 'G', uint32(i)), domain)
+	}
+	pp.table = newFixedBase(append([]*bn256.G1{pp.H}, pp.G...))
+	return pp
+}
+
+// generatorInput encodes label, the kind of generator and its index
+// unambiguously.
+func generatorInput(label []byte, kind byte, i uint32) []byte {
+	n := uint32(len(label))
+	ret := []byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+	ret = append(ret, label...)
+	return append(ret, kind, byte(i>>24), byte(i>>16), byte(i>>8), byte(i))
+}
+
+// RandomBlinding returns a random blinding factor read from r.
+func RandomBlinding(r io.Reader) (*big.Int, error) {
+	return rand.Int(r, bn256.Order)
+}
+
+// Commit returns the commitment to values under blinding. values may be
+// shorter than len(pp.G), in which case the remaining values are zero. All
+// scalars are reduced modulo Order.
+func (pp *Params) Commit(values []*big.Int, blinding *big.Int) (*Commitment, error) {
+	if len(values) > len(pp.G) {
+		return nil, errors.New("pedersen: too many values")
+	}
+
+	scalars := append([]*big.Int{blinding}, values...)
+	if pp.table != nil {
+		return &Commitment{pp.table.mul(scalars)}, nil
+	}
+	points := append([]*bn256.G1{pp.H}, pp.G[:len(values)]...)
+	return &Commitment{new(bn256.G1).MultiScalarMult(points, scalars)}, nil
+}
+
+// Verify returns true iff c opens to values under blinding.
+func (pp *Params) Verify(c *Commitment, values []*big.Int, blinding *big.Int) bool {
+	d, err := pp.Commit(values, blinding)
+	if err != nil {
+		return false
+	}
+	return c.Equal(d)
+}
+
+// Commitment is a Pedersen commitment. The zero value is suitable for use as
+// the output of an operation, but cannot be used as an input.
+type Commitment struct {
+	p *bn256.G1
+}
+
+// NewCommitment returns the commitment whose value is the point p.
+func NewCommitment(p *bn256.G1) *Commitment {
+	return &Commitment{new(bn256.G1).Set(p)}
+}
+
+// Point returns the point underlying c.
+func (c *Commitment) Point() *bn256.G1 {
+	return new(bn256.G1).Set(c.p)
+}
+
+// Add sets c to a+b, which is a commitment to the sum of the values of a and b
+// under the sum of their blinding factors, and then returns c.
+func (c *Commitment) Add(a, b *Commitment) *Commitment {
+	if c.p == nil {
+		c.p = new(bn256.G1)
+	}
+	c.p.Add(a.p, b.p)
+	return c
+}
+
+// Neg sets c to -a, which is a commitment to the negated values of a under the
+// negated blinding factor, and then returns c.
+func (c *Commitment) Neg(a *Commitment) *Commitment {
+	if c.p == nil {
+		c.p = new(bn256.G1)
+	}
+	c.p.Neg(a.p)
+	return c
+}
+
+// ScalarMult sets c to k·a, which is a commitment to the values of a scaled by
+// k under the blinding factor of a scaled by k, and then returns c.
+func (c *Commitment) ScalarMult(a *Commitment, k *big.Int) *Commitment {
+	if c.p == nil {
+		c.p = new(bn256.G1)
+	}
+	c.p.ScalarMult(a.p, k)
+	return c
+}
+
+// Equal returns true iff c and d are the same commitment.
+func (c *Commitment) Equal(d *Commitment) bool {
+	return c.p.Equal(d.p)
+}
+
+// Marshal converts c into a byte slice.
+func (c *Commitment) Marshal() []byte {
+	return c.p.Marshal()
+}
+
+// Unmarshal sets c to the result of converting the output of Marshal back into
+// a commitment.
+func (c *Commitment) Unmarshal(m []byte) ([]byte, error) {
+	if c.p == nil {
+		c.p = new(bn256.G1)
+	}
+	return c.p.Unmarshal(m)
+}
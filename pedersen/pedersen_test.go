@@ -0,0 +1,175 @@
+package pedersen
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+func randomScalars(t *testing.T, n int) []*big.Int {
+	t.Helper()
+
+	ret := make([]*big.Int, n)
+	for i := range ret {
+		k, err := rand.Int(rand.Reader, bn256.Order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret[i] = k
+	}
+	return ret
+}
+
+func TestSetupDeterministic(t *testing.T) {
+	a, b := Setup([]byte("test"), 4), Setup([]byte("test"), 4)
+	for i := range a.G {
+		if !bytes.Equal(a.G[i].Marshal(), b.G[i].Marshal()) {
+			t.Fatalf("G[%d] differs between setups", i)
+		}
+	}
+	if !bytes.Equal(a.H.Marshal(), b.H.Marshal()) {
+		t.Fatal("H differs between setups")
+	}
+
+	c := Setup([]byte("other"), 4)
+	if bytes.Equal(a.G[0].Marshal(), c.G[0].Marshal()) {
+		t.Fatal("label ignored")
+	}
+	if bytes.Equal(a.G[0].Marshal(), a.G[1].Marshal()) || bytes.Equal(a.G[0].Marshal(), a.H.Marshal()) {
+		t.Fatal("generators are not distinct")
+	}
+}
+
+func TestCommit(t *testing.T) {
+	pp := Setup([]byte("test"), 8)
+	values := randomScalars(t, 8)
+	blinding := randomScalars(t, 1)[0]
+
+	c, err := pp.Commit(values, blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Compare against the naive computation.
+	want := new(bn256.G1).ScalarMult(pp.H, blinding)
+	for i, v := range values {
+		want.Add(want, new(bn256.G1).ScalarMult(pp.G[i], v))
+	}
+	if !bytes.Equal(c.Marshal(), want.Marshal()) {
+		t.Fatal("commitment does not match naive computation")
+	}
+
+	if !pp.Verify(c, values, blinding) {
+		t.Fatal("valid opening rejected")
+	}
+	values[3].Add(values[3], big.NewInt(1))
+	if pp.Verify(c, values, blinding) {
+		t.Fatal("invalid opening accepted")
+	}
+
+	if _, err := pp.Commit(randomScalars(t, 9), blinding); err == nil {
+		t.Fatal("too many values accepted")
+	}
+}
+
+func TestCommitShortAndZero(t *testing.T) {
+	pp := Setup([]byte("test"), 4)
+
+	c, err := pp.Commit([]*big.Int{big.NewInt(5)}, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := new(bn256.G1).ScalarMult(pp.G[0], big.NewInt(5))
+	if !bytes.Equal(c.Marshal(), want.Marshal()) {
+		t.Fatal("commitment to short vector is wrong")
+	}
+
+	zero, err := pp.Commit(nil, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(zero.Marshal(), make([]byte, 64)) {
+		t.Fatal("commitment to zero is not the identity")
+	}
+}
+
+// TestCommitWithoutTable checks that the precomputed tables agree with the
+// generic multi-scalar multiplication used for Params built by hand, including
+// for scalars that are not reduced.
+func TestCommitWithoutTable(t *testing.T) {
+	pp := Setup([]byte("test"), 4)
+	bare := &Params{G: pp.G, H: pp.H}
+
+	values := randomScalars(t, 4)
+	values[1].Add(values[1], bn256.Order)
+	values[2].Neg(values[2])
+	blinding := randomScalars(t, 1)[0]
+
+	c, err := pp.Commit(values, blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := bare.Commit(values, blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equal(d) {
+		t.Fatal("fixed-base commitment differs from the generic one")
+	}
+}
+
+func TestHomomorphic(t *testing.T) {
+	pp := Setup([]byte("test"), 4)
+	a, b := randomScalars(t, 4), randomScalars(t, 4)
+	ra, rb := randomScalars(t, 1)[0], randomScalars(t, 1)[0]
+
+	ca, _ := pp.Commit(a, ra)
+	cb, _ := pp.Commit(b, rb)
+	sum := new(Commitment).Add(ca, cb)
+
+	values := make([]*big.Int, 4)
+	for i := range values {
+		values[i] = new(big.Int).Add(a[i], b[i])
+	}
+	if !pp.Verify(sum, values, new(big.Int).Add(ra, rb)) {
+		t.Fatal("sum of commitments does not open to sum of values")
+	}
+
+	diff := new(Commitment).Add(ca, new(Commitment).Neg(cb))
+	for i := range values {
+		values[i].Sub(a[i], b[i])
+	}
+	if !pp.Verify(diff, values, new(big.Int).Sub(ra, rb)) {
+		t.Fatal("difference of commitments does not open to difference of values")
+	}
+}
+
+func TestCommitmentMarshal(t *testing.T) {
+	pp := Setup([]byte("test"), 2)
+	c, _ := pp.Commit(randomScalars(t, 2), randomScalars(t, 1)[0])
+
+	d := new(Commitment)
+	if _, err := d.Unmarshal(c.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equal(d) {
+		t.Fatal("commitment changed after round trip")
+	}
+}
+
+func BenchmarkCommit(b *testing.B) {
+	pp := Setup([]byte("bench"), 64)
+	values := make([]*big.Int, 64)
+	for i := range values {
+		values[i], _ = rand.Int(rand.Reader, bn256.Order)
+	}
+	blinding, _ := rand.Int(rand.Reader, bn256.Order)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		pp.Commit(values, blinding)
+	}
+}
@@ -0,0 +1,249 @@
+package plonk
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// The snarkjs JSON files encode field elements as decimal strings and points
+// as projective coordinate arrays. G₂ coordinates are pairs [c0, c1] holding
+// the value c0 + c1·i.
+
+type jsonVerifyingKey struct {
+	Protocol string     `json:"protocol"`
+	Curve    string     `json:"curve"`
+	NPublic  int        `json:"nPublic"`
+	Power    int        `json:"power"`
+	K1       string     `json:"k1"`
+	K2       string     `json:"k2"`
+	Qm       []string   `json:"Qm"`
+	Ql       []string   `json:"Ql"`
+	Qr       []string   `json:"Qr"`
+	Qo       []string   `json:"Qo"`
+	Qc       []string   `json:"Qc"`
+	S1       []string   `json:"S1"`
+	S2       []string   `json:"S2"`
+	S3       []string   `json:"S3"`
+	X2       [][]string `json:"X_2"`
+	W        string     `json:"w"`
+}
+
+type jsonProof struct {
+	Protocol string   `json:"protocol"`
+	Curve    string   `json:"curve"`
+	A        []string `json:"A"`
+	B        []string `json:"B"`
+	C        []string `json:"C"`
+	Z        []string `json:"Z"`
+	T1       []string `json:"T1"`
+	T2       []string `json:"T2"`
+	T3       []string `json:"T3"`
+	Wxi      []string `json:"Wxi"`
+	Wxiw     []string `json:"Wxiw"`
+	EvalA    string   `json:"eval_a"`
+	EvalB    string   `json:"eval_b"`
+	EvalC    string   `json:"eval_c"`
+	EvalS1   string   `json:"eval_s1"`
+	EvalS2   string   `json:"eval_s2"`
+	EvalZw   string   `json:"eval_zw"`
+}
+
+var (
+	errProtocol = errors.New("plonk: not a bn128 PLONK file")
+	errNumber   = errors.New("plonk: malformed number")
+	errPoint    = errors.New("plonk: malformed point")
+)
+
+// UnmarshalJSON sets vk to the verifying key in a snarkjs
+// verification_key.json file.
+func (vk *VerifyingKey) UnmarshalJSON(data []byte) error {
+	var j jsonVerifyingKey
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Protocol != "plonk" || j.Curve != "bn128" {
+		return errProtocol
+	}
+	if j.NPublic < 0 || j.Power < 1 || j.Power > 28 {
+		return errors.New("plonk: malformed verifying key")
+	}
+
+	p := &parser{}
+	k := VerifyingKey{NPublic: j.NPublic, Power: j.Power}
+	k.K1 = p.scalar(j.K1)
+	k.K2 = p.scalar(j.K2)
+	k.Omega = p.scalar(j.W)
+	k.Qm = p.g1(j.Qm)
+	k.Ql = p.g1(j.Ql)
+	k.Qr = p.g1(j.Qr)
+	k.Qo = p.g1(j.Qo)
+	k.Qc = p.g1(j.Qc)
+	k.S1 = p.g1(j.S1)
+	k.S2 = p.g1(j.S2)
+	k.S3 = p.g1(j.S3)
+	k.X2 = p.g2(j.X2)
+	if p.err != nil {
+		return p.err
+	}
+	*vk = k
+	return nil
+}
+
+// UnmarshalJSON sets proof to the proof in a snarkjs proof.json file.
+func (proof *Proof) UnmarshalJSON(data []byte) error {
+	var j jsonProof
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Protocol != "plonk" || (j.Curve != "" && j.Curve != "bn128") {
+		return errProtocol
+	}
+
+	p := &parser{}
+	pr := Proof{
+		A:      p.g1(j.A),
+		B:      p.g1(j.B),
+		C:      p.g1(j.C),
+		Z:      p.g1(j.Z),
+		T1:     p.g1(j.T1),
+		T2:     p.g1(j.T2),
+		T3:     p.g1(j.T3),
+		Wxi:    p.g1(j.Wxi),
+		Wxiw:   p.g1(j.Wxiw),
+		EvalA:  p.scalar(j.EvalA),
+		EvalB:  p.scalar(j.EvalB),
+		EvalC:  p.scalar(j.EvalC),
+		EvalS1: p.scalar(j.EvalS1),
+		EvalS2: p.scalar(j.EvalS2),
+		EvalZw: p.scalar(j.EvalZw),
+	}
+	if p.err != nil {
+		return p.err
+	}
+	*proof = pr
+	return nil
+}
+
+// ParsePublicSignals parses the contents of a snarkjs public.json file.
+func ParsePublicSignals(data []byte) ([]*big.Int, error) {
+	var j []string
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+
+	p := &parser{}
+	signals := make([]*big.Int, len(j))
+	for i, s := range j {
+		signals[i] = p.scalar(s)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	return signals, nil
+}
+
+// parser decodes snarkjs numbers and points, remembering the first error it
+// encounters so that a whole file can be decoded before checking for errors.
+type parser struct {
+	err error
+}
+
+func (p *parser) number(s string, modulus *big.Int) *big.Int {
+	if p.err != nil {
+		return nil
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Sign() < 0 || n.Cmp(modulus) >= 0 {
+		p.err = errNumber
+		return nil
+	}
+	return n
+}
+
+func (p *parser) scalar(s string) *big.Int {
+	return p.number(s, bn256.Order)
+}
+
+func (p *parser) g1(c []string) *bn256.G1 {
+	if p.err != nil {
+		return nil
+	}
+	if len(c) != 3 {
+		p.err = errPoint
+		return nil
+	}
+
+	// Each value is a 256-bit number.
+	const numBytes = 256 / 8
+	buf := make([]byte, 2*numBytes)
+
+	switch c[2] {
+	case "0":
+		// The point at infinity, which Unmarshal encodes as zeros.
+	case "1":
+		putNumber(buf[:numBytes], p.number(c[0], bn256.P))
+		putNumber(buf[numBytes:], p.number(c[1], bn256.P))
+	default:
+		p.err = errPoint
+	}
+	if p.err != nil {
+		return nil
+	}
+
+	e := new(bn256.G1)
+	if _, err := e.Unmarshal(buf); err != nil {
+		p.err = err
+		return nil
+	}
+	return e
+}
+
+func (p *parser) g2(c [][]string) *bn256.G2 {
+	if p.err != nil {
+		return nil
+	}
+	if len(c) != 3 || len(c[0]) != 2 || len(c[1]) != 2 || len(c[2]) != 2 {
+		p.err = errPoint
+		return nil
+	}
+
+	// Each value is a 256-bit number.
+	const numBytes = 256 / 8
+	buf := make([]byte, 4*numBytes)
+
+	switch {
+	case c[2][0] == "0" && c[2][1] == "0":
+		// The point at infinity, which Unmarshal encodes as zeros.
+	case c[2][0] == "1" && c[2][1] == "0":
+		// G2.Unmarshal expects the imaginary part of each coordinate
+		// first.
+		putNumber(buf[:numBytes], p.number(c[0][1], bn256.P))
+		putNumber(buf[numBytes:2*numBytes], p.number(c[0][0], bn256.P))
+		putNumber(buf[2*numBytes:3*numBytes], p.number(c[1][1], bn256.P))
+		putNumber(buf[3*numBytes:], p.number(c[1][0], bn256.P))
+	default:
+		p.err = errPoint
+	}
+	if p.err != nil {
+		return nil
+	}
+
+	e := new(bn256.G2)
+	if _, err := e.Unmarshal(buf); err != nil {
+		p.err = err
+		return nil
+	}
+	return e
+}
+
+// putNumber writes n into out as a big-endian number of len(out) bytes.
+func putNumber(out []byte, n *big.Int) {
+	if n == nil {
+		return
+	}
+	b := n.Bytes()
+	copy(out[len(out)-len(b):], b)
+}
@@ -0,0 +1,315 @@
+// Package plonk implements a verifier for PLONK proofs produced by snarkjs.
+//
+// Verifying keys and proofs are read from the verification_key.json and
+// proof.json files written by snarkjs. The Fiat-Shamir challenges are
+// recomputed with the same Keccak-256 transcript as snarkjs, so a proof that
+// snarkjs accepts for a given set of public signals is also accepted here.
+// The final KZG batch opening check is performed with bn256.PairingCheck.
+package plonk
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// VerifyingKey is a PLONK verifying key as exported by snarkjs.
+type VerifyingKey struct {
+	// NPublic is the number of public signals of the circuit.
+	NPublic int
+	// Power is the base two logarithm of the domain size.
+	Power int
+	// K1 and K2 are the coset generators of the copy constraints.
+	K1, K2 *big.Int
+	// Omega is a primitive 2^Power-th root of unity.
+	Omega *big.Int
+
+	Qm, Ql, Qr, Qo, Qc *bn256.G1
+	S1, S2, S3         *bn256.G1
+
+	// X2 is the trusted setup point τ·g₂.
+	X2 *bn256.G2
+}
+
+// Proof is a PLONK proof as exported by snarkjs.
+type Proof struct {
+	A, B, C    *bn256.G1
+	Z          *bn256.G1
+	T1, T2, T3 *bn256.G1
+	Wxi, Wxiw  *bn256.G1
+
+	EvalA, EvalB, EvalC *big.Int
+	EvalS1, EvalS2      *big.Int
+	EvalZw              *big.Int
+}
+
+// challenges holds the Fiat-Shamir challenges of a proof, together with the
+// values derived from them that are used more than once by the verifier.
+type challenges struct {
+	beta, gamma, alpha, xi, u *big.Int
+	v                         [6]*big.Int
+
+	xin, zh *big.Int
+}
+
+var (
+	errPublicCount = errors.New("plonk: wrong number of public signals")
+	errMalformed   = errors.New("plonk: malformed proof")
+	errInvalid     = errors.New("plonk: invalid proof")
+)
+
+// Verify checks that proof is a valid proof for the given public signals
+// under vk. It returns nil if the proof is valid.
+func Verify(vk *VerifyingKey, proof *Proof, publicSignals []*big.Int) error {
+	if len(publicSignals) != vk.NPublic {
+		return errPublicCount
+	}
+	if !proof.wellFormed() {
+		return errMalformed
+	}
+	for _, s := range publicSignals {
+		if s.Sign() < 0 || s.Cmp(bn256.Order) >= 0 {
+			return errMalformed
+		}
+	}
+
+	ch := computeChallenges(vk, proof, publicSignals)
+	if ch.zh.Sign() == 0 {
+		// xi lies in the evaluation domain, so the Lagrange evaluations
+		// are undefined.
+		return errInvalid
+	}
+	L := lagrangeEvaluations(vk, ch)
+	pi := publicInput(publicSignals, L)
+
+	r0 := computeR0(proof, ch, L, pi)
+	D := computeD(vk, proof, ch, L)
+	F := computeF(vk, proof, ch, D)
+	E := computeE(proof, ch, r0)
+
+	// A1 = Wxi + u·Wxiw
+	A1 := new(bn256.G1).ScalarMult(proof.Wxiw, ch.u)
+	A1.Add(A1, proof.Wxi)
+	A1.Neg(A1)
+
+	// B1 = xi·Wxi + u·xi·ω·Wxiw + F - E
+	B1 := new(bn256.G1).ScalarMult(proof.Wxi, ch.xi)
+	s := mul(ch.u, ch.xi)
+	s = mul(s, vk.Omega)
+	t := new(bn256.G1).ScalarMult(proof.Wxiw, s)
+	B1.Add(B1, t)
+	B1.Add(B1, F)
+	t.Neg(E)
+	B1.Add(B1, t)
+
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	if !bn256.PairingCheck([]*bn256.G1{A1, B1}, []*bn256.G2{vk.X2, g2}) {
+		return errInvalid
+	}
+	return nil
+}
+
+func (p *Proof) wellFormed() bool {
+	points := []*bn256.G1{p.A, p.B, p.C, p.Z, p.T1, p.T2, p.T3, p.Wxi, p.Wxiw}
+	for _, pt := range points {
+		if pt == nil {
+			return false
+		}
+	}
+	evals := []*big.Int{p.EvalA, p.EvalB, p.EvalC, p.EvalS1, p.EvalS2, p.EvalZw}
+	for _, e := range evals {
+		if e == nil || e.Sign() < 0 || e.Cmp(bn256.Order) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func computeChallenges(vk *VerifyingKey, proof *Proof, publicSignals []*big.Int) *challenges {
+	ch := &challenges{}
+	t := newTranscript()
+
+	t.appendPoint(vk.Qm)
+	t.appendPoint(vk.Ql)
+	t.appendPoint(vk.Qr)
+	t.appendPoint(vk.Qo)
+	t.appendPoint(vk.Qc)
+	t.appendPoint(vk.S1)
+	t.appendPoint(vk.S2)
+	t.appendPoint(vk.S3)
+	for _, s := range publicSignals {
+		t.appendScalar(s)
+	}
+	t.appendPoint(proof.A)
+	t.appendPoint(proof.B)
+	t.appendPoint(proof.C)
+	ch.beta = t.challenge()
+
+	t.reset()
+	t.appendScalar(ch.beta)
+	ch.gamma = t.challenge()
+
+	t.reset()
+	t.appendScalar(ch.beta)
+	t.appendScalar(ch.gamma)
+	t.appendPoint(proof.Z)
+	ch.alpha = t.challenge()
+
+	t.reset()
+	t.appendScalar(ch.alpha)
+	t.appendPoint(proof.T1)
+	t.appendPoint(proof.T2)
+	t.appendPoint(proof.T3)
+	ch.xi = t.challenge()
+
+	t.reset()
+	t.appendScalar(ch.xi)
+	t.appendScalar(proof.EvalA)
+	t.appendScalar(proof.EvalB)
+	t.appendScalar(proof.EvalC)
+	t.appendScalar(proof.EvalS1)
+	t.appendScalar(proof.EvalS2)
+	t.appendScalar(proof.EvalZw)
+	ch.v[1] = t.challenge()
+	for i := 2; i < len(ch.v); i++ {
+		ch.v[i] = mul(ch.v[i-1], ch.v[1])
+	}
+
+	t.reset()
+	t.appendPoint(proof.Wxi)
+	t.appendPoint(proof.Wxiw)
+	ch.u = t.challenge()
+
+	ch.xin = new(big.Int).Set(ch.xi)
+	for i := 0; i < vk.Power; i++ {
+		ch.xin = mul(ch.xin, ch.xin)
+	}
+	ch.zh = sub(ch.xin, big.NewInt(1))
+	return ch
+}
+
+// lagrangeEvaluations returns the evaluations at xi of the Lagrange basis
+// polynomials L₁, …, Lₘ where m = max(1, NPublic). The returned slice is
+// indexed from one.
+func lagrangeEvaluations(vk *VerifyingKey, ch *challenges) []*big.Int {
+	n := new(big.Int).Lsh(big.NewInt(1), uint(vk.Power))
+
+	count := vk.NPublic
+	if count < 1 {
+		count = 1
+	}
+	L := make([]*big.Int, count+1)
+
+	w := big.NewInt(1)
+	for i := 1; i <= count; i++ {
+		num := mul(w, ch.zh)
+		den := mul(n, sub(ch.xi, w))
+		L[i] = mul(num, inv(den))
+		w = mul(w, vk.Omega)
+	}
+	return L
+}
+
+func publicInput(publicSignals, L []*big.Int) *big.Int {
+	pi := new(big.Int)
+	for i, s := range publicSignals {
+		pi = sub(pi, mul(s, L[i+1]))
+	}
+	return pi
+}
+
+func computeR0(proof *Proof, ch *challenges, L []*big.Int, pi *big.Int) *big.Int {
+	alpha2 := mul(ch.alpha, ch.alpha)
+
+	e2 := mul(L[1], alpha2)
+
+	e3a := add(add(proof.EvalA, mul(ch.beta, proof.EvalS1)), ch.gamma)
+	e3b := add(add(proof.EvalB, mul(ch.beta, proof.EvalS2)), ch.gamma)
+	e3c := add(proof.EvalC, ch.gamma)
+	e3 := mul(mul(mul(e3a, e3b), mul(e3c, proof.EvalZw)), ch.alpha)
+
+	return sub(sub(pi, e2), e3)
+}
+
+func computeD(vk *VerifyingKey, proof *Proof, ch *challenges, L []*big.Int) *bn256.G1 {
+	a, b, c := proof.EvalA, proof.EvalB, proof.EvalC
+
+	// d1 = Qm·a·b + Ql·a + Qr·b + Qo·c + Qc
+	d1 := new(bn256.G1).ScalarMult(vk.Qm, mul(a, b))
+	t := new(bn256.G1).ScalarMult(vk.Ql, a)
+	d1.Add(d1, t)
+	t.ScalarMult(vk.Qr, b)
+	d1.Add(d1, t)
+	t.ScalarMult(vk.Qo, c)
+	d1.Add(d1, t)
+	d1.Add(d1, vk.Qc)
+
+	// d2 = Z·((a+β·xi+γ)(b+β·k1·xi+γ)(c+β·k2·xi+γ)·α + L₁·α² + u)
+	betaxi := mul(ch.beta, ch.xi)
+	d2a1 := add(add(a, betaxi), ch.gamma)
+	d2a2 := add(add(b, mul(betaxi, vk.K1)), ch.gamma)
+	d2a3 := add(add(c, mul(betaxi, vk.K2)), ch.gamma)
+	d2a := mul(mul(mul(d2a1, d2a2), d2a3), ch.alpha)
+	d2b := mul(L[1], mul(ch.alpha, ch.alpha))
+	d2 := new(bn256.G1).ScalarMult(proof.Z, add(add(d2a, d2b), ch.u))
+
+	// d3 = S3·((a+β·s1+γ)(b+β·s2+γ)·α·β·zω)
+	d3a := add(add(a, mul(ch.beta, proof.EvalS1)), ch.gamma)
+	d3b := add(add(b, mul(ch.beta, proof.EvalS2)), ch.gamma)
+	d3c := mul(mul(ch.alpha, ch.beta), proof.EvalZw)
+	d3 := new(bn256.G1).ScalarMult(vk.S3, mul(mul(d3a, d3b), d3c))
+
+	// d4 = (T1 + xⁿ·T2 + x²ⁿ·T3)·Zh(xi)
+	d4 := new(bn256.G1).ScalarMult(proof.T2, ch.xin)
+	t.ScalarMult(proof.T3, mul(ch.xin, ch.xin))
+	d4.Add(d4, t)
+	d4.Add(d4, proof.T1)
+	d4.ScalarMult(d4, ch.zh)
+
+	D := new(bn256.G1).Add(d1, d2)
+	D.Add(D, t.Neg(d3))
+	D.Add(D, t.Neg(d4))
+	return D
+}
+
+func computeF(vk *VerifyingKey, proof *Proof, ch *challenges, D *bn256.G1) *bn256.G1 {
+	F := new(bn256.G1).Set(D)
+	t := new(bn256.G1)
+
+	points := []*bn256.G1{proof.A, proof.B, proof.C, vk.S1, vk.S2}
+	for i, p := range points {
+		t.ScalarMult(p, ch.v[i+1])
+		F.Add(F, t)
+	}
+	return F
+}
+
+func computeE(proof *Proof, ch *challenges, r0 *big.Int) *bn256.G1 {
+	e := new(big.Int).Neg(r0)
+	evals := []*big.Int{proof.EvalA, proof.EvalB, proof.EvalC, proof.EvalS1, proof.EvalS2}
+	for i, ev := range evals {
+		e = add(e, mul(ch.v[i+1], ev))
+	}
+	e = add(e, mul(ch.u, proof.EvalZw))
+	return new(bn256.G1).ScalarBaseMult(e)
+}
+
+func add(a, b *big.Int) *big.Int {
+	r := new(big.Int).Add(a, b)
+	return r.Mod(r, bn256.Order)
+}
+
+func sub(a, b *big.Int) *big.Int {
+	r := new(big.Int).Sub(a, b)
+	return r.Mod(r, bn256.Order)
+}
+
+func mul(a, b *big.Int) *big.Int {
+	r := new(big.Int).Mul(a, b)
+	return r.Mod(r, bn256.Order)
+}
+
+func inv(a *big.Int) *big.Int {
+	return new(big.Int).ModInverse(a, bn256.Order)
+}
@@ -0,0 +1,89 @@
+package plonk
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+)
+
+// The fixtures in testdata are snarkjs PLONK files for a circuit proving
+// knowledge of x such that x³+x+5 equals the single public signal.
+// proof.json is for x=3 and proof_2.json for x=5.
+
+func loadFixture(t *testing.T, proofName, publicName string) (*VerifyingKey, *Proof, []*big.Int) {
+	t.Helper()
+
+	read := func(name string) []byte {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	vk := new(VerifyingKey)
+	if err := json.Unmarshal(read("verification_key.json"), vk); err != nil {
+		t.Fatal(err)
+	}
+	proof := new(Proof)
+	if err := json.Unmarshal(read(proofName), proof); err != nil {
+		t.Fatal(err)
+	}
+	signals, err := ParsePublicSignals(read(publicName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return vk, proof, signals
+}
+
+func TestVerify(t *testing.T) {
+	for _, f := range [][2]string{{"proof.json", "public.json"}, {"proof_2.json", "public_2.json"}} {
+		vk, proof, signals := loadFixture(t, f[0], f[1])
+		if err := Verify(vk, proof, signals); err != nil {
+			t.Errorf("%s: %v", f[0], err)
+		}
+	}
+}
+
+func TestVerifyWrongPublicSignals(t *testing.T) {
+	vk, proof, _ := loadFixture(t, "proof.json", "public.json")
+	_, _, other := loadFixture(t, "proof_2.json", "public_2.json")
+
+	if err := Verify(vk, proof, other); err != errInvalid {
+		t.Errorf("proof verified against wrong public signals: %v", err)
+	}
+	if err := Verify(vk, proof, nil); err != errPublicCount {
+		t.Errorf("proof verified with missing public signals: %v", err)
+	}
+}
+
+func TestVerifyTampered(t *testing.T) {
+	vk, proof, signals := loadFixture(t, "proof.json", "public.json")
+	proof.EvalA = add(proof.EvalA, big.NewInt(1))
+	if err := Verify(vk, proof, signals); err != errInvalid {
+		t.Errorf("tampered evaluation accepted: %v", err)
+	}
+
+	vk, proof, signals = loadFixture(t, "proof.json", "public.json")
+	_, other, _ := loadFixture(t, "proof_2.json", "public_2.json")
+	proof.Wxi = other.Wxi
+	if err := Verify(vk, proof, signals); err != errInvalid {
+		t.Errorf("tampered opening accepted: %v", err)
+	}
+}
+
+func TestUnmarshalRejectsMalformed(t *testing.T) {
+	tests := []string{
+		`{"protocol":"groth16","curve":"bn128"}`,
+		`{"protocol":"plonk","curve":"bn128","A":["1","3","1"]}`,
+		`{"protocol":"plonk","curve":"bn128","A":["1","2","2"]}`,
+		`{"protocol":"plonk","curve":"bn128","A":["x","2","1"]}`,
+	}
+	for _, test := range tests {
+		if err := json.Unmarshal([]byte(test), new(Proof)); err == nil {
+			t.Errorf("%s: no error", test)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package plonk
+
+import (
+	"hash"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+	"golang.org/x/crypto/sha3"
+)
+
+// transcript reproduces the Keccak256Transcript used by snarkjs. Points are
+// absorbed as their uncompressed big-endian affine coordinates and scalars as
+// 32 big-endian bytes. A challenge is the Keccak-256 digest of everything
+// absorbed since the last reset, reduced modulo Order.
+type transcript struct {
+	h hash.Hash
+}
+
+func newTranscript() *transcript {
+	return &transcript{h: sha3.NewLegacyKeccak256()}
+}
+
+func (t *transcript) reset() {
+	t.h.Reset()
+}
+
+func (t *transcript) appendPoint(p *bn256.G1) {
+	t.h.Write(p.Marshal())
+}
+
+func (t *transcript) appendScalar(s *big.Int) {
+	var buf [32]byte
+	b := s.Bytes()
+	copy(buf[32-len(b):], b)
+	t.h.Write(buf[:])
+}
+
+func (t *transcript) challenge() *big.Int {
+	c := new(big.Int).SetBytes(t.h.Sum(nil))
+	return c.Mod(c, bn256.Order)
+}
@@ -0,0 +1,189 @@
+package ps
+
+import (
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/transcript"
+)
+
+// BlindRequest asks a signer to sign messages of which some are hidden in a
+// commitment
+//
+//	C = t·g + Σ mᵢ·Yᵢ
+//
+// over the hidden indices, together with a proof of knowledge of its opening.
+type BlindRequest struct {
+	C      *bn256.G1
+	Hidden []int // indices of the committed messages, in increasing order
+
+	Challenge *big.Int
+	ST        *big.Int
+	SM        []*big.Int // responses for the hidden messages
+}
+
+// NewBlindRequest returns a request for a signature of messages under pk in
+// which the messages at the hidden indices are committed to, and the
+// blinding factor needed to unblind the signature. Randomness is read from r.
+func NewBlindRequest(r io.Reader, pk *PublicKey, messages []*big.Int, hidden []int) (*BlindRequest, *big.Int, error) {
+	if len(messages) != len(pk.Y) {
+		return nil, nil, errCount
+	}
+	hidden, _, err := splitIndices(hidden, len(messages))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rs, err := randomScalars(r, 2+len(hidden))
+	if err != nil {
+		return nil, nil, err
+	}
+	t, tTilde, mTilde := rs[0], rs[1], rs[2:]
+
+	points, scalars := commitBases(pk, hidden), []*big.Int{t}
+	for _, j := range hidden {
+		scalars = append(scalars, messages[j])
+	}
+	C := new(bn256.G1).MultiScalarMult(points, scalars)
+	T := new(bn256.G1).MultiScalarMult(points, append([]*big.Int{tTilde}, mTilde...))
+
+	c := commitChallenge(pk, hidden, C, T)
+	req := &BlindRequest{
+		C:         C,
+		Hidden:    hidden,
+		Challenge: c,
+		ST:        add(tTilde, mul(t, c)),
+		SM:        make([]*big.Int, len(hidden)),
+	}
+	for i, j := range hidden {
+		req.SM[i] = add(mTilde[i], mul(messages[j], c))
+	}
+	return req, t, nil
+}
+
+// Verify returns nil iff the proof of knowledge in req is valid under pk.
+func (req *BlindRequest) Verify(pk *PublicKey) error {
+	if req.C == nil || *req.C == (bn256.G1{}) || req.Challenge == nil || req.ST == nil || len(req.SM) != len(req.Hidden) {
+		return errMalformed
+	}
+	for _, s := range req.SM {
+		if s == nil || s.Sign() < 0 || s.Cmp(bn256.Order) >= 0 {
+			return errMalformed
+		}
+	}
+	hidden, _, err := splitIndices(req.Hidden, len(pk.Y))
+	if err != nil {
+		return err
+	}
+	if !sort.IntsAreSorted(req.Hidden) {
+		return errIndex
+	}
+
+	// T = s_t·g + Σ sᵢ·Yᵢ - c·C
+	points := append(commitBases(pk, hidden), req.C)
+	scalars := append([]*big.Int{req.ST}, req.SM...)
+	scalars = append(scalars, new(big.Int).Sub(bn256.Order, req.Challenge))
+	T := new(bn256.G1).MultiScalarMult(points, scalars)
+
+	if commitChallenge(pk, hidden, req.C, T).Cmp(req.Challenge) != 0 {
+		return errProof
+	}
+	return nil
+}
+
+// BlindSign returns a blinded signature for req under sk, which must match
+// pk, after checking the proof in req. messages holds the messages that are
+// not hidden; its entries at hidden indices are ignored. Randomness is read
+// from r.
+func BlindSign(r io.Reader, sk *SecretKey, pk *PublicKey, req *BlindRequest, messages []*big.Int) (*Signature, error) {
+	if len(messages) != len(sk.Y) {
+		return nil, errCount
+	}
+	if err := req.Verify(pk); err != nil {
+		return nil, err
+	}
+	u, err := randomNonZero(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// σ₂ = u·(X + C + Σ mⱼ·Yⱼ) over the known messages, where X = x·g.
+	hidden := make([]bool, len(messages))
+	for _, j := range req.Hidden {
+		hidden[j] = true
+	}
+	e := new(big.Int).Set(sk.X)
+	for j, m := range messages {
+		if !hidden[j] {
+			e.Add(e, new(big.Int).Mul(sk.Y[j], m))
+		}
+	}
+	e.Mul(e, u)
+	e.Mod(e, bn256.Order)
+
+	sigma2 := new(bn256.G1).ScalarMult(req.C, u)
+	sigma2.Add(sigma2, new(bn256.G1).ScalarBaseMult(e))
+	return &Signature{
+		Sigma1: new(bn256.G1).ScalarBaseMult(u),
+		Sigma2: sigma2,
+	}, nil
+}
+
+// Unblind returns the signature of the messages in a blind request from the
+// blinded signature sig, given the blinding factor t returned with the
+// request.
+func Unblind(sig *Signature, t *big.Int) *Signature {
+	sigma2 := new(bn256.G1).ScalarMult(sig.Sigma1, t)
+	sigma2.Neg(sigma2)
+	return &Signature{
+		Sigma1: new(bn256.G1).Set(sig.Sigma1),
+		Sigma2: sigma2.Add(sigma2, sig.Sigma2),
+	}
+}
+
+// commitBases returns g followed by the Yᵢ at the hidden indices.
+func commitBases(pk *PublicKey, hidden []int) []*bn256.G1 {
+	points := []*bn256.G1{new(bn256.G1).ScalarBaseMult(big.NewInt(1))}
+	for _, j := range hidden {
+		points = append(points, pk.Y[j])
+	}
+	return points
+}
+
+// commitChallenge returns the Fiat-Shamir challenge of a blind request.
+func commitChallenge(pk *PublicKey, hidden []int, C, T *bn256.G1) *big.Int {
+	t := transcript.New(commitDomain)
+	t.AppendMessage("n", appendUint64(nil, uint64(len(pk.Y))))
+	for _, Y := range pk.Y {
+		t.AppendG1("Y", Y)
+	}
+	t.AppendMessage("hidden", appendUint64(nil, uint64(len(hidden))))
+	for _, j := range hidden {
+		t.AppendMessage("i", appendUint64(nil, uint64(j)))
+	}
+	t.AppendG1("C", C)
+	t.AppendG1("T", T)
+	return t.ChallengeScalar("c")
+}
+
+// splitIndices returns the sorted indices out of n that are in indices and
+// the sorted ones that are not.
+func splitIndices(indices []int, n int) (in, out []int, err error) {
+	seen := make([]bool, n)
+	for _, j := range indices {
+		if j < 0 || j >= n || seen[j] {
+			return nil, nil, errIndex
+		}
+		seen[j] = true
+	}
+	in = append([]int(nil), indices...)
+	sort.Ints(in)
+	for j := 0; j < n; j++ {
+		if !seen[j] {
+			out = append(out, j)
+		}
+	}
+	return in, out, nil
+}
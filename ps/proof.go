@@ -0,0 +1,202 @@
+package ps
+
+import (
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/transcript"
+)
+
+// Proof is a zero-knowledge proof of possession of a signature on a vector of
+// messages, of which some are disclosed. It consists of a randomized
+// signature σ' on the messages and an extra message t, and
+//
+//	K = t·g̃ + Σ mᵢ·Ỹᵢ
+//
+// over the undisclosed messages, which hides them because t is random,
+// together with a proof of knowledge of its opening. The verifier checks
+// e(σ'₁, X̃ + K + Σ mⱼ·Ỹⱼ) = e(σ'₂, g̃) over the disclosed messages.
+type Proof struct {
+	Sigma *Signature
+	K     *bn256.G2
+
+	C  *big.Int
+	ST *big.Int
+	SM []*big.Int // responses for the undisclosed messages
+}
+
+// ProvePossession returns a proof of possession of sig, which must be a
+// signature of messages under pk, that discloses the messages at the given
+// indices. nonce is bound to the proof, for example to prevent its replay.
+// Randomness is read from r.
+func ProvePossession(r io.Reader, pk *PublicKey, sig *Signature, messages []*big.Int, disclosed []int, nonce []byte) (*Proof, error) {
+	disclosed, undisclosed, err := splitIndices(disclosed, len(messages))
+	if err != nil {
+		return nil, err
+	}
+	if err := Verify(pk, sig, messages); err != nil {
+		return nil, err
+	}
+
+	u, err := randomNonZero(r)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := randomScalars(r, 2+len(undisclosed))
+	if err != nil {
+		return nil, err
+	}
+	t, tTilde, mTilde := rs[0], rs[1], rs[2:]
+
+	// σ' = (u·σ₁, u·(σ₂ + t·σ₁)) is a signature on the messages and t.
+	sigma1 := new(bn256.G1).ScalarMult(sig.Sigma1, u)
+	sigma2 := new(bn256.G1).ScalarMult(sig.Sigma1, t)
+	sigma2.Add(sigma2, sig.Sigma2)
+	sigma2.ScalarMult(sigma2, u)
+
+	points := possessionBases(pk, undisclosed)
+	scalars := []*big.Int{t}
+	for _, j := range undisclosed {
+		scalars = append(scalars, messages[j])
+	}
+	K := multiScalarMultG2(points, scalars)
+	T := multiScalarMultG2(points, append([]*big.Int{tTilde}, mTilde...))
+
+	proof := &Proof{
+		Sigma: &Signature{Sigma1: sigma1, Sigma2: sigma2},
+		K:     K,
+		SM:    make([]*big.Int, len(undisclosed)),
+	}
+	proof.C = possessionChallenge(pk, proof, T, disclosed, messages, nonce)
+	proof.ST = add(tTilde, mul(t, proof.C))
+	for i, j := range undisclosed {
+		proof.SM[i] = add(mTilde[i], mul(messages[j], proof.C))
+	}
+	return proof, nil
+}
+
+// VerifyPossession returns nil iff proof is a valid proof of possession of a
+// signature under pk on messages including disclosedMessages at
+// disclosedIndices, bound to nonce.
+func VerifyPossession(pk *PublicKey, proof *Proof, disclosedIndices []int, disclosedMessages []*big.Int, nonce []byte) error {
+	if len(disclosedIndices) != len(disclosedMessages) || proof.Sigma == nil || proof.Sigma.Sigma1 == nil ||
+		proof.Sigma.Sigma2 == nil || proof.K == nil || proof.C == nil || proof.ST == nil {
+		return errMalformed
+	}
+	for _, s := range proof.SM {
+		if s == nil {
+			return errMalformed
+		}
+	}
+	n := len(disclosedIndices) + len(proof.SM)
+	if n != len(pk.YTilde) {
+		return errCount
+	}
+	if isIdentity(proof.Sigma.Sigma1.Marshal()) {
+		return errInvalid
+	}
+
+	disclosed, undisclosed, err := splitIndices(disclosedIndices, n)
+	if err != nil {
+		return err
+	}
+	messages := make([]*big.Int, n)
+	for i, j := range disclosedIndices {
+		messages[j] = disclosedMessages[i]
+	}
+
+	// T = s_t·g̃ + Σ sᵢ·Ỹᵢ - c·K
+	points := append(possessionBases(pk, undisclosed), proof.K)
+	scalars := append([]*big.Int{proof.ST}, proof.SM...)
+	scalars = append(scalars, new(big.Int).Sub(bn256.Order, proof.C))
+	T := multiScalarMultG2(points, scalars)
+	if possessionChallenge(pk, proof, T, disclosed, messages, nonce).Cmp(proof.C) != 0 {
+		return errProof
+	}
+
+	K := new(bn256.G2).Add(pk.XTilde, proof.K)
+	for _, j := range disclosed {
+		K.Add(K, new(bn256.G2).ScalarMult(pk.YTilde[j], messages[j]))
+	}
+	return check(proof.Sigma, K)
+}
+
+// possessionBases returns g̃ followed by the Ỹᵢ at the undisclosed indices.
+func possessionBases(pk *PublicKey, undisclosed []int) []*bn256.G2 {
+	points := []*bn256.G2{new(bn256.G2).ScalarBaseMult(big.NewInt(1))}
+	for _, j := range undisclosed {
+		points = append(points, pk.YTilde[j])
+	}
+	return points
+}
+
+// possessionChallenge returns the Fiat-Shamir challenge of a proof of
+// possession.
+func possessionChallenge(pk *PublicKey, proof *Proof, T *bn256.G2, disclosed []int, messages []*big.Int, nonce []byte) *big.Int {
+	t := transcript.New(proofDomain)
+	t.AppendG2("XTilde", pk.XTilde)
+	t.AppendMessage("n", appendUint64(nil, uint64(len(pk.YTilde))))
+	for _, Y := range pk.YTilde {
+		t.AppendG2("YTilde", Y)
+	}
+	t.AppendMessage("disclosed", appendUint64(nil, uint64(len(disclosed))))
+	for _, j := range disclosed {
+		t.AppendMessage("i", appendUint64(nil, uint64(j)))
+		t.AppendScalar("m", messages[j])
+	}
+	t.AppendG1("sigma1", proof.Sigma.Sigma1)
+	t.AppendG1("sigma2", proof.Sigma.Sigma2)
+	t.AppendG2("K", proof.K)
+	t.AppendG2("T", T)
+	t.AppendMessage("nonce", nonce)
+	return t.ChallengeScalar("c")
+}
+
+// multiScalarMultG2 returns Σ kᵢ·aᵢ.
+func multiScalarMultG2(a []*bn256.G2, k []*big.Int) *bn256.G2 {
+	sum := new(bn256.G2).ScalarBaseMult(new(big.Int))
+	for i := range a {
+		sum.Add(sum, new(bn256.G2).ScalarMult(a[i], k[i]))
+	}
+	return sum
+}
+
+// Marshal converts proof into a byte slice: σ' and K followed by c, s_t and
+// the sᵢ.
+func (proof *Proof) Marshal() []byte {
+	ret := append(proof.Sigma.Marshal(), proof.K.Marshal()...)
+	for _, k := range append([]*big.Int{proof.C, proof.ST}, proof.SM...) {
+		ret = appendScalar(ret, k)
+	}
+	return ret
+}
+
+// Unmarshal sets proof to the result of converting the output of Marshal back
+// into a proof. It consumes all of m, since the number of undisclosed messages
+// is not known in advance.
+func (proof *Proof) Unmarshal(m []byte) error {
+	const fixed = 2*64 + 128 + 2*32
+	if len(m) < fixed || (len(m)-fixed)%32 != 0 {
+		return errMalformed
+	}
+
+	sig, K := new(Signature), new(bn256.G2)
+	m, err := sig.Unmarshal(m)
+	if err != nil {
+		return err
+	}
+	if m, err = K.Unmarshal(m); err != nil {
+		return err
+	}
+	scalars := make([]*big.Int, len(m)/32)
+	for i := range scalars {
+		if scalars[i], m, err = readScalar(m); err != nil {
+			return err
+		}
+	}
+
+	proof.Sigma, proof.K = sig, K
+	proof.C, proof.ST, proof.SM = scalars[0], scalars[1], scalars[2:]
+	return nil
+}
@@ -0,0 +1,246 @@
+// Package ps implements Pointcheval-Sanders signatures.
+//
+// The secret key for signing vectors of n messages is (x, y₁, …, yₙ) and the
+// public key is (X̃, Ỹ₁, …, Ỹₙ) = (x·g̃, y₁·g̃, …, yₙ·g̃) in G₂, together with
+// Yᵢ = yᵢ·g in G₁ for blind signing. A signature on m₁, …, mₙ is
+//
+//	σ = (h, (x + Σ yᵢ·mᵢ)·h)
+//
+// for a random h ∈ G₁, and is checked with
+//
+//	e(σ₁, X̃ + Σ mᵢ·Ỹᵢ) = e(σ₂, g̃)
+//
+// With n = 1 this is the single-message scheme of the paper. Signatures are
+// two elements of G₁ and can be randomized into signatures that cannot be
+// linked to the original, and the holder of a signature can prove in zero
+// knowledge that it has one without revealing it, or some of the messages.
+// A user can also obtain a signature on messages that it only shows to the
+// signer in a commitment.
+//
+// See Pointcheval and Sanders, "Short Randomizable Signatures",
+// https://eprint.iacr.org/2015/525.
+package ps
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// Labels of the Fiat-Shamir transcripts.
+const (
+	commitDomain = "bn256/ps/commitment"
+	proofDomain  = "bn256/ps/proof"
+)
+
+var (
+	errInvalid   = errors.New("ps: invalid signature")
+	errMalformed = errors.New("ps: malformed input")
+	errCount     = errors.New("ps: wrong number of messages")
+	errIndex     = errors.New("ps: invalid or repeated message index")
+	errProof     = errors.New("ps: invalid proof")
+)
+
+// SecretKey is a secret key for signing vectors of len(Y) messages.
+type SecretKey struct {
+	X *big.Int
+	Y []*big.Int
+}
+
+// PublicKey is a public key for vectors of len(YTilde) messages.
+type PublicKey struct {
+	XTilde *bn256.G2
+	YTilde []*bn256.G2
+	// Y holds yᵢ·g, which users need to commit to messages for blind
+	// signing.
+	Y []*bn256.G1
+}
+
+// KeyGen returns a new key pair for signing vectors of n messages using
+// randomness from r.
+func KeyGen(r io.Reader, n int) (*SecretKey, *PublicKey, error) {
+	if n < 1 {
+		return nil, nil, errCount
+	}
+	ks, err := randomScalars(r, n+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sk := &SecretKey{X: ks[0], Y: ks[1:]}
+	pk := &PublicKey{
+		XTilde: new(bn256.G2).ScalarBaseMult(sk.X),
+		YTilde: make([]*bn256.G2, n),
+		Y:      make([]*bn256.G1, n),
+	}
+	for i, y := range sk.Y {
+		pk.YTilde[i] = new(bn256.G2).ScalarBaseMult(y)
+		pk.Y[i] = new(bn256.G1).ScalarBaseMult(y)
+	}
+	return sk, pk, nil
+}
+
+// Signature is a Pointcheval-Sanders signature.
+type Signature struct {
+	Sigma1, Sigma2 *bn256.G1
+}
+
+// Sign returns a signature of messages under sk using randomness from r.
+func Sign(r io.Reader, sk *SecretKey, messages []*big.Int) (*Signature, error) {
+	if len(messages) != len(sk.Y) {
+		return nil, errCount
+	}
+	u, err := randomNonZero(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// σ₂ = (x + Σ yᵢ·mᵢ)·u·g
+	e := new(big.Int).Set(sk.X)
+	for i, m := range messages {
+		e.Add(e, new(big.Int).Mul(sk.Y[i], m))
+	}
+	e.Mul(e, u)
+	e.Mod(e, bn256.Order)
+
+	return &Signature{
+		Sigma1: new(bn256.G1).ScalarBaseMult(u),
+		Sigma2: new(bn256.G1).ScalarBaseMult(e),
+	}, nil
+}
+
+// Verify returns nil iff sig is a signature of messages under pk.
+func Verify(pk *PublicKey, sig *Signature, messages []*big.Int) error {
+	if len(messages) != len(pk.YTilde) {
+		return errCount
+	}
+	if sig.Sigma1 == nil || sig.Sigma2 == nil || isIdentity(sig.Sigma1.Marshal()) {
+		return errMalformed
+	}
+
+	// X̃ + Σ mᵢ·Ỹᵢ
+	K := new(bn256.G2).Set(pk.XTilde)
+	for i, m := range messages {
+		K.Add(K, new(bn256.G2).ScalarMult(pk.YTilde[i], m))
+	}
+	return check(sig, K)
+}
+
+// check returns nil iff e(σ₁, K) = e(σ₂, g̃).
+func check(sig *Signature, K *bn256.G2) error {
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	negSigma2 := new(bn256.G1).Neg(sig.Sigma2)
+	if !bn256.PairingCheck([]*bn256.G1{sig.Sigma1, negSigma2}, []*bn256.G2{K, g2}) {
+		return errInvalid
+	}
+	return nil
+}
+
+// Randomize returns a signature on the same messages as sig that cannot be
+// linked to it, using randomness from r.
+func Randomize(r io.Reader, sig *Signature) (*Signature, error) {
+	t, err := randomNonZero(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{
+		Sigma1: new(bn256.G1).ScalarMult(sig.Sigma1, t),
+		Sigma2: new(bn256.G1).ScalarMult(sig.Sigma2, t),
+	}, nil
+}
+
+// Marshal converts sig into a byte slice.
+func (sig *Signature) Marshal() []byte {
+	return append(sig.Sigma1.Marshal(), sig.Sigma2.Marshal()...)
+}
+
+// Unmarshal sets sig to the result of converting the output of Marshal back
+// into a signature and then returns the remainder of m.
+func (sig *Signature) Unmarshal(m []byte) ([]byte, error) {
+	s1, s2 := new(bn256.G1), new(bn256.G1)
+	m, err := s1.Unmarshal(m)
+	if err != nil {
+		return nil, err
+	}
+	if m, err = s2.Unmarshal(m); err != nil {
+		return nil, err
+	}
+	sig.Sigma1, sig.Sigma2 = s1, s2
+	return m, nil
+}
+
+// randomScalars returns n scalars read from r, each uniform in [0, Order), so
+// that any of them may be zero.
+func randomScalars(r io.Reader, n int) ([]*big.Int, error) {
+	ks := make([]*big.Int, n)
+	for i := range ks {
+		k, err := rand.Int(r, bn256.Order)
+		if err != nil {
+			return nil, err
+		}
+		ks[i] = k
+	}
+	return ks, nil
+}
+
+// randomNonZero returns a random non-zero scalar read from r.
+func randomNonZero(r io.Reader) (*big.Int, error) {
+	for {
+		k, err := rand.Int(r, bn256.Order)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() != 0 {
+			return k, nil
+		}
+	}
+}
+
+// isIdentity returns true iff m is the encoding of the identity.
+func isIdentity(m []byte) bool {
+	for _, b := range m {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// appendScalar appends the 32-byte big-endian encoding of k mod Order to b.
+func appendScalar(b []byte, k *big.Int) []byte {
+	r := new(big.Int).Mod(k, bn256.Order).Bytes()
+	b = append(b, make([]byte, 32-len(r))...)
+	return append(b, r...)
+}
+
+func appendUint64(b []byte, x uint64) []byte {
+	for i := 7; i >= 0; i-- {
+		b = append(b, byte(x>>uint(8*i)))
+	}
+	return b
+}
+
+// readScalar reads a canonical scalar from m and returns it and the rest of m.
+func readScalar(m []byte) (*big.Int, []byte, error) {
+	if len(m) < 32 {
+		return nil, nil, errMalformed
+	}
+	k := new(big.Int).SetBytes(m[:32])
+	if k.Cmp(bn256.Order) >= 0 {
+		return nil, nil, errMalformed
+	}
+	return k, m[32:], nil
+}
+
+func add(a, b *big.Int) *big.Int {
+	c := new(big.Int).Add(a, b)
+	return c.Mod(c, bn256.Order)
+}
+
+func mul(a, b *big.Int) *big.Int {
+	c := new(big.Int).Mul(a, b)
+	return c.Mod(c, bn256.Order)
+}
@@ -0,0 +1,224 @@
+package ps
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+func randomMessages(t *testing.T, n int) []*big.Int {
+	t.Helper()
+
+	ms, err := randomScalars(rand.Reader, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ms
+}
+
+func TestSignVerify(t *testing.T) {
+	for _, n := range []int{1, 2, 5} {
+		sk, pk, err := KeyGen(rand.Reader, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs := randomMessages(t, n)
+
+		sig, err := Sign(rand.Reader, sk, msgs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(pk, sig, msgs); err != nil {
+			t.Fatalf("%d messages: %v", n, err)
+		}
+
+		other := new(Signature)
+		if rest, err := other.Unmarshal(sig.Marshal()); err != nil || len(rest) != 0 {
+			t.Fatal("round trip failed")
+		}
+		if err := Verify(pk, other, msgs); err != nil {
+			t.Fatal("signature rejected after round trip")
+		}
+
+		changed := append([]*big.Int(nil), msgs...)
+		changed[n-1] = add(changed[n-1], big.NewInt(1))
+		if err := Verify(pk, sig, changed); err == nil {
+			t.Fatal("signature accepted for changed messages")
+		}
+		if err := Verify(pk, sig, msgs[:n-1]); err == nil {
+			t.Fatal("signature accepted for fewer messages")
+		}
+		_, otherPK, _ := KeyGen(rand.Reader, n)
+		if err := Verify(otherPK, sig, msgs); err == nil {
+			t.Fatal("signature accepted under another key")
+		}
+
+		zero := new(bn256.G1).ScalarBaseMult(new(big.Int))
+		if err := Verify(pk, &Signature{zero, zero}, msgs); err == nil {
+			t.Fatal("identity signature accepted")
+		}
+	}
+}
+
+func TestRandomize(t *testing.T) {
+	sk, pk, _ := KeyGen(rand.Reader, 3)
+	msgs := randomMessages(t, 3)
+	sig, _ := Sign(rand.Reader, sk, msgs)
+
+	rsig, err := Randomize(rand.Reader, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(pk, rsig, msgs); err != nil {
+		t.Fatal("randomized signature rejected")
+	}
+	if bytes.Equal(sig.Sigma1.Marshal(), rsig.Sigma1.Marshal()) {
+		t.Fatal("randomization did not change the signature")
+	}
+}
+
+func TestBlindSign(t *testing.T) {
+	sk, pk, _ := KeyGen(rand.Reader, 4)
+	msgs := randomMessages(t, 4)
+
+	for _, hidden := range [][]int{{0, 1, 2, 3}, {2, 0}, {3}} {
+		req, bf, err := NewBlindRequest(rand.Reader, pk, msgs, hidden)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The signer only learns the messages that are not hidden.
+		known := append([]*big.Int(nil), msgs...)
+		for _, j := range hidden {
+			known[j] = nil
+		}
+		blinded, err := BlindSign(rand.Reader, sk, pk, req, known)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(pk, blinded, msgs); err == nil {
+			t.Fatal("blinded signature accepted")
+		}
+		if err := Verify(pk, Unblind(blinded, bf), msgs); err != nil {
+			t.Fatalf("hiding %v: %v", hidden, err)
+		}
+	}
+
+	req, _, _ := NewBlindRequest(rand.Reader, pk, msgs, []int{1})
+	req.SM[0] = add(req.SM[0], big.NewInt(1))
+	if _, err := BlindSign(rand.Reader, sk, pk, req, msgs); err == nil {
+		t.Fatal("request with invalid proof signed")
+	}
+	req, _, _ = NewBlindRequest(rand.Reader, pk, msgs, []int{1})
+	req.Hidden = []int{2}
+	if _, err := BlindSign(rand.Reader, sk, pk, req, msgs); err == nil {
+		t.Fatal("request with changed indices signed")
+	}
+	if _, _, err := NewBlindRequest(rand.Reader, pk, msgs, []int{4}); err == nil {
+		t.Fatal("index out of range accepted")
+	}
+
+	for name, tamper := range map[string]func(*BlindRequest){
+		"nil response":       func(req *BlindRequest) { req.SM[0] = nil },
+		"negative response":  func(req *BlindRequest) { req.SM[0] = big.NewInt(-1) },
+		"unreduced response": func(req *BlindRequest) { req.SM[0] = new(big.Int).Add(req.SM[0], bn256.Order) },
+		"zero-value C":       func(req *BlindRequest) { req.C = new(bn256.G1) },
+	} {
+		req, _, _ := NewBlindRequest(rand.Reader, pk, msgs, []int{1, 2})
+		tamper(req)
+		if err := req.Verify(pk); err != errMalformed {
+			t.Errorf("%s: got %v, want %v", name, err, errMalformed)
+		}
+	}
+}
+
+func TestProof(t *testing.T) {
+	sk, pk, _ := KeyGen(rand.Reader, 4)
+	msgs := randomMessages(t, 4)
+	sig, _ := Sign(rand.Reader, sk, msgs)
+	nonce := []byte("nonce 1234")
+
+	for _, disclosed := range [][]int{nil, {1}, {3, 0}, {0, 1, 2, 3}} {
+		proof, err := ProvePossession(rand.Reader, pk, sig, msgs, disclosed, nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dm := make([]*big.Int, len(disclosed))
+		for i, j := range disclosed {
+			dm[i] = msgs[j]
+		}
+
+		decoded := new(Proof)
+		if err := decoded.Unmarshal(proof.Marshal()); err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyPossession(pk, decoded, disclosed, dm, nonce); err != nil {
+			t.Fatalf("disclosing %v: %v", disclosed, err)
+		}
+
+		if err := VerifyPossession(pk, proof, disclosed, dm, []byte("nonce 5678")); err == nil {
+			t.Fatal("proof accepted with another nonce")
+		}
+		if len(disclosed) > 0 {
+			lie := append([]*big.Int(nil), dm...)
+			lie[0] = add(lie[0], big.NewInt(1))
+			if err := VerifyPossession(pk, proof, disclosed, lie, nonce); err == nil {
+				t.Fatal("proof accepted for a forged disclosed message")
+			}
+		}
+	}
+
+	a, _ := ProvePossession(rand.Reader, pk, sig, msgs, []int{0}, nonce)
+	b, _ := ProvePossession(rand.Reader, pk, sig, msgs, []int{0}, nonce)
+	if bytes.Equal(a.Sigma.Marshal(), b.Sigma.Marshal()) || bytes.Equal(a.K.Marshal(), b.K.Marshal()) {
+		t.Fatal("proofs share values")
+	}
+
+	if _, err := ProvePossession(rand.Reader, pk, sig, msgs[:3], nil, nonce); err == nil {
+		t.Fatal("proof generated for the wrong messages")
+	}
+}
+
+func TestProofTampered(t *testing.T) {
+	sk, pk, _ := KeyGen(rand.Reader, 3)
+	msgs := randomMessages(t, 3)
+	sig, _ := Sign(rand.Reader, sk, msgs)
+	proof, _ := ProvePossession(rand.Reader, pk, sig, msgs, []int{1}, nil)
+	dm := msgs[1:2]
+	g := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	one := big.NewInt(1)
+
+	for i, f := range []func(p *Proof){
+		func(p *Proof) { p.Sigma.Sigma1 = new(bn256.G1).Add(p.Sigma.Sigma1, g) },
+		func(p *Proof) { p.Sigma.Sigma2 = new(bn256.G1).Add(p.Sigma.Sigma2, g) },
+		func(p *Proof) { p.K = new(bn256.G2).Add(p.K, g2) },
+		func(p *Proof) { p.C = add(p.C, one) },
+		func(p *Proof) { p.ST = add(p.ST, one) },
+		func(p *Proof) { p.SM[0] = add(p.SM[0], one) },
+		func(p *Proof) { p.SM = p.SM[1:] },
+	} {
+		p := new(Proof)
+		if err := p.Unmarshal(proof.Marshal()); err != nil {
+			t.Fatal(err)
+		}
+		f(p)
+		if err := VerifyPossession(pk, p, []int{1}, dm, nil); err == nil {
+			t.Errorf("tampered proof %d accepted", i)
+		}
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	sk, pk, _ := KeyGen(rand.Reader, 5)
+	msgs, _ := randomScalars(rand.Reader, 5)
+	sig, _ := Sign(rand.Reader, sk, msgs)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Verify(pk, sig, msgs)
+	}
+}
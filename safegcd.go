@@ -0,0 +1,215 @@
+package bn256
+
+// This file implements inversion in GF(p) with the "safegcd" algorithm of
+// Bernstein and Yang, "Fast constant-time gcd computation and modular
+// inversion" (https://eprint.iacr.org/2019/266), in the form used by
+// libsecp256k1: the state is held in signed 62-bit limbs, divsteps are applied
+// in batches of 59 to the low bits only, and each batch is then applied to the
+// full numbers as a 2×2 matrix. Ten batches suffice for any 256-bit modulus,
+// and every batch runs the same instructions whatever its input, so the
+// running time does not depend on the element being inverted.
+
+import (
+	"math/bits"
+)
+
+// signed62 is a number Σ v[i]·2^(62i) whose limbs are signed; v[0..3] are
+// normally in [0, 2^62) and v[4] carries the sign.
+type signed62 [5]int64
+
+const mask62 = 1<<62 - 1
+
+// p62 is p in signed62 form.
+var p62 = signed62{0x3c208c16d87cfd47, 0x1e05aa45a1c72a34, 0x05045b68181585d9, 0x19139cb84c680a6e, 0x30}
+
+// pInv62 is p^-1 mod 2^62.
+const pInv62 = 0x382df87d1b799c77
+
+// trans2x2 is the transition matrix of a batch of divsteps, scaled by 2^62.
+type trans2x2 struct {
+	u, v, q, r int64
+}
+
+// int128 is a signed 128-bit accumulator in two's complement.
+type int128 struct {
+	hi, lo uint64
+}
+
+// mulAdd adds a·b to c.
+func (c *int128) mulAdd(a, b int64) {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	// Correct the unsigned product for the signs of a and b.
+	hi -= uint64(a>>63) & uint64(b)
+	hi -= uint64(b>>63) & uint64(a)
+
+	var carry uint64
+	c.lo, carry = bits.Add64(c.lo, lo, 0)
+	c.hi, _ = bits.Add64(c.hi, hi, carry)
+}
+
+// shift62 divides c by 2^62, rounding towards minus infinity.
+func (c *int128) shift62() {
+	c.lo = c.lo>>62 | c.hi<<2
+	c.hi = uint64(int64(c.hi) >> 62)
+}
+
+// divsteps59 applies 59 divsteps to the low bits f0 and g0 of f and g, starting
+// from zeta = -(δ+1/2), and returns the new zeta together with the transition
+// matrix, which is scaled by 2^62.
+func divsteps59(zeta int64, f0, g0 uint64) (int64, trans2x2) {
+	u, v, q, r := uint64(8), uint64(0), uint64(0), uint64(8)
+	f, g := f0, g0
+
+	for i := 3; i < 62; i++ {
+		// If zeta < 0 and g is odd, swap (f, u, v) with (g, q, r) and negate
+		// the new g, q and r; then, if g is odd, add f, u and v to g, q and r.
+		c1 := uint64(zeta >> 63)
+		c2 := -(g & 1)
+		x := (f ^ c1) - c1
+		y := (u ^ c1) - c1
+		z := (v ^ c1) - c1
+		g += x & c2
+		q += y & c2
+		r += z & c2
+		c1 &= c2
+		zeta = (zeta ^ int64(c1)) - 1
+		f += g & c1
+		u += q & c1
+		v += r & c1
+		g >>= 1
+		u <<= 1
+		v <<= 1
+	}
+
+	return zeta, trans2x2{int64(u), int64(v), int64(q), int64(r)}
+}
+
+// updateDE sets (d, e) to t·(d, e)/2^62 mod p, where d and e are in (-2p, p)
+// and remain so.
+func updateDE(d, e *signed62, t *trans2x2) {
+	u, v, q, r := t.u, t.v, t.q, t.r
+
+	// Choose md and me such that t·(d, e) + p·(md, me) is a multiple of 2^62
+	// and the result stays in range.
+	sd, se := d[4]>>63, e[4]>>63
+	md := (u & sd) + (v & se)
+	me := (q & sd) + (r & se)
+
+	var cd, ce int128
+	cd.mulAdd(u, d[0])
+	cd.mulAdd(v, e[0])
+	ce.mulAdd(q, d[0])
+	ce.mulAdd(r, e[0])
+
+	md -= int64((pInv62*cd.lo + uint64(md)) & mask62)
+	me -= int64((pInv62*ce.lo + uint64(me)) & mask62)
+
+	cd.mulAdd(p62[0], md)
+	ce.mulAdd(p62[0], me)
+	cd.shift62()
+	ce.shift62()
+
+	for i := 1; i < 5; i++ {
+		cd.mulAdd(u, d[i])
+		cd.mulAdd(v, e[i])
+		cd.mulAdd(p62[i], md)
+		ce.mulAdd(q, d[i])
+		ce.mulAdd(r, e[i])
+		ce.mulAdd(p62[i], me)
+		d[i-1] = int64(cd.lo & mask62)
+		e[i-1] = int64(ce.lo & mask62)
+		cd.shift62()
+		ce.shift62()
+	}
+	d[4] = int64(cd.lo)
+	e[4] = int64(ce.lo)
+}
+
+// updateFG sets (f, g) to t·(f, g)/2^62, which is exact.
+func updateFG(f, g *signed62, t *trans2x2) {
+	u, v, q, r := t.u, t.v, t.q, t.r
+
+	var cf, cg int128
+	cf.mulAdd(u, f[0])
+	cf.mulAdd(v, g[0])
+	cg.mulAdd(q, f[0])
+	cg.mulAdd(r, g[0])
+	cf.shift62()
+	cg.shift62()
+
+	for i := 1; i < 5; i++ {
+		cf.mulAdd(u, f[i])
+		cf.mulAdd(v, g[i])
+		cg.mulAdd(q, f[i])
+		cg.mulAdd(r, g[i])
+		f[i-1] = int64(cf.lo & mask62)
+		g[i-1] = int64(cg.lo & mask62)
+		cf.shift62()
+		cg.shift62()
+	}
+	f[4] = int64(cf.lo)
+	g[4] = int64(cg.lo)
+}
+
+// normalize brings d from (-2p, p) into [0, p), negating it first if sign is
+// negative.
+func (d *signed62) normalize(sign int64) {
+	// Add p if d is negative, then negate if requested, which leaves d in
+	// (-p, p).
+	c := d[4] >> 63
+	for i := range d {
+		d[i] += p62[i] & c
+	}
+	c = sign >> 63
+	for i := range d {
+		d[i] = (d[i] ^ c) - c
+	}
+	d.carry()
+
+	// Add p again if d is still negative.
+	c = d[4] >> 63
+	for i := range d {
+		d[i] += p62[i] & c
+	}
+	d.carry()
+}
+
+// carry propagates the high bits of each limb into the next.
+func (d *signed62) carry() {
+	for i := 0; i < 4; i++ {
+		d[i+1] += d[i] >> 62
+		d[i] &= mask62
+	}
+}
+
+// invertSafegcd sets e to 1/f in Montgomery form, or to zero if f is zero.
+func (e *gfP) invertSafegcd(f *gfP) {
+	// The Montgomery form of f is fR, so its inverse is f^-1·R^-1, which is
+	// brought back to f^-1·R by a Montgomery multiplication with R³.
+	g := signed62{
+		int64(f[0] & mask62),
+		int64((f[0]>>62 | f[1]<<2) & mask62),
+		int64((f[1]>>60 | f[2]<<4) & mask62),
+		int64((f[2]>>58 | f[3]<<6) & mask62),
+		int64(f[3] >> 56),
+	}
+	fs := p62
+	d, x := signed62{}, signed62{1}
+
+	zeta := int64(-1)
+	for i := 0; i < 10; i++ {
+		var t trans2x2
+		zeta, t = divsteps59(zeta, uint64(fs[0]), uint64(g[0]))
+		updateDE(&d, &x, &t)
+		updateFG(&fs, &g, &t)
+	}
+
+	// Now g is zero and fs is ±1, so d is ±f^-1.
+	d.normalize(fs[4])
+
+	e[0] = uint64(d[0]) | uint64(d[1])<<62
+	e[1] = uint64(d[1])>>2 | uint64(d[2])<<60
+	e[2] = uint64(d[2])>>4 | uint64(d[3])<<58
+	e[3] = uint64(d[3])>>6 | uint64(d[4])<<56
+	gfpMul(e, e, r3)
+}
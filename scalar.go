@@ -0,0 +1,140 @@
+package bn256
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+// Scalar is an integer modulo Order, by which elements of G1, G2 and GT are
+// multiplied. It implements the same encoding interfaces as the groups: the
+// binary form is 32 bytes big-endian, the text form is those bytes in hex,
+// prefixed with "0x", and JSON is a hex string. DecimalScalar encodes in JSON
+// as a decimal string instead, as snarkjs writes public signals. The zero
+// value is zero.
+//
+// A Scalar may be copied: its value is never modified in place, so a copy is
+// unaffected by later changes to the original.
+type Scalar struct {
+	k *big.Int
+}
+
+// NewScalar returns k mod Order as a Scalar.
+func NewScalar(k *big.Int) *Scalar {
+	return new(Scalar).SetBig(k)
+}
+
+// SetBig sets e to k mod Order and then returns e.
+func (e *Scalar) SetBig(k *big.Int) *Scalar {
+	e.k = new(big.Int).Mod(k, Order)
+	return e
+}
+
+// value returns the value of e, which is nil for the zero value.
+func (e *Scalar) value() *big.Int {
+	if e.k == nil {
+		return new(big.Int)
+	}
+	return e.k
+}
+
+// Big returns e as an integer in [0, Order).
+func (e *Scalar) Big() *big.Int {
+	return new(big.Int).Set(e.value())
+}
+
+// String returns e in decimal.
+func (e *Scalar) String() string {
+	return e.value().String()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e *Scalar) MarshalBinary() ([]byte, error) {
+	// Each value is a 256-bit number.
+	const numBytes = 256 / 8
+
+	b := e.value().Bytes()
+	ret := make([]byte, numBytes)
+	copy(ret[numBytes-len(b):], b)
+	return ret, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The data must be
+// exactly 32 bytes and encode a number less than Order.
+func (e *Scalar) UnmarshalBinary(data []byte) error {
+	if len(data) != 256/8 {
+		return errors.New("bn256: scalar is not 32 bytes")
+	}
+	return e.setCanonical(new(big.Int).SetBytes(data))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (e *Scalar) MarshalText() ([]byte, error) {
+	b, _ := e.MarshalBinary()
+	return marshalHex(b), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Besides the output of
+// MarshalText, it accepts a number less than Order in decimal.
+func (e *Scalar) UnmarshalText(text []byte) error {
+	if len(text) >= 2 && text[0] == '0' && text[1] == 'x' {
+		return unmarshalHex(e.UnmarshalBinary, text)
+	}
+	k, ok := new(big.Int).SetString(string(text), 10)
+	if !ok {
+		return errors.New("bn256: scalar is neither hex nor decimal")
+	}
+	return e.setCanonical(k)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *Scalar) MarshalJSON() ([]byte, error) {
+	b, _ := e.MarshalBinary()
+	return marshalJSONHex(b)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a string in either of
+// the forms accepted by UnmarshalText.
+func (e *Scalar) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return e.UnmarshalText([]byte(s))
+}
+
+// setCanonical sets e to k, which must be in [0, Order).
+func (e *Scalar) setCanonical(k *big.Int) error {
+	if k.Sign() < 0 || k.Cmp(Order) >= 0 {
+		return errors.New("bn256: scalar is not less than Order")
+	}
+	e.k = new(big.Int).Set(k)
+	return nil
+}
+
+// DecimalScalar is a Scalar that encodes in JSON as a decimal string. Convert
+// between the two with (*DecimalScalar)(s) and (*Scalar)(d).
+type DecimalScalar Scalar
+
+// MarshalJSON implements json.Marshaler.
+func (e *DecimalScalar) MarshalJSON() ([]byte, error) {
+	return json.Marshal((*Scalar)(e).String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *DecimalScalar) UnmarshalJSON(data []byte) error {
+	return (*Scalar)(e).UnmarshalJSON(data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (e *DecimalScalar) MarshalBinary() ([]byte, error) {
+	return (*Scalar)(e).MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (e *DecimalScalar) UnmarshalBinary(data []byte) error {
+	return (*Scalar)(e).UnmarshalBinary(data)
+}
@@ -0,0 +1,71 @@
+package bn256
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestScalar(t *testing.T) {
+	k := NewScalar(big.NewInt(-1))
+	if k.Big().Cmp(new(big.Int).Sub(Order, big.NewInt(1))) != 0 {
+		t.Fatal("NewScalar does not reduce")
+	}
+
+	b, _ := k.MarshalBinary()
+	if len(b) != 32 {
+		t.Fatalf("%d-byte binary form", len(b))
+	}
+	if err := new(Scalar).UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	hex, _ := json.Marshal(k)
+	dec, _ := json.Marshal((*DecimalScalar)(k))
+	if string(dec) != `"`+k.String()+`"` {
+		t.Errorf("decimal JSON form is %s", dec)
+	}
+	for _, data := range [][]byte{hex, dec} {
+		got := new(Scalar)
+		if err := json.Unmarshal(data, got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Big().Cmp(k.Big()) != 0 {
+			t.Errorf("%s decoded to %v", data, got)
+		}
+	}
+
+	ob := Order.Bytes()
+	for _, bad := range []string{
+		`"` + Order.String() + `"`,
+		`"-1"`,
+		`"0x` + new(big.Int).SetBytes(ob).Text(16) + `"`,
+		`"0x01"`,
+		`"12a"`,
+		`12`,
+	} {
+		if err := json.Unmarshal([]byte(bad), new(Scalar)); err == nil {
+			t.Errorf("%s accepted", bad)
+		}
+	}
+}
+
+func TestScalarCopy(t *testing.T) {
+	var zero Scalar
+	if zero.Big().Sign() != 0 || zero.String() != "0" {
+		t.Fatal("zero value is not zero")
+	}
+
+	k := NewScalar(big.NewInt(5))
+	c := *k
+	k.SetBig(big.NewInt(7))
+	if c.Big().Int64() != 5 {
+		t.Errorf("copy changed to %v", &c)
+	}
+	if err := k.UnmarshalText([]byte("9")); err != nil {
+		t.Fatal(err)
+	}
+	if c.Big().Int64() != 5 {
+		t.Errorf("copy changed to %v", &c)
+	}
+}
@@ -0,0 +1,96 @@
+package sigma
+
+import (
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// Element is an element of one of the groups G₁, G₂ and GT, written
+// additively. Its methods do not modify their receiver. Elements of different
+// groups must not be combined.
+type Element interface {
+	// Add returns the sum of the receiver and b.
+	Add(b Element) Element
+	// ScalarMult returns k times the receiver.
+	ScalarMult(k *big.Int) Element
+	// Marshal returns the encoding of the element used by bn256, preceded
+	// by a byte identifying its group.
+	Marshal() []byte
+}
+
+// Group tags, which keep the encodings of elements of different groups apart.
+const (
+	tagG1 = 1
+	tagG2 = 2
+	tagGT = 3
+)
+
+// G1 returns p as an Element.
+func G1(p *bn256.G1) Element {
+	return g1Element{new(bn256.G1).Set(p)}
+}
+
+// G2 returns p as an Element.
+func G2(p *bn256.G2) Element {
+	return g2Element{new(bn256.G2).Set(p)}
+}
+
+// GT returns p as an Element.
+func GT(p *bn256.GT) Element {
+	return gtElement{new(bn256.GT).Set(p)}
+}
+
+type g1Element struct{ p *bn256.G1 }
+
+func (a g1Element) Add(b Element) Element {
+	return g1Element{new(bn256.G1).Add(a.p, b.(g1Element).p)}
+}
+
+func (a g1Element) ScalarMult(k *big.Int) Element {
+	return g1Element{new(bn256.G1).ScalarMult(a.p, k)}
+}
+
+func (a g1Element) Marshal() []byte {
+	return append([]byte{tagG1}, a.p.Marshal()...)
+}
+
+type g2Element struct{ p *bn256.G2 }
+
+func (a g2Element) Add(b Element) Element {
+	return g2Element{new(bn256.G2).Add(a.p, b.(g2Element).p)}
+}
+
+func (a g2Element) ScalarMult(k *big.Int) Element {
+	return g2Element{new(bn256.G2).ScalarMult(a.p, k)}
+}
+
+func (a g2Element) Marshal() []byte {
+	return append([]byte{tagG2}, a.p.Marshal()...)
+}
+
+type gtElement struct{ p *bn256.GT }
+
+func (a gtElement) Add(b Element) Element {
+	return gtElement{new(bn256.GT).Add(a.p, b.(gtElement).p)}
+}
+
+func (a gtElement) ScalarMult(k *big.Int) Element {
+	return gtElement{new(bn256.GT).ScalarMult(a.p, k)}
+}
+
+func (a gtElement) Marshal() []byte {
+	return append([]byte{tagGT}, a.p.Marshal()...)
+}
+
+// equal returns true iff a and b are the same element of the same group.
+func equal(a, b Element) bool {
+	return string(a.Marshal()) == string(b.Marshal())
+}
+
+// mulSub returns s·B - c·Y, which the verifier compares with a commitment.
+func mulSub(B, Y Element, s, c *big.Int) Element {
+	negC := new(big.Int).Neg(c)
+	negC.Mod(negC, bn256.Order)
+	return B.ScalarMult(s).Add(Y.ScalarMult(negC))
+}
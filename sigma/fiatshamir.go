@@ -0,0 +1,132 @@
+package sigma
+
+import (
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// FiatShamir makes the protocols non-interactive by deriving the challenge
+// from a hash of the statement and the commitments.
+type FiatShamir struct {
+	// Hash returns the hash function challenges are derived with, for
+	// example sha256.New or sha3.NewLegacyKeccak256.
+	Hash func() hash.Hash
+	// Domain separates the proofs of one application from those of
+	// another, so that a proof made for one cannot be replayed in the other.
+	Domain []byte
+}
+
+// Proof is a non-interactive proof of a Statement.
+type Proof struct {
+	C, S *big.Int
+}
+
+// OrProof is a non-interactive proof of an Or.
+type OrProof struct {
+	C, S []*big.Int
+}
+
+// Kinds of proof, which keep the challenges of different protocols apart.
+const (
+	kindStatement = 1
+	kindOr        = 2
+)
+
+// Prove returns a proof of st with the witness x, using randomness from r.
+func (fs *FiatShamir) Prove(st *Statement, x *big.Int, r io.Reader) (*Proof, error) {
+	p := NewProver(st, x)
+	commitment, err := p.Commit(r)
+	if err != nil {
+		return nil, err
+	}
+	c := fs.challenge(kindStatement, []*Statement{st}, [][]Element{commitment})
+	return &Proof{c, p.Respond(c)}, nil
+}
+
+// Verify returns true iff proof is a valid proof of st.
+func (fs *FiatShamir) Verify(st *Statement, proof *Proof) bool {
+	if !st.valid() || !canonical(proof.C) || !canonical(proof.S) {
+		return false
+	}
+	commitment := simulate(st, proof.C, proof.S)
+	c := fs.challenge(kindStatement, []*Statement{st}, [][]Element{commitment})
+	return c.Cmp(proof.C) == 0
+}
+
+// ProveOr returns a proof of o with the witness x of o.Statements[known], using
+// randomness from r.
+func (fs *FiatShamir) ProveOr(o *Or, known int, x *big.Int, r io.Reader) (*OrProof, error) {
+	p := NewOrProver(o, known, x)
+	commitments, err := p.Commit(r)
+	if err != nil {
+		return nil, err
+	}
+	cs, ss := p.Respond(fs.challenge(kindOr, o.Statements, commitments))
+	return &OrProof{cs, ss}, nil
+}
+
+// VerifyOr returns true iff proof is a valid proof of o.
+func (fs *FiatShamir) VerifyOr(o *Or, proof *OrProof) bool {
+	n := len(o.Statements)
+	if !o.valid() || len(proof.C) != n || len(proof.S) != n {
+		return false
+	}
+
+	commitments := make([][]Element, n)
+	sum := new(big.Int)
+	for i, st := range o.Statements {
+		if !canonical(proof.C[i]) || !canonical(proof.S[i]) {
+			return false
+		}
+		commitments[i] = simulate(st, proof.C[i], proof.S[i])
+		sum.Add(sum, proof.C[i])
+	}
+	sum.Mod(sum, bn256.Order)
+	return fs.challenge(kindOr, o.Statements, commitments).Cmp(sum) == 0
+}
+
+// challenge returns the challenge for the given statements and commitments.
+// At least 64 bytes of hash output are reduced modulo Order so that the result
+// is close to uniform.
+func (fs *FiatShamir) challenge(kind byte, statements []*Statement, commitments [][]Element) *big.Int {
+	var msg []byte
+	msg = appendBytes(msg, fs.Domain)
+	msg = append(msg, kind)
+	msg = appendLength(msg, len(statements))
+	for i, st := range statements {
+		msg = appendLength(msg, len(st.Bases))
+		for j := range st.Bases {
+			msg = append(msg, st.Bases[j].Marshal()...)
+			msg = append(msg, st.Images[j].Marshal()...)
+			msg = append(msg, commitments[i][j].Marshal()...)
+		}
+	}
+
+	var digest []byte
+	for ctr := byte(0); len(digest) < 64; ctr++ {
+		h := fs.Hash()
+		h.Write([]byte{ctr})
+		h.Write(msg)
+		digest = h.Sum(digest)
+	}
+	c := new(big.Int).SetBytes(digest)
+	return c.Mod(c, bn256.Order)
+}
+
+// appendBytes appends b to msg, preceded by its length.
+func appendBytes(msg, b []byte) []byte {
+	return append(appendLength(msg, len(b)), b...)
+}
+
+// appendLength appends n to msg as a 4-byte big-endian number.
+func appendLength(msg []byte, n int) []byte {
+	return append(msg, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+}
+
+// canonical returns true iff k is a reduced scalar.
+func canonical(k *big.Int) bool {
+	return k != nil && k.Sign() >= 0 && k.Cmp(bn256.Order) < 0
+}
@@ -0,0 +1,134 @@
+package sigma
+
+import (
+	"crypto/rand"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// Or is the claim that the prover knows the witness of at least one of
+// Statements.
+type Or struct {
+	Statements []*Statement
+}
+
+func (o *Or) valid() bool {
+	if len(o.Statements) == 0 {
+		return false
+	}
+	for _, st := range o.Statements {
+		if !st.valid() {
+			return false
+		}
+	}
+	return true
+}
+
+// Check returns true iff the challenges cs and responses ss answer the
+// challenge c for the commitments in an interactive run of the protocol for o.
+// The challenges must add up to c.
+func (o *Or) Check(commitments [][]Element, c *big.Int, cs, ss []*big.Int) bool {
+	n := len(o.Statements)
+	if !o.valid() || len(commitments) != n || len(cs) != n || len(ss) != n {
+		return false
+	}
+
+	sum := new(big.Int)
+	for i, st := range o.Statements {
+		if !st.Check(commitments[i], cs[i], ss[i]) {
+			return false
+		}
+		sum.Add(sum, cs[i])
+	}
+	sum.Sub(sum, c)
+	return sum.Mod(sum, bn256.Order).Sign() == 0
+}
+
+// OrProver is the prover of an interactive run of the protocol for an Or.
+type OrProver struct {
+	o     *Or
+	known int
+	x, k  *big.Int
+
+	// cs and ss are the simulated challenges and responses of the
+	// statements whose witness is unknown.
+	cs, ss []*big.Int
+}
+
+// NewOrProver returns a prover of o that knows the witness x of
+// o.Statements[known].
+func NewOrProver(o *Or, known int, x *big.Int) *OrProver {
+	return &OrProver{o: o, known: known, x: x}
+}
+
+// Commit returns the first message of the prover, using randomness from r.
+// The commitments for the statements whose witness is unknown are simulated
+// from challenges and responses chosen in advance.
+func (p *OrProver) Commit(r io.Reader) ([][]Element, error) {
+	if !p.o.valid() || p.known < 0 || p.known >= len(p.o.Statements) {
+		return nil, errStatement
+	}
+
+	n := len(p.o.Statements)
+	p.cs, p.ss = make([]*big.Int, n), make([]*big.Int, n)
+	commitments := make([][]Element, n)
+	for i, st := range p.o.Statements {
+		if i == p.known {
+			k, err := rand.Int(r, bn256.Order)
+			if err != nil {
+				return nil, err
+			}
+			p.k = k
+			commitments[i] = commit(st, k)
+			continue
+		}
+
+		c, err := rand.Int(r, bn256.Order)
+		if err != nil {
+			return nil, err
+		}
+		s, err := rand.Int(r, bn256.Order)
+		if err != nil {
+			return nil, err
+		}
+		p.cs[i], p.ss[i] = c, s
+		commitments[i] = simulate(st, c, s)
+	}
+	return commitments, nil
+}
+
+// Respond returns the challenges and responses of the prover to the challenge
+// c. It must be called once, after Commit.
+func (p *OrProver) Respond(c *big.Int) (cs, ss []*big.Int) {
+	if p.k == nil {
+		panic("sigma: Respond called before Commit")
+	}
+
+	// The challenge of the known statement is whatever makes the sum c.
+	ck := new(big.Int).Set(c)
+	for i, ci := range p.cs {
+		if i != p.known {
+			ck.Sub(ck, ci)
+		}
+	}
+	ck.Mod(ck, bn256.Order)
+
+	cs = append([]*big.Int(nil), p.cs...)
+	ss = append([]*big.Int(nil), p.ss...)
+	cs[p.known] = ck
+	ss[p.known] = response(p.k, ck, p.x)
+	p.k = nil
+	return cs, ss
+}
+
+// simulate returns the commitment for st that the response s answers for the
+// challenge c.
+func simulate(st *Statement, c, s *big.Int) []Element {
+	ret := make([]Element, len(st.Bases))
+	for i := range st.Bases {
+		ret[i] = mulSub(st.Bases[i], st.Images[i], s, c)
+	}
+	return ret
+}
@@ -0,0 +1,118 @@
+// Package sigma implements sigma protocols: zero-knowledge proofs of
+// knowledge of discrete logarithms over G₁, G₂ and GT.
+//
+// A Statement claims knowledge of a single witness x such that Yᵢ = x·Bᵢ for
+// each of its equations. With one equation it is a Schnorr proof of knowledge
+// of a discrete logarithm; with two it is a Chaum-Pedersen proof of equality
+// of discrete logarithms. The equations may be in different groups, for
+// example to show that the same exponent is used in G₁ and GT. An Or claims
+// knowledge of the witness of at least one of several statements without
+// revealing which, using the technique of Cramer, Damgård and Schoenmakers.
+//
+// Each protocol is available in interactive form, as a Prover that commits
+// and then responds to the verifier's challenge, and in non-interactive form
+// through the Fiat-Shamir transform, with a hash function chosen by the
+// caller.
+//
+// GT is written additively here, as it is in bn256, so x·B for B ∈ GT is the
+// exponentiation Bˣ.
+package sigma
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+var errStatement = errors.New("sigma: malformed statement")
+
+// Statement is the claim that the prover knows x with Images[i] = x·Bases[i]
+// for every i.
+type Statement struct {
+	Bases, Images []Element
+}
+
+// Schnorr returns the statement that the prover knows the discrete logarithm
+// of Y to the base B.
+func Schnorr(B, Y Element) *Statement {
+	return &Statement{[]Element{B}, []Element{Y}}
+}
+
+// DLEQ returns the statement that the prover knows x with Y1 = x·B1 and
+// Y2 = x·B2. The two equations may be in different groups.
+func DLEQ(B1, Y1, B2, Y2 Element) *Statement {
+	return &Statement{[]Element{B1, B2}, []Element{Y1, Y2}}
+}
+
+func (st *Statement) valid() bool {
+	return len(st.Bases) > 0 && len(st.Bases) == len(st.Images)
+}
+
+// Check returns true iff the response s answers the challenge c for the
+// commitment in an interactive run of the protocol for st.
+func (st *Statement) Check(commitment []Element, c, s *big.Int) bool {
+	if !st.valid() || len(commitment) != len(st.Bases) {
+		return false
+	}
+	for i := range st.Bases {
+		if !equal(mulSub(st.Bases[i], st.Images[i], s, c), commitment[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Prover is the prover of an interactive run of the protocol for a Statement.
+type Prover struct {
+	st   *Statement
+	x, k *big.Int
+}
+
+// NewProver returns a prover of st with the witness x.
+func NewProver(st *Statement, x *big.Int) *Prover {
+	return &Prover{st: st, x: x}
+}
+
+// Commit returns the first message of the prover, using randomness from r.
+func (p *Prover) Commit(r io.Reader) ([]Element, error) {
+	if !p.st.valid() {
+		return nil, errStatement
+	}
+	k, err := rand.Int(r, bn256.Order)
+	if err != nil {
+		return nil, err
+	}
+	p.k = k
+	return commit(p.st, k), nil
+}
+
+// Respond returns the response of the prover to the challenge c. It must be
+// called once, after Commit.
+func (p *Prover) Respond(c *big.Int) *big.Int {
+	if p.k == nil {
+		panic("sigma: Respond called before Commit")
+	}
+	s := response(p.k, c, p.x)
+	// A second response with the same commitment would reveal the witness.
+	p.k = nil
+	return s
+}
+
+// commit returns k·Bᵢ for each base of st.
+func commit(st *Statement, k *big.Int) []Element {
+	ret := make([]Element, len(st.Bases))
+	for i, B := range st.Bases {
+		ret[i] = B.ScalarMult(k)
+	}
+	return ret
+}
+
+// response returns k + c·x.
+func response(k, c, x *big.Int) *big.Int {
+	s := new(big.Int).Mul(c, x)
+	s.Add(s, k)
+	return s.Mod(s, bn256.Order)
+}
@@ -0,0 +1,216 @@
+package sigma
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+	"golang.org/x/crypto/sha3"
+)
+
+var fs = &FiatShamir{Hash: sha256.New, Domain: []byte("test")}
+
+func randomScalar(t *testing.T) *big.Int {
+	t.Helper()
+
+	k, err := rand.Int(rand.Reader, bn256.Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+// bases returns a random element of each of G₁, G₂ and GT.
+func bases(t *testing.T) []Element {
+	t.Helper()
+
+	_, g1, _ := bn256.RandomG1(rand.Reader)
+	_, g2, _ := bn256.RandomG2(rand.Reader)
+	return []Element{G1(g1), G2(g2), GT(bn256.Pair(g1, g2))}
+}
+
+func TestSchnorr(t *testing.T) {
+	for _, B := range bases(t) {
+		x := randomScalar(t)
+		st := Schnorr(B, B.ScalarMult(x))
+
+		proof, err := fs.Prove(st, x, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fs.Verify(st, proof) {
+			t.Fatal("valid proof rejected")
+		}
+
+		other := Schnorr(B, B.ScalarMult(randomScalar(t)))
+		if fs.Verify(other, proof) {
+			t.Fatal("proof accepted for another statement")
+		}
+		wrong, _ := fs.Prove(other, x, rand.Reader)
+		if fs.Verify(other, wrong) {
+			t.Fatal("proof with the wrong witness accepted")
+		}
+	}
+}
+
+func TestInteractive(t *testing.T) {
+	for _, B := range bases(t) {
+		x := randomScalar(t)
+		st := Schnorr(B, B.ScalarMult(x))
+
+		p := NewProver(st, x)
+		commitment, err := p.Commit(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := randomScalar(t)
+		s := p.Respond(c)
+		if !st.Check(commitment, c, s) {
+			t.Fatal("valid run rejected")
+		}
+		if st.Check(commitment, new(big.Int).Add(c, big.NewInt(1)), s) {
+			t.Fatal("response accepted for another challenge")
+		}
+	}
+}
+
+// TestDLEQAcrossGroups proves that the same exponent is used in G₁ and GT.
+func TestDLEQAcrossGroups(t *testing.T) {
+	_, g1, _ := bn256.RandomG1(rand.Reader)
+	_, g2, _ := bn256.RandomG2(rand.Reader)
+	B1, B2 := G1(g1), GT(bn256.Pair(g1, g2))
+
+	x := randomScalar(t)
+	st := DLEQ(B1, B1.ScalarMult(x), B2, B2.ScalarMult(x))
+	proof, err := fs.Prove(st, x, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fs.Verify(st, proof) {
+		t.Fatal("valid proof rejected")
+	}
+
+	y := randomScalar(t)
+	unequal := DLEQ(B1, B1.ScalarMult(x), B2, B2.ScalarMult(y))
+	for _, w := range []*big.Int{x, y} {
+		proof, _ := fs.Prove(unequal, w, rand.Reader)
+		if fs.Verify(unequal, proof) {
+			t.Fatal("proof of unequal logarithms accepted")
+		}
+	}
+}
+
+func TestOr(t *testing.T) {
+	bs := bases(t)
+	statements := make([]*Statement, 3)
+	witnesses := make([]*big.Int, 3)
+	for i := range statements {
+		witnesses[i] = randomScalar(t)
+		statements[i] = Schnorr(bs[i], bs[i].ScalarMult(witnesses[i]))
+	}
+	o := &Or{statements}
+
+	for known := range statements {
+		proof, err := fs.ProveOr(o, known, witnesses[known], rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fs.VerifyOr(o, proof) {
+			t.Fatalf("valid proof with witness %d rejected", known)
+		}
+
+		// Interactive run.
+		p := NewOrProver(o, known, witnesses[known])
+		commitments, err := p.Commit(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := randomScalar(t)
+		cs, ss := p.Respond(c)
+		if !o.Check(commitments, c, cs, ss) {
+			t.Fatalf("valid run with witness %d rejected", known)
+		}
+		if o.Check(commitments, new(big.Int).Add(c, big.NewInt(1)), cs, ss) {
+			t.Fatal("responses accepted for another challenge")
+		}
+	}
+
+	// Without any witness, no proof verifies.
+	for known := range statements {
+		proof, _ := fs.ProveOr(o, known, randomScalar(t), rand.Reader)
+		if fs.VerifyOr(o, proof) {
+			t.Fatal("proof without a witness accepted")
+		}
+	}
+}
+
+func TestTampered(t *testing.T) {
+	B := bases(t)[0]
+	x := randomScalar(t)
+	st := Schnorr(B, B.ScalarMult(x))
+	proof, _ := fs.Prove(st, x, rand.Reader)
+
+	one := big.NewInt(1)
+	for i, p := range []*Proof{
+		{new(big.Int).Add(proof.C, one), proof.S},
+		{proof.C, new(big.Int).Add(proof.S, one)},
+		{proof.C, new(big.Int).Add(proof.S, bn256.Order)},
+		{nil, proof.S},
+	} {
+		if fs.Verify(st, p) {
+			t.Errorf("tampered proof %d accepted", i)
+		}
+	}
+
+	o := &Or{[]*Statement{st, Schnorr(B, B.ScalarMult(randomScalar(t)))}}
+	orProof, _ := fs.ProveOr(o, 0, x, rand.Reader)
+	orProof.C[0], orProof.C[1] = orProof.C[1], orProof.C[0]
+	if fs.VerifyOr(o, orProof) {
+		t.Fatal("tampered or-proof accepted")
+	}
+}
+
+func TestPluggableHash(t *testing.T) {
+	B := bases(t)[1]
+	x := randomScalar(t)
+	st := Schnorr(B, B.ScalarMult(x))
+
+	keccak := &FiatShamir{Hash: sha3.NewLegacyKeccak256, Domain: []byte("test")}
+	wide := &FiatShamir{Hash: sha512.New, Domain: []byte("test")}
+	otherDomain := &FiatShamir{Hash: sha256.New, Domain: []byte("other")}
+
+	for _, f := range []*FiatShamir{keccak, wide} {
+		proof, err := f.Prove(st, x, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !f.Verify(st, proof) {
+			t.Fatal("valid proof rejected")
+		}
+		if fs.Verify(st, proof) {
+			t.Fatal("proof accepted with another hash")
+		}
+	}
+
+	proof, _ := fs.Prove(st, x, rand.Reader)
+	if otherDomain.Verify(st, proof) {
+		t.Fatal("proof accepted in another domain")
+	}
+}
+
+func TestMalformedStatement(t *testing.T) {
+	B := bases(t)[0]
+	if _, err := fs.Prove(&Statement{}, big.NewInt(1), rand.Reader); err == nil {
+		t.Fatal("empty statement accepted")
+	}
+	if _, err := fs.Prove(&Statement{[]Element{B}, nil}, big.NewInt(1), rand.Reader); err == nil {
+		t.Fatal("statement without images accepted")
+	}
+	o := &Or{[]*Statement{Schnorr(B, B)}}
+	if _, err := fs.ProveOr(o, 1, big.NewInt(1), rand.Reader); err == nil {
+		t.Fatal("known statement out of range accepted")
+	}
+}
@@ -0,0 +1,90 @@
+package solgen
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+	"golang.org/x/crypto/sha3"
+)
+
+// Proof is a Groth16 proof.
+type Proof struct {
+	A *bn256.G1
+	B *bn256.G2
+	C *bn256.G1
+}
+
+// Marshal converts p into the 256-byte encoding taken by the generated
+// contract: A.Marshal(), B.Marshal() and C.Marshal() in turn.
+func (p *Proof) Marshal() []byte {
+	ret := make([]byte, 0, 256)
+	ret = append(ret, p.A.Marshal()...)
+	ret = append(ret, p.B.Marshal()...)
+	ret = append(ret, p.C.Marshal()...)
+	return ret
+}
+
+// Unmarshal sets p to the result of converting the output of Marshal back
+// into a proof.
+func (p *Proof) Unmarshal(m []byte) error {
+	if len(m) != 256 {
+		return errors.New("solgen: malformed proof")
+	}
+
+	q := Proof{A: new(bn256.G1), B: new(bn256.G2), C: new(bn256.G1)}
+	var err error
+	if m, err = q.A.Unmarshal(m); err != nil {
+		return err
+	}
+	if m, err = q.B.Unmarshal(m); err != nil {
+		return err
+	}
+	if _, err = q.C.Unmarshal(m); err != nil {
+		return err
+	}
+
+	*p = q
+	return nil
+}
+
+// verifyProofSignature is the signature of the verifier function of the
+// generated contract.
+const verifyProofSignature = "verifyProof(bytes,uint256[])"
+
+// Calldata returns the ABI-encoded calldata of a call to verifyProof on the
+// generated contract with p and the given public inputs.
+func (p *Proof) Calldata(inputs []*big.Int) ([]byte, error) {
+	for _, x := range inputs {
+		if x.Sign() < 0 || x.Cmp(bn256.Order) >= 0 {
+			return nil, errors.New("solgen: public input out of range")
+		}
+	}
+	proof := p.Marshal()
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(verifyProofSignature))
+	selector := h.Sum(nil)[:4]
+
+	// The head holds the offsets of the two dynamic arguments. The proof is
+	// 256 bytes long, so needs no padding.
+	ret := make([]byte, 0, 4+4*32+len(proof)+32*len(inputs))
+	ret = append(ret, selector...)
+	ret = appendWord(ret, big.NewInt(2*32))
+	ret = appendWord(ret, big.NewInt(int64(3*32+len(proof))))
+	ret = appendWord(ret, big.NewInt(int64(len(proof))))
+	ret = append(ret, proof...)
+	ret = appendWord(ret, big.NewInt(int64(len(inputs))))
+	for _, x := range inputs {
+		ret = appendWord(ret, x)
+	}
+	return ret, nil
+}
+
+// appendWord appends the 32-byte big-endian encoding of x to out.
+func appendWord(out []byte, x *big.Int) []byte {
+	var word [32]byte
+	b := x.Bytes()
+	copy(word[32-len(b):], b)
+	return append(out, word[:]...)
+}
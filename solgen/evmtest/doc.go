@@ -0,0 +1,6 @@
+// Package evmtest compiles the verifiers generated by solgen with solc and runs
+// them on go-ethereum's simulated backend. It is a separate module so that the
+// bn256 module does not depend on go-ethereum; run its tests from this
+// directory with solc on the PATH, or with SOLC naming the compiler to use.
+// Without solc they are skipped, unless SOLC is set, in which case they fail.
+package evmtest
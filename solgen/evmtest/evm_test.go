@@ -0,0 +1,182 @@
+package evmtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/solgen"
+	"github.com/clearmatics/bn256/solgen/solgentest"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// compile compiles the contract called name in src with solc and returns its
+// ABI and creation bytecode.
+func compile(t *testing.T, solc, name string, src []byte) (abi.ABI, []byte) {
+	t.Helper()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, name+".sol")
+	if err := os.WriteFile(file, src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(solc, "--combined-json", "abi,bin", name+".sol")
+	cmd.Dir = dir
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("solc: %v", err)
+	}
+
+	var res struct {
+		Contracts map[string]struct {
+			ABI json.RawMessage
+			Bin string
+		}
+	}
+	if err := json.Unmarshal(out, &res); err != nil {
+		t.Fatal(err)
+	}
+	c, ok := res.Contracts[name+".sol:"+name]
+	if !ok {
+		t.Fatalf("solc output has no contract %s", name)
+	}
+
+	// Older versions of solc give the ABI as a JSON string.
+	abiJSON := []byte(c.ABI)
+	var s string
+	if json.Unmarshal(abiJSON, &s) == nil {
+		abiJSON = []byte(s)
+	}
+	parsed, err := abi.JSON(bytes.NewReader(abiJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed, common.FromHex(c.Bin)
+}
+
+// solcPath returns the solc binary named by $SOLC, failing the test if it
+// cannot be found, or else the one on the PATH. The test is skipped only when
+// $SOLC is unset and there is no solc on the PATH.
+func solcPath(t *testing.T) string {
+	t.Helper()
+
+	if name := os.Getenv("SOLC"); name != "" {
+		solc, err := exec.LookPath(name)
+		if err != nil {
+			t.Fatalf("SOLC=%s: %v", name, err)
+		}
+		return solc
+	}
+	solc, err := exec.LookPath("solc")
+	if err != nil {
+		t.Skip("solc not found; set SOLC to require it")
+	}
+	return solc
+}
+
+func TestVerifierOnEVM(t *testing.T) {
+	solc := solcPath(t)
+
+	vk, proof, inputs := solgentest.Setup(t, 3)
+	src := new(bytes.Buffer)
+	if err := solgen.Generate(src, vk, &solgen.Options{ContractName: "Verifier", License: "MIT"}); err != nil {
+		t.Fatal(err)
+	}
+	parsed, bin := compile(t, solc, "Verifier", src.Bytes())
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	backend := simulated.NewBackend(types.GenesisAlloc{auth.From: {Balance: balance}})
+	defer backend.Close()
+	client := backend.Client()
+
+	addr, _, _, err := bind.DeployContract(auth, parsed, bin, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	// verify calls verifyProof with the calldata built by Proof.Calldata. A
+	// reverted call counts as a rejection.
+	verify := func(p *solgen.Proof, inputs []*big.Int) bool {
+		t.Helper()
+		calldata, err := p.Calldata(inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := ethereum.CallMsg{From: auth.From, To: &addr, Data: calldata}
+		out, err := client.CallContract(context.Background(), msg, nil)
+		if err != nil {
+			return false
+		}
+		res, err := parsed.Unpack("verifyProof", out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res[0].(bool)
+	}
+
+	if !verify(proof, inputs) {
+		t.Fatal("valid proof rejected")
+	}
+
+	wrong := append([]*big.Int(nil), inputs...)
+	wrong[1] = new(big.Int).Add(wrong[1], big.NewInt(1))
+	if verify(proof, wrong) {
+		t.Error("proof accepted for wrong inputs")
+	}
+	if verify(proof, inputs[:2]) {
+		t.Error("proof accepted with too few inputs")
+	}
+
+	bad := &solgen.Proof{A: proof.A, B: proof.B, C: new(bn256.G1).Neg(proof.C)}
+	if verify(bad, inputs) {
+		t.Error("modified proof accepted")
+	}
+}
+
+// TestCalldataLayout checks the calldata of Proof.Calldata against the ABI
+// encoding of go-ethereum, which does not need solc.
+func TestCalldataLayout(t *testing.T) {
+	const verifierABI = `[{"type":"function","name":"verifyProof","stateMutability":"view",` +
+		`"inputs":[{"name":"proof","type":"bytes"},{"name":"input","type":"uint256[]"}],` +
+		`"outputs":[{"name":"","type":"bool"}]}]`
+	parsed, err := abi.JSON(strings.NewReader(verifierABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, proof, inputs := solgentest.Setup(t, 3)
+	want, err := parsed.Pack("verifyProof", proof.Marshal(), inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := proof.Calldata(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("calldata differs from the ABI encoding:\n got %x\nwant %x", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+// Package solgen generates Solidity verifiers for Groth16 proofs over this
+// curve.
+//
+// The generated contract checks proofs with the Ethereum precompiles for
+// addition (0x06), scalar multiplication (0x07) and pairing checks (0x08) on
+// alt_bn128. It takes the proof as the concatenation of the G1.Marshal and
+// G2.Marshal encodings of its three points. G2.Marshal writes the imaginary
+// part of each coordinate first, which is the order expected by the pairing
+// precompile (EIP-197), so the proof bytes are passed to the precompile
+// without reordering.
+//
+// The tests of the separate module in the evmtest directory compile generated
+// contracts with solc and run them on go-ethereum's simulated backend; see
+// the test-evm target of the Makefile.
+package solgen
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"regexp"
+	"text/template"
+
+	"github.com/clearmatics/bn256"
+)
+
+// VerifyingKey is a Groth16 verifying key. A proof (A, B, C) for the public
+// inputs x₁, …, xₙ is valid if
+//
+//	e(A, B) = e(Alpha, Beta)·e(IC₀ + x₁·IC₁ + … + xₙ·ICₙ, Gamma)·e(C, Delta)
+type VerifyingKey struct {
+	Alpha              *bn256.G1
+	Beta, Gamma, Delta *bn256.G2
+	IC                 []*bn256.G1
+}
+
+// Marshal converts vk into a byte slice holding the encodings of Alpha, Beta,
+// Gamma, Delta and each element of IC in turn.
+func (vk *VerifyingKey) Marshal() []byte {
+	ret := make([]byte, 0, 64+3*128+64*len(vk.IC))
+	ret = append(ret, vk.Alpha.Marshal()...)
+	ret = append(ret, vk.Beta.Marshal()...)
+	ret = append(ret, vk.Gamma.Marshal()...)
+	ret = append(ret, vk.Delta.Marshal()...)
+	for _, p := range vk.IC {
+		ret = append(ret, p.Marshal()...)
+	}
+	return ret
+}
+
+// Unmarshal sets vk to the result of converting the output of Marshal back
+// into a verifying key.
+func (vk *VerifyingKey) Unmarshal(m []byte) error {
+	k := VerifyingKey{
+		Alpha: new(bn256.G1),
+		Beta:  new(bn256.G2),
+		Gamma: new(bn256.G2),
+		Delta: new(bn256.G2),
+	}
+
+	var err error
+	if m, err = k.Alpha.Unmarshal(m); err != nil {
+		return err
+	}
+	if m, err = k.Beta.Unmarshal(m); err != nil {
+		return err
+	}
+	if m, err = k.Gamma.Unmarshal(m); err != nil {
+		return err
+	}
+	if m, err = k.Delta.Unmarshal(m); err != nil {
+		return err
+	}
+	if len(m) == 0 || len(m)%64 != 0 {
+		return errors.New("solgen: malformed verifying key")
+	}
+	for len(m) > 0 {
+		p := new(bn256.G1)
+		if m, err = p.Unmarshal(m); err != nil {
+			return err
+		}
+		k.IC = append(k.IC, p)
+	}
+
+	*vk = k
+	return nil
+}
+
+// Options configure the generated contract.
+type Options struct {
+	// ContractName is the name of the contract. It defaults to "Verifier".
+	ContractName string
+	// License is the SPDX license identifier written at the top of the
+	// file. It defaults to "UNLICENSED".
+	License string
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// Generate writes a Solidity contract verifying proofs under vk to w. The
+// contract exposes
+//
+//	function verifyProof(bytes calldata proof, uint256[] calldata input) external view returns (bool)
+//
+// where proof is the output of Proof.Marshal. Calldata for this function is
+// built by Proof.Calldata.
+func Generate(w io.Writer, vk *VerifyingKey, opts *Options) error {
+	if len(vk.IC) == 0 {
+		return errors.New("solgen: verifying key has no IC points")
+	}
+
+	data := &contract{Name: "Verifier", License: "UNLICENSED"}
+	if opts != nil && opts.ContractName != "" {
+		data.Name = opts.ContractName
+	}
+	if opts != nil && opts.License != "" {
+		data.License = opts.License
+	}
+	if !identifier.MatchString(data.Name) {
+		return errors.New("solgen: invalid contract name")
+	}
+
+	data.Alpha = g1Words(vk.Alpha)
+	data.Beta = g2Words(vk.Beta)
+	data.Gamma = g2Words(vk.Gamma)
+	data.Delta = g2Words(vk.Delta)
+	for _, p := range vk.IC {
+		data.IC = append(data.IC, g1Words(p))
+	}
+
+	return contractTemplate.Execute(w, data)
+}
+
+// contract holds the values substituted into contractTemplate. Points are
+// held as the decimal values of the 32-byte words of their encodings.
+type contract struct {
+	Name, License      string
+	Alpha              []string
+	Beta, Gamma, Delta []string
+	IC                 [][]string
+}
+
+func g1Words(p *bn256.G1) []string {
+	return words(p.Marshal())
+}
+
+func g2Words(p *bn256.G2) []string {
+	return words(p.Marshal())
+}
+
+func words(m []byte) []string {
+	ret := make([]string, 0, len(m)/32)
+	for i := 0; i < len(m); i += 32 {
+		ret = append(ret, new(big.Int).SetBytes(m[i:i+32]).String())
+	}
+	return ret
+}
+
+var contractTemplate = template.Must(template.New("contract").Funcs(template.FuncMap{
+	"inputs": func(ic [][]string) int { return len(ic) - 1 },
+	"dec":    func(i int) int { return i - 1 },
+}).Parse(contractSource))
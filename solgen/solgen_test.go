@@ -0,0 +1,159 @@
+package solgen_test
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/solgen"
+	"github.com/clearmatics/bn256/solgen/solgentest"
+	"golang.org/x/crypto/sha3"
+)
+
+// verifyCalldata decodes calldata and performs the same checks as the
+// generated contract.
+func verifyCalldata(t *testing.T, vk *solgen.VerifyingKey, calldata []byte) bool {
+	t.Helper()
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte("verifyProof(bytes,uint256[])"))
+	if !bytes.Equal(calldata[:4], h.Sum(nil)[:4]) {
+		t.Fatal("wrong selector")
+	}
+	args := calldata[4:]
+	word := func(off int) *big.Int { return new(big.Int).SetBytes(args[off : off+32]) }
+
+	proofOff := int(word(0).Int64())
+	proofLen := int(word(proofOff).Int64())
+	if proofLen != 256 {
+		t.Fatalf("proof length %d", proofLen)
+	}
+	w := args[proofOff+32 : proofOff+32+proofLen]
+
+	inputOff := int(word(32).Int64())
+	n := int(word(inputOff).Int64())
+	if n != len(vk.IC)-1 {
+		t.Fatalf("input length %d", n)
+	}
+
+	x := new(bn256.G1).Set(vk.IC[0])
+	for i := 0; i < n; i++ {
+		s := word(inputOff + 32 + 32*i)
+		x.Add(x, new(bn256.G1).ScalarMult(vk.IC[i+1], s))
+	}
+
+	// The contract negates A by replacing y with (Q - y) % Q.
+	negA := make([]byte, 64)
+	copy(negA, w[:32])
+	y := new(big.Int).SetBytes(w[32:64])
+	y.Sub(bn256.P, y).Mod(y, bn256.P)
+	b := y.Bytes()
+	copy(negA[64-len(b):], b)
+
+	A, B, C := new(bn256.G1), new(bn256.G2), new(bn256.G1)
+	if _, err := A.Unmarshal(negA); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := B.Unmarshal(w[64:192]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := C.Unmarshal(w[192:256]); err != nil {
+		t.Fatal(err)
+	}
+	return bn256.PairingCheck(
+		[]*bn256.G1{A, vk.Alpha, x, C},
+		[]*bn256.G2{B, vk.Beta, vk.Gamma, vk.Delta},
+	)
+}
+
+// word returns the i-th 32-byte word of m in decimal, as the generated
+// contract writes its constants.
+func word(m []byte, i int) string {
+	return new(big.Int).SetBytes(m[32*i : 32*i+32]).String()
+}
+
+func TestCalldata(t *testing.T) {
+	vk, proof, inputs := solgentest.Setup(t, 3)
+
+	calldata, err := proof.Calldata(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verifyCalldata(t, vk, calldata) {
+		t.Fatal("valid proof rejected")
+	}
+
+	inputs[0].Add(inputs[0], big.NewInt(1))
+	calldata, err = proof.Calldata(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verifyCalldata(t, vk, calldata) {
+		t.Fatal("proof accepted for wrong inputs")
+	}
+
+	if _, err := proof.Calldata([]*big.Int{bn256.Order}); err == nil {
+		t.Fatal("out of range input accepted")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	vk, _, _ := solgentest.Setup(t, 2)
+
+	buf := new(bytes.Buffer)
+	if err := solgen.Generate(buf, vk, &solgen.Options{ContractName: "Groth16Verifier", License: "MIT"}); err != nil {
+		t.Fatal(err)
+	}
+	src := buf.String()
+
+	want := []string{
+		"// SPDX-License-Identifier: MIT",
+		"contract Groth16Verifier {",
+		"uint256 constant INPUTS = 2;",
+		"uint256 constant ALPHA_X = " + word(vk.Alpha.Marshal(), 0) + ";",
+		"uint256 constant BETA_X_IM = " + word(vk.Beta.Marshal(), 0) + ";",
+		"uint256 constant BETA_X_RE = " + word(vk.Beta.Marshal(), 1) + ";",
+		"uint256 constant DELTA_Y_RE = " + word(vk.Delta.Marshal(), 3) + ";",
+		"uint256 constant IC2_Y = " + word(vk.IC[2].Marshal(), 1) + ";",
+		"require(input[1] < R",
+		"ecMul([IC2_X, IC2_Y], input[1])",
+	}
+	for _, s := range want {
+		if !strings.Contains(src, s) {
+			t.Errorf("generated contract does not contain %q", s)
+		}
+	}
+	if strings.Contains(src, "IC3_X") {
+		t.Error("generated contract has too many IC points")
+	}
+
+	if err := solgen.Generate(buf, vk, &solgen.Options{ContractName: "not a name"}); err == nil {
+		t.Error("invalid contract name accepted")
+	}
+}
+
+func TestVerifyingKeyMarshal(t *testing.T) {
+	vk, proof, _ := solgentest.Setup(t, 1)
+
+	vk2 := new(solgen.VerifyingKey)
+	if err := vk2.Unmarshal(vk.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(vk.Marshal(), vk2.Marshal()) {
+		t.Fatal("verifying key changed after round trip")
+	}
+
+	proof2 := new(solgen.Proof)
+	if err := proof2.Unmarshal(proof.Marshal()); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(proof.Marshal(), proof2.Marshal()) {
+		t.Fatal("proof changed after round trip")
+	}
+
+	if err := vk2.Unmarshal(vk.Marshal()[:64+3*128]); err == nil {
+		t.Fatal("verifying key without IC points accepted")
+	}
+}
@@ -0,0 +1,61 @@
+// Package solgentest provides utilities for testing the verifiers generated by
+// solgen.
+package solgentest
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/solgen"
+)
+
+// Setup returns a verifying key with n public inputs together with a valid
+// proof for random inputs. The proof is built from the trapdoor of the key
+// rather than from a circuit.
+func Setup(t testing.TB, n int) (*solgen.VerifyingKey, *solgen.Proof, []*big.Int) {
+	t.Helper()
+
+	random := func() *big.Int {
+		k, err := rand.Int(rand.Reader, bn256.Order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+	alpha, beta, gamma, delta := random(), random(), random(), random()
+
+	vk := &solgen.VerifyingKey{
+		Alpha: new(bn256.G1).ScalarBaseMult(alpha),
+		Beta:  new(bn256.G2).ScalarBaseMult(beta),
+		Gamma: new(bn256.G2).ScalarBaseMult(gamma),
+		Delta: new(bn256.G2).ScalarBaseMult(delta),
+	}
+	ic := make([]*big.Int, n+1)
+	for i := range ic {
+		ic[i] = random()
+		vk.IC = append(vk.IC, new(bn256.G1).ScalarBaseMult(ic[i]))
+	}
+
+	// Solve a·b = alpha·beta + (ic₀ + Σ xᵢ·icᵢ)·gamma + c·delta for c.
+	inputs := make([]*big.Int, n)
+	sum := new(big.Int).Set(ic[0])
+	for i := range inputs {
+		inputs[i] = random()
+		sum.Add(sum, new(big.Int).Mul(inputs[i], ic[i+1]))
+	}
+	a, b := random(), random()
+	c := new(big.Int).Mul(a, b)
+	c.Sub(c, new(big.Int).Mul(alpha, beta))
+	c.Sub(c, sum.Mul(sum, gamma))
+	c.Mul(c, new(big.Int).ModInverse(delta, bn256.Order))
+	c.Mod(c, bn256.Order)
+
+	proof := &solgen.Proof{
+		A: new(bn256.G1).ScalarBaseMult(a),
+		B: new(bn256.G2).ScalarBaseMult(b),
+		C: new(bn256.G1).ScalarBaseMult(c),
+	}
+	return vk, proof, inputs
+}
@@ -0,0 +1,113 @@
+package solgen
+
+const contractSource = `// SPDX-License-Identifier: {{.License}}
+pragma solidity ^0.8.0;
+
+// {{.Name}} verifies Groth16 proofs over alt_bn128.
+//
+// A proof is the 256-byte concatenation of the points A (64 bytes), B (128
+// bytes) and C (64 bytes), each encoded as 32-byte big-endian words. G1 points
+// are (x, y). G2 points are (x_im, x_re, y_im, y_re), the order expected by
+// the pairing precompile.
+//
+// This file was generated by bn256-solgen. Do not edit.
+contract {{.Name}} {
+    // Order of the groups.
+    uint256 constant R = 21888242871839275222246405745257275088548364400416034343698204186575808495617;
+    // Modulus of the base field.
+    uint256 constant Q = 21888242871839275222246405745257275088696311157297823662689037894645226208583;
+
+    uint256 constant ALPHA_X = {{index .Alpha 0}};
+    uint256 constant ALPHA_Y = {{index .Alpha 1}};
+
+    uint256 constant BETA_X_IM = {{index .Beta 0}};
+    uint256 constant BETA_X_RE = {{index .Beta 1}};
+    uint256 constant BETA_Y_IM = {{index .Beta 2}};
+    uint256 constant BETA_Y_RE = {{index .Beta 3}};
+
+    uint256 constant GAMMA_X_IM = {{index .Gamma 0}};
+    uint256 constant GAMMA_X_RE = {{index .Gamma 1}};
+    uint256 constant GAMMA_Y_IM = {{index .Gamma 2}};
+    uint256 constant GAMMA_Y_RE = {{index .Gamma 3}};
+
+    uint256 constant DELTA_X_IM = {{index .Delta 0}};
+    uint256 constant DELTA_X_RE = {{index .Delta 1}};
+    uint256 constant DELTA_Y_IM = {{index .Delta 2}};
+    uint256 constant DELTA_Y_RE = {{index .Delta 3}};
+{{range $i, $p := .IC}}
+    uint256 constant IC{{$i}}_X = {{index $p 0}};
+    uint256 constant IC{{$i}}_Y = {{index $p 1}};
+{{- end}}
+
+    uint256 constant INPUTS = {{inputs .IC}};
+
+    function verifyProof(bytes calldata proof, uint256[] calldata input) external view returns (bool) {
+        require(proof.length == 256, "verifier: bad proof length");
+        require(input.length == INPUTS, "verifier: bad input length");
+
+        uint256[8] memory w = abi.decode(proof, (uint256[8]));
+        require(w[1] < Q, "verifier: bad proof");
+
+        uint256[2] memory x = [IC0_X, IC0_Y];
+{{- range $i, $p := .IC}}{{if $i}}
+        require(input[{{dec $i}}] < R, "verifier: bad input");
+        x = ecAdd(x, ecMul([IC{{$i}}_X, IC{{$i}}_Y], input[{{dec $i}}]));
+{{- end}}{{end}}
+
+        uint256[24] memory p;
+        // e(-A, B)
+        p[0] = w[0];
+        p[1] = (Q - w[1]) % Q;
+        p[2] = w[2];
+        p[3] = w[3];
+        p[4] = w[4];
+        p[5] = w[5];
+        // e(alpha, beta)
+        p[6] = ALPHA_X;
+        p[7] = ALPHA_Y;
+        p[8] = BETA_X_IM;
+        p[9] = BETA_X_RE;
+        p[10] = BETA_Y_IM;
+        p[11] = BETA_Y_RE;
+        // e(IC0 + input[0] * IC1 + ..., gamma)
+        p[12] = x[0];
+        p[13] = x[1];
+        p[14] = GAMMA_X_IM;
+        p[15] = GAMMA_X_RE;
+        p[16] = GAMMA_Y_IM;
+        p[17] = GAMMA_Y_RE;
+        // e(C, delta)
+        p[18] = w[6];
+        p[19] = w[7];
+        p[20] = DELTA_X_IM;
+        p[21] = DELTA_X_RE;
+        p[22] = DELTA_Y_IM;
+        p[23] = DELTA_Y_RE;
+
+        uint256[1] memory out;
+        bool ok;
+        assembly {
+            ok := staticcall(gas(), 8, p, 768, out, 32)
+        }
+        return ok && out[0] == 1;
+    }
+
+    function ecAdd(uint256[2] memory a, uint256[2] memory b) internal view returns (uint256[2] memory r) {
+        uint256[4] memory args = [a[0], a[1], b[0], b[1]];
+        bool ok;
+        assembly {
+            ok := staticcall(gas(), 6, args, 128, r, 64)
+        }
+        require(ok, "verifier: ecAdd failed");
+    }
+
+    function ecMul(uint256[2] memory a, uint256 s) internal view returns (uint256[2] memory r) {
+        uint256[3] memory args = [a[0], a[1], s];
+        bool ok;
+        assembly {
+            ok := staticcall(gas(), 7, args, 96, r, 64)
+        }
+        require(ok, "verifier: ecMul failed");
+    }
+}
+`
@@ -0,0 +1,377 @@
+package bn256
+
+// Square roots and quadratic residuosity in GF(p) and GF(p²).
+//
+// Since p ≡ 3 mod 4, a square a ∈ GF(p) has the root a^((p+1)/4), and for any
+// a the square of that candidate is a·χ(a), where χ(a) = a^((p-1)/2) is the
+// Legendre symbol, so one exponentiation both finds the root and tells
+// whether there is one. An element of GF(p²) is a square if and only if its
+// norm is a square in GF(p), and its roots are found from roots in GF(p) with
+// the complex method.
+
+// Sqrt sets e to a^((p+1)/4), which is a square root of a if there is one,
+// and returns whether it is. It runs in constant time.
+func (e *gfP) Sqrt(a *gfP) bool {
+	t, s := &gfP{}, &gfP{}
+	t.Set(a)
+	s.expSqrt(t)
+	e.Set(s)
+	gfpMul(s, s, s)
+	return gfpEqual(s, t)
+}
+
+// Legendre returns the Legendre symbol of e: 1 if e is a non-zero square, -1
+// if it is not a square and 0 if it is zero.
+func (e *gfP) Legendre() int {
+	s := &gfP{}
+	s.expSqrt(e)
+	gfpMul(s, s, s)
+	switch {
+	case *e == gfP{}:
+		return 0
+	case gfpEqual(s, e):
+		return 1
+	}
+	return -1
+}
+
+// gfpEqual returns whether a and b are equal, in constant time.
+func gfpEqual(a, b *gfP) bool {
+	var d uint64
+	for i := range a {
+		d |= a[i] ^ b[i]
+	}
+	return d == 0
+}
+
+// norm returns a₀² + a₁², the norm of a = a₀ + a₁·i in GF(p).
+func (e *gfP2) norm() *gfP {
+	n, t := &gfP{}, &gfP{}
+	gfpMul(n, &e.x, &e.x)
+	gfpMul(t, &e.y, &e.y)
+	gfpAdd(n, n, t)
+	return n
+}
+
+// Legendre returns 1 if e is a non-zero square in GF(p²), -1 if it is not a
+// square and 0 if it is zero.
+func (e *gfP2) Legendre() int {
+	return e.norm().Legendre()
+}
+
+// Sqrt sets e to a square root of a and returns true, or leaves e unchanged
+// and returns false if a is not a square. Unlike gfP.Sqrt, its running time
+// depends on a.
+//
+// It uses the complex method: if x₀ + x₁·i is a root of a₀ + a₁·i with a₁ ≠ 0,
+// then x₀² = (a₀ ± √(a₀²+a₁²))/2 and x₁ = a₁/(2x₀), and exactly one choice of
+// sign gives a square.
+func (e *gfP2) Sqrt(a *gfP2) bool {
+	zero := gfP{}
+	if a.x == zero {
+		// One of a₀ and -a₀ = i²·a₀ has a root in GF(p).
+		r := &gfP{}
+		if r.Sqrt(&a.y) {
+			e.x, e.y = zero, *r
+			return true
+		}
+		gfpNeg(r, &a.y)
+		r.Sqrt(r)
+		e.x, e.y = *r, zero
+		return true
+	}
+
+	lambda := &gfP{}
+	if !lambda.Sqrt(a.norm()) {
+		return false
+	}
+	half := newGFp(2)
+	half.Invert(half)
+
+	delta, x0 := &gfP{}, &gfP{}
+	gfpAdd(delta, &a.y, lambda)
+	gfpMul(delta, delta, half)
+	if !x0.Sqrt(delta) {
+		gfpSub(delta, &a.y, lambda)
+		gfpMul(delta, delta, half)
+		x0.Sqrt(delta)
+	}
+
+	x1 := &gfP{}
+	gfpAdd(x1, x0, x0)
+	x1.Invert(x1)
+	gfpMul(x1, x1, &a.x)
+	e.x, e.y = *x1, *x0
+	return true
+}
+
+// expSqrt sets e to x^((p+1)/4) using an addition chain of 300 squarings and
+// multiplications, generated with github.com/mmcloughlin/addchain. The chain
+// is fixed, so the running time does not depend on x.
+func (e *gfP) expSqrt(x *gfP) {
+	var (
+		t0  = &gfP{}
+		t1  = &gfP{}
+		t2  = &gfP{}
+		t3  = &gfP{}
+		t4  = &gfP{}
+		t5  = &gfP{}
+		t6  = &gfP{}
+		t7  = &gfP{}
+		t8  = &gfP{}
+		t9  = &gfP{}
+		t10 = &gfP{}
+		t11 = &gfP{}
+		t12 = &gfP{}
+		t13 = &gfP{}
+		t14 = &gfP{}
+		t15 = &gfP{}
+		t16 = &gfP{}
+		t17 = &gfP{}
+		t18 = &gfP{}
+		z   = &gfP{}
+	)
+
+	// Step 1: t4 = x^0x2
+	gfpMul(t4, x, x)
+	// Step 2: t13 = x^0x3
+	gfpMul(t13, x, t4)
+	// Step 3: t8 = x^0x5
+	gfpMul(t8, t4, t13)
+	// Step 4: t0 = x^0x6
+	gfpMul(t0, x, t8)
+	// Step 5: t9 = x^0x7
+	gfpMul(t9, x, t0)
+	// Step 6: t3 = x^0xb
+	gfpMul(t3, t8, t0)
+	// Step 7: t1 = x^0xc
+	gfpMul(t1, x, t3)
+	// Step 8: t7 = x^0xd
+	gfpMul(t7, x, t1)
+	// Step 9: t6 = x^0xf
+	gfpMul(t6, t4, t7)
+	// Step 10: t15 = x^0x11
+	gfpMul(t15, t4, t6)
+	// Step 11: t16 = x^0x13
+	gfpMul(t16, t4, t15)
+	// Step 12: t11 = x^0x17
+	gfpMul(t11, t0, t15)
+	// Step 13: t10 = x^0x19
+	gfpMul(t10, t4, t11)
+	// Step 14: t14 = x^0x1b
+	gfpMul(t14, t4, t10)
+	// Step 15: t2 = x^0x1f
+	gfpMul(t2, t0, t10)
+	// Step 16: t5 = x^0x23
+	gfpMul(t5, t1, t11)
+	// Step 17: t17 = x^0x27
+	gfpMul(t17, t1, t14)
+	// Step 18: z = x^0x29
+	gfpMul(z, t4, t17)
+	// Step 19: t12 = x^0x2b
+	gfpMul(t12, t4, z)
+	// Step 20: t4 = x^0x2d
+	gfpMul(t4, t4, t12)
+	// Step 21: t1 = x^0x39
+	gfpMul(t1, t1, t4)
+	// Step 22: t18 = x^0x60
+	gfpMul(t18, t17, t1)
+	// Step 27: t18 = x^0xc00
+	for s := 0; s < 5; s++ {
+		gfpMul(t18, t18, t18)
+	}
+	// Step 28: t18 = x^0xc19
+	gfpMul(t18, t10, t18)
+	// Step 37: t18 = x^0x183200
+	for s := 0; s < 9; s++ {
+		gfpMul(t18, t18, t18)
+	}
+	// Step 38: t17 = x^0x183227
+	gfpMul(t17, t17, t18)
+	// Step 46: t17 = x^0x18322700
+	for s := 0; s < 8; s++ {
+		gfpMul(t17, t17, t17)
+	}
+	// Step 47: t17 = x^0x18322739
+	gfpMul(t17, t1, t17)
+	// Step 51: t17 = x^0x183227390
+	for s := 0; s < 4; s++ {
+		gfpMul(t17, t17, t17)
+	}
+	// Step 52: t17 = x^0x183227397
+	gfpMul(t17, t9, t17)
+	// Step 61: t17 = x^0x30644e72e00
+	for s := 0; s < 9; s++ {
+		gfpMul(t17, t17, t17)
+	}
+	// Step 62: t16 = x^0x30644e72e13
+	gfpMul(t16, t16, t17)
+	// Step 69: t16 = x^0x1832273970980
+	for s := 0; s < 7; s++ {
+		gfpMul(t16, t16, t16)
+	}
+	// Step 70: t16 = x^0x183227397098d
+	gfpMul(t16, t7, t16)
+	// Step 83: t16 = x^0x30644e72e131a000
+	for s := 0; s < 13; s++ {
+		gfpMul(t16, t16, t16)
+	}
+	// Step 84: t16 = x^0x30644e72e131a029
+	gfpMul(t16, z, t16)
+	// Step 89: t16 = x^0x60c89ce5c26340520
+	for s := 0; s < 5; s++ {
+		gfpMul(t16, t16, t16)
+	}
+	// Step 90: t16 = x^0x60c89ce5c26340537
+	gfpMul(t16, t11, t16)
+	// Step 97: t16 = x^0x30644e72e131a029b80
+	for s := 0; s < 7; s++ {
+		gfpMul(t16, t16, t16)
+	}
+	// Step 98: t16 = x^0x30644e72e131a029b85
+	gfpMul(t16, t8, t16)
+	// Step 108: t16 = x^0xc19139cb84c680a6e1400
+	for s := 0; s < 10; s++ {
+		gfpMul(t16, t16, t16)
+	}
+	// Step 109: t15 = x^0xc19139cb84c680a6e1411
+	gfpMul(t15, t15, t16)
+	// Step 115: t15 = x^0x30644e72e131a029b850440
+	for s := 0; s < 6; s++ {
+		gfpMul(t15, t15, t15)
+	}
+	// Step 116: t14 = x^0x30644e72e131a029b85045b
+	gfpMul(t14, t14, t15)
+	// Step 121: t14 = x^0x60c89ce5c263405370a08b60
+	for s := 0; s < 5; s++ {
+		gfpMul(t14, t14, t14)
+	}
+	// Step 122: t14 = x^0x60c89ce5c263405370a08b6d
+	gfpMul(t14, t7, t14)
+	// Step 130: t14 = x^0x60c89ce5c263405370a08b6d00
+	for s := 0; s < 8; s++ {
+		gfpMul(t14, t14, t14)
+	}
+	// Step 131: t13 = x^0x60c89ce5c263405370a08b6d03
+	gfpMul(t13, t13, t14)
+	// Step 143: t13 = x^0x60c89ce5c263405370a08b6d03000
+	for s := 0; s < 12; s++ {
+		gfpMul(t13, t13, t13)
+	}
+	// Step 144: t12 = x^0x60c89ce5c263405370a08b6d0302b
+	gfpMul(t12, t12, t13)
+	// Step 153: t12 = x^0xc19139cb84c680a6e14116da0605600
+	for s := 0; s < 9; s++ {
+		gfpMul(t12, t12, t12)
+	}
+	// Step 154: t11 = x^0xc19139cb84c680a6e14116da0605617
+	gfpMul(t11, t11, t12)
+	// Step 160: t11 = x^0x30644e72e131a029b85045b68181585c0
+	for s := 0; s < 6; s++ {
+		gfpMul(t11, t11, t11)
+	}
+	// Step 161: t10 = x^0x30644e72e131a029b85045b68181585d9
+	gfpMul(t10, t10, t11)
+	// Step 166: t10 = x^0x60c89ce5c263405370a08b6d0302b0bb20
+	for s := 0; s < 5; s++ {
+		gfpMul(t10, t10, t10)
+	}
+	// Step 167: t10 = x^0x60c89ce5c263405370a08b6d0302b0bb2f
+	gfpMul(t10, t6, t10)
+	// Step 179: t10 = x^0x60c89ce5c263405370a08b6d0302b0bb2f000
+	for s := 0; s < 12; s++ {
+		gfpMul(t10, t10, t10)
+	}
+	// Step 180: t10 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d
+	gfpMul(t10, t4, t10)
+	// Step 187: t10 = x^0x30644e72e131a029b85045b68181585d9781680
+	for s := 0; s < 7; s++ {
+		gfpMul(t10, t10, t10)
+	}
+	// Step 188: t10 = x^0x30644e72e131a029b85045b68181585d97816a9
+	gfpMul(t10, z, t10)
+	// Step 197: t10 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d5200
+	for s := 0; s < 9; s++ {
+		gfpMul(t10, t10, t10)
+	}
+	// Step 198: t10 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d522d
+	gfpMul(t10, t4, t10)
+	// Step 205: t10 = x^0x30644e72e131a029b85045b68181585d97816a91680
+	for s := 0; s < 7; s++ {
+		gfpMul(t10, t10, t10)
+	}
+	// Step 206: t9 = x^0x30644e72e131a029b85045b68181585d97816a91687
+	gfpMul(t9, t9, t10)
+	// Step 215: t9 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d522d0e00
+	for s := 0; s < 9; s++ {
+		gfpMul(t9, t9, t9)
+	}
+	// Step 216: t9 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d522d0e39
+	gfpMul(t9, t1, t9)
+	// Step 220: t9 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d522d0e390
+	for s := 0; s < 4; s++ {
+		gfpMul(t9, t9, t9)
+	}
+	// Step 221: t8 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d522d0e395
+	gfpMul(t8, t8, t9)
+	// Step 228: t8 = x^0x30644e72e131a029b85045b68181585d97816a916871ca80
+	for s := 0; s < 7; s++ {
+		gfpMul(t8, t8, t8)
+	}
+	// Step 229: t7 = x^0x30644e72e131a029b85045b68181585d97816a916871ca8d
+	gfpMul(t7, t7, t8)
+	// Step 235: t7 = x^0xc19139cb84c680a6e14116da060561765e05aa45a1c72a340
+	for s := 0; s < 6; s++ {
+		gfpMul(t7, t7, t7)
+	}
+	// Step 236: t6 = x^0xc19139cb84c680a6e14116da060561765e05aa45a1c72a34f
+	gfpMul(t6, t6, t7)
+	// Step 241: t6 = x^0x183227397098d014dc2822db40c0ac2ecbc0b548b438e5469e0
+	for s := 0; s < 5; s++ {
+		gfpMul(t6, t6, t6)
+	}
+	// Step 242: t6 = x^0x183227397098d014dc2822db40c0ac2ecbc0b548b438e5469e1
+	gfpMul(t6, x, t6)
+	// Step 253: t6 = x^0xc19139cb84c680a6e14116da060561765e05aa45a1c72a34f0800
+	for s := 0; s < 11; s++ {
+		gfpMul(t6, t6, t6)
+	}
+	// Step 254: t5 = x^0xc19139cb84c680a6e14116da060561765e05aa45a1c72a34f0823
+	gfpMul(t5, t5, t6)
+	// Step 265: t5 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d522d0e3951a78411800
+	for s := 0; s < 11; s++ {
+		gfpMul(t5, t5, t5)
+	}
+	// Step 266: t4 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d522d0e3951a7841182d
+	gfpMul(t4, t4, t5)
+	// Step 270: t4 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d522d0e3951a7841182d0
+	for s := 0; s < 4; s++ {
+		gfpMul(t4, t4, t4)
+	}
+	// Step 271: t3 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d522d0e3951a7841182db
+	gfpMul(t3, t3, t4)
+	// Step 280: t3 = x^0xc19139cb84c680a6e14116da060561765e05aa45a1c72a34f082305b600
+	for s := 0; s < 9; s++ {
+		gfpMul(t3, t3, t3)
+	}
+	// Step 281: t2 = x^0xc19139cb84c680a6e14116da060561765e05aa45a1c72a34f082305b61f
+	gfpMul(t2, t2, t3)
+	// Step 289: t2 = x^0xc19139cb84c680a6e14116da060561765e05aa45a1c72a34f082305b61f00
+	for s := 0; s < 8; s++ {
+		gfpMul(t2, t2, t2)
+	}
+	// Step 290: t1 = x^0xc19139cb84c680a6e14116da060561765e05aa45a1c72a34f082305b61f39
+	gfpMul(t1, t1, t2)
+	// Step 291: t0 = x^0xc19139cb84c680a6e14116da060561765e05aa45a1c72a34f082305b61f3f
+	gfpMul(t0, t0, t1)
+	// Step 298: t0 = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d522d0e3951a7841182db0f9f80
+	for s := 0; s < 7; s++ {
+		gfpMul(t0, t0, t0)
+	}
+	// Step 299: z = x^0x60c89ce5c263405370a08b6d0302b0bb2f02d522d0e3951a7841182db0f9fa9
+	gfpMul(z, z, t0)
+	// Step 300: z = x^0xc19139cb84c680a6e14116da060561765e05aa45a1c72a34f082305b61f3f52
+	gfpMul(z, z, z)
+
+	e.Set(z)
+}
@@ -0,0 +1,139 @@
+package bn256
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+// sqrtTestValues returns the integers in [0, n) and (p-n, p), and n random
+// ones.
+func sqrtTestValues(t *testing.T, n int) []*big.Int {
+	t.Helper()
+
+	var ret []*big.Int
+	for i := 0; i < n; i++ {
+		ret = append(ret, big.NewInt(int64(i)))
+		ret = append(ret, new(big.Int).Sub(P, big.NewInt(int64(i+1))))
+		k, err := rand.Int(rand.Reader, P)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret = append(ret, k)
+	}
+	return ret
+}
+
+// TestSqrtFp checks Sqrt and Legendre on GF(p) against big.Int.
+func TestSqrtFp(t *testing.T) {
+	for _, x := range sqrtTestValues(t, 2000) {
+		a := new(Fp).SetBig(x)
+
+		if got, want := a.Legendre(), big.Jacobi(x, P); got != want {
+			t.Fatalf("Legendre(%v) = %d, want %d", x, got, want)
+		}
+
+		want := new(big.Int).ModSqrt(x, P)
+		root := new(Fp).Sqrt(a)
+		if (root == nil) != (want == nil) {
+			t.Fatalf("Sqrt(%v): got %v, want %v", x, root, want)
+		}
+		if root == nil {
+			continue
+		}
+		got := root.Big()
+		if got.Cmp(want) != 0 && got.Cmp(new(big.Int).Sub(P, want)) != 0 {
+			t.Fatalf("Sqrt(%v) = %v, want ±%v", x, got, want)
+		}
+		if got.Sign() != 0 && big.Jacobi(got, P) != 1 {
+			t.Fatalf("Sqrt(%v) = %v is not a square", x, got)
+		}
+	}
+}
+
+// TestSqrtFp2 checks Sqrt and Legendre on GF(p²) against big.Int: a₀+a₁·i is
+// a square iff a₀²+a₁² is a square mod p, and the root is checked by squaring
+// it with big.Int arithmetic.
+func TestSqrtFp2(t *testing.T) {
+	var values []*big.Int
+	for i := int64(-20); i <= 20; i++ {
+		values = append(values, new(big.Int).Mod(big.NewInt(i), P))
+	}
+	for _, k := range sqrtTestValues(t, 20)[40:] {
+		values = append(values, k)
+	}
+
+	for _, a0 := range values {
+		for _, a1 := range values {
+			a := new(Fp2).SetBig(a0, a1)
+			norm := new(big.Int).Add(new(big.Int).Mul(a0, a0), new(big.Int).Mul(a1, a1))
+			norm.Mod(norm, P)
+
+			if got, want := a.Legendre(), big.Jacobi(norm, P); got != want {
+				t.Fatalf("Legendre(%v) = %d, want %d", a, got, want)
+			}
+
+			root := new(Fp2).Sqrt(a)
+			if (root != nil) != (big.Jacobi(norm, P) >= 0) {
+				t.Fatalf("Sqrt(%v) = %v", a, root)
+			}
+			if root == nil {
+				continue
+			}
+
+			// (x₀+x₁i)² = x₀²-x₁² + 2x₀x₁i
+			x0, x1 := root.Big()
+			re := new(big.Int).Sub(new(big.Int).Mul(x0, x0), new(big.Int).Mul(x1, x1))
+			im := new(big.Int).Mul(x0, x1)
+			im.Lsh(im, 1)
+			if re.Mod(re, P).Cmp(a0) != 0 || im.Mod(im, P).Cmp(a1) != 0 {
+				t.Fatalf("Sqrt(%v) = %v is not a root", a, root)
+			}
+		}
+	}
+}
+
+// TestSqrtAliasing checks that the receiver may be the argument.
+func TestSqrtAliasing(t *testing.T) {
+	a := new(Fp).SetInt64(4)
+	if a.Sqrt(a) == nil || a.Big().Cmp(big.NewInt(2)) != 0 && a.Big().Cmp(new(big.Int).Sub(P, big.NewInt(2))) != 0 {
+		t.Fatal("aliased Fp root is wrong")
+	}
+	b := new(Fp2).SetBig(big.NewInt(3), big.NewInt(4))
+	want := new(Fp2).Set(b)
+	if b.Sqrt(b) == nil || !new(Fp2).Square(b).Equal(want) {
+		t.Fatal("aliased Fp2 root is wrong")
+	}
+}
+
+func BenchmarkSqrtFp(b *testing.B) {
+	x, _ := rand.Int(rand.Reader, P)
+	a := new(Fp).SetBig(x.Mul(x, x))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		new(Fp).Sqrt(a)
+	}
+}
+
+func BenchmarkSqrtBig(b *testing.B) {
+	x, _ := rand.Int(rand.Reader, P)
+	x.Mul(x, x).Mod(x, P)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		new(big.Int).ModSqrt(x, P)
+	}
+}
+
+func BenchmarkSqrtFp2(b *testing.B) {
+	x, _ := rand.Int(rand.Reader, P)
+	y, _ := rand.Int(rand.Reader, P)
+	a := new(Fp2).SetBig(x, y)
+	a.Square(a)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		new(Fp2).Sqrt(a)
+	}
+}
@@ -0,0 +1,160 @@
+package tbls
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+var (
+	errThreshold = errors.New("tbls: invalid threshold")
+	errIndex     = errors.New("tbls: invalid or repeated share index")
+	errShares    = errors.New("tbls: not enough shares")
+)
+
+// Share is a share of a secret: the value of the sharing polynomial at Index,
+// which is at least 1.
+type Share struct {
+	Index int
+	Value *big.Int
+}
+
+// Commitments are Feldman commitments to the coefficients a₀, …, aₜ₋₁ of a
+// sharing polynomial: the k-th commitment is aₖ·g₂. They let the holder of a
+// share check it without learning the secret, and the first commitment is the
+// public key that matches the secret.
+type Commitments []*bn256.G2
+
+// Split shares secret among n parties so that any t of them can recover it
+// and fewer learn nothing about it. The i-th share has index i+1. Randomness is
+// read from r.
+func Split(r io.Reader, secret *big.Int, t, n int) ([]*Share, Commitments, error) {
+	if t < 1 || t > n {
+		return nil, nil, errThreshold
+	}
+
+	coeffs := make([]*big.Int, t)
+	coeffs[0] = new(big.Int).Mod(secret, bn256.Order)
+	for k := 1; k < t; k++ {
+		a, err := rand.Int(r, bn256.Order)
+		if err != nil {
+			return nil, nil, err
+		}
+		coeffs[k] = a
+	}
+
+	commits := make(Commitments, t)
+	for k, a := range coeffs {
+		commits[k] = new(bn256.G2).ScalarBaseMult(a)
+	}
+
+	shares := make([]*Share, n)
+	for i := range shares {
+		shares[i] = &Share{i + 1, evalPoly(coeffs, big.NewInt(int64(i+1)))}
+	}
+	return shares, commits, nil
+}
+
+// evalPoly returns the value of the polynomial with the given coefficients at
+// x, using Horner's method.
+func evalPoly(coeffs []*big.Int, x *big.Int) *big.Int {
+	ret := new(big.Int)
+	for k := len(coeffs) - 1; k >= 0; k-- {
+		ret.Mul(ret, x)
+		ret.Add(ret, coeffs[k])
+		ret.Mod(ret, bn256.Order)
+	}
+	return ret
+}
+
+// Threshold returns the number of shares needed to recover the secret.
+func (c Commitments) Threshold() int {
+	return len(c)
+}
+
+// PublicKey returns the public key that matches the shared secret.
+func (c Commitments) PublicKey() *bn256.G2 {
+	return new(bn256.G2).Set(c[0])
+}
+
+// SharePublicKey returns the public key that matches the share with index i,
+// which is the sharing polynomial evaluated at i in the exponent.
+func (c Commitments) SharePublicKey(i int) *bn256.G2 {
+	x := big.NewInt(int64(i))
+	ret := new(bn256.G2).Set(c[len(c)-1])
+	for k := len(c) - 2; k >= 0; k-- {
+		ret.ScalarMult(ret, x)
+		ret.Add(ret, c[k])
+	}
+	return ret
+}
+
+// VerifyShare returns true iff s is a share of the secret committed to by c.
+func (c Commitments) VerifyShare(s *Share) bool {
+	if s.Index < 1 || s.Value == nil {
+		return false
+	}
+	want := c.SharePublicKey(s.Index).Marshal()
+	got := new(bn256.G2).ScalarBaseMult(s.Value).Marshal()
+	return string(got) == string(want)
+}
+
+// Recover returns the secret shared with threshold t from at least t of its
+// shares. Only the first t shares are used.
+func Recover(shares []*Share, t int) (*big.Int, error) {
+	if t < 1 {
+		return nil, errThreshold
+	}
+	if len(shares) < t {
+		return nil, errShares
+	}
+	indices := make([]int, t)
+	for i, s := range shares[:t] {
+		indices[i] = s.Index
+	}
+	lambda, err := lagrangeAtZero(indices)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := new(big.Int)
+	for i, s := range shares[:t] {
+		secret.Add(secret, new(big.Int).Mul(lambda[i], s.Value))
+	}
+	return secret.Mod(secret, bn256.Order), nil
+}
+
+// lagrangeAtZero returns the Lagrange coefficients for interpolating the value
+// at zero of a polynomial from its values at indices:
+//
+//	λᵢ = Π_{j≠i} xⱼ / (xⱼ - xᵢ)
+func lagrangeAtZero(indices []int) ([]*big.Int, error) {
+	seen := make(map[int]bool, len(indices))
+	for _, i := range indices {
+		if i < 1 || seen[i] {
+			return nil, errIndex
+		}
+		seen[i] = true
+	}
+
+	ret := make([]*big.Int, len(indices))
+	for i, xi := range indices {
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j, xj := range indices {
+			if i == j {
+				continue
+			}
+			num.Mul(num, big.NewInt(int64(xj)))
+			num.Mod(num, bn256.Order)
+			den.Mul(den, big.NewInt(int64(xj-xi)))
+			den.Mod(den, bn256.Order)
+		}
+		den.ModInverse(den, bn256.Order)
+		ret[i] = num.Mul(num, den)
+		ret[i].Mod(ret[i], bn256.Order)
+	}
+	return ret, nil
+}
@@ -0,0 +1,78 @@
+// Package tbls implements threshold BLS signatures.
+//
+// A signing key is split among n parties with Shamir secret sharing so that
+// any t of them can sign. Each party signs with its share to produce a partial
+// signature σᵢ = sᵢ·H(m) in G₁, and any t valid partial signatures are combined
+// by Lagrange interpolation in the exponent into the ordinary BLS signature
+// s·H(m), which verifies against the public key s·g₂ in G₂.
+//
+// Feldman commitments to the sharing polynomial let each party check its share
+// and let anyone check a partial signature before combining it, so that a
+// faulty party can be identified.
+package tbls
+
+import (
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// domain is the domain separation tag used to hash messages to G₁.
+var domain = []byte("bn256/tbls/sign")
+
+// HashMessage returns the point of G₁ that msg is signed as.
+func HashMessage(msg []byte) *bn256.G1 {
+	return bn256.HashToG1(msg, domain)
+}
+
+// PartialSignature is the signature of a message under the share with index
+// Index.
+type PartialSignature struct {
+	Index int
+	Sig   *bn256.G1
+}
+
+// Sign returns the partial signature of msg under s.
+func Sign(s *Share, msg []byte) *PartialSignature {
+	return &PartialSignature{s.Index, new(bn256.G1).ScalarMult(HashMessage(msg), s.Value)}
+}
+
+// VerifyPartial returns true iff p is a valid partial signature of msg under
+// the share that c commits to at index p.Index.
+func VerifyPartial(c Commitments, p *PartialSignature, msg []byte) bool {
+	if p.Index < 1 || p.Sig == nil {
+		return false
+	}
+	return Verify(c.SharePublicKey(p.Index), msg, p.Sig)
+}
+
+// Combine returns the BLS signature recovered from at least t partial
+// signatures, where t is the threshold of the sharing. Only the first t partial
+// signatures are used, and they should have been checked with VerifyPartial.
+func Combine(partials []*PartialSignature, t int) (*bn256.G1, error) {
+	if t < 1 {
+		return nil, errThreshold
+	}
+	if len(partials) < t {
+		return nil, errShares
+	}
+
+	indices := make([]int, t)
+	sigs := make([]*bn256.G1, t)
+	for i, p := range partials[:t] {
+		indices[i], sigs[i] = p.Index, p.Sig
+	}
+	lambda, err := lagrangeAtZero(indices)
+	if err != nil {
+		return nil, err
+	}
+	return new(bn256.G1).MultiScalarMult(sigs, lambda), nil
+}
+
+// Verify returns true iff sig is a BLS signature of msg under pk.
+func Verify(pk *bn256.G2, msg []byte, sig *bn256.G1) bool {
+	// e(σ, g₂) = e(H(m), pk)
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	negH := new(bn256.G1).Neg(HashMessage(msg))
+	return bn256.PairingCheck([]*bn256.G1{sig, negH}, []*bn256.G2{g2, pk})
+}
@@ -0,0 +1,162 @@
+package tbls
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+func split(t *testing.T, threshold, n int) (*big.Int, []*Share, Commitments) {
+	t.Helper()
+
+	secret, err := rand.Int(rand.Reader, bn256.Order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, commits, err := Split(rand.Reader, secret, threshold, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret, shares, commits
+}
+
+func TestSplitRecover(t *testing.T) {
+	for _, tc := range []struct{ t, n int }{{1, 1}, {1, 3}, {2, 3}, {3, 5}, {5, 5}} {
+		secret, shares, commits := split(t, tc.t, tc.n)
+		if commits.Threshold() != tc.t {
+			t.Fatalf("threshold is %d, want %d", commits.Threshold(), tc.t)
+		}
+		if !bytes.Equal(commits.PublicKey().Marshal(), new(bn256.G2).ScalarBaseMult(secret).Marshal()) {
+			t.Fatal("public key does not match secret")
+		}
+		for _, s := range shares {
+			if !commits.VerifyShare(s) {
+				t.Fatalf("share %d rejected", s.Index)
+			}
+		}
+
+		// Any t shares, in any order, recover the secret.
+		reversed := make([]*Share, len(shares))
+		for i, s := range shares {
+			reversed[len(shares)-1-i] = s
+		}
+		for _, set := range [][]*Share{shares, reversed} {
+			got, err := Recover(set, tc.t)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Cmp(secret) != 0 {
+				t.Fatalf("t=%d, n=%d: recovered wrong secret", tc.t, tc.n)
+			}
+		}
+
+		if tc.t > 1 {
+			got, _ := Recover(shares, tc.t-1)
+			if got.Cmp(secret) == 0 {
+				t.Fatal("secret recovered from too few shares")
+			}
+		}
+	}
+
+	if _, _, err := Split(rand.Reader, big.NewInt(1), 4, 3); err == nil {
+		t.Fatal("threshold above number of shares accepted")
+	}
+}
+
+func TestVerifyShare(t *testing.T) {
+	_, shares, commits := split(t, 3, 5)
+
+	bad := &Share{shares[0].Index, new(big.Int).Add(shares[0].Value, big.NewInt(1))}
+	if commits.VerifyShare(bad) {
+		t.Fatal("corrupted share accepted")
+	}
+	moved := &Share{shares[1].Index, shares[0].Value}
+	if commits.VerifyShare(moved) {
+		t.Fatal("share accepted at the wrong index")
+	}
+	if commits.VerifyShare(&Share{0, shares[0].Value}) {
+		t.Fatal("share with index zero accepted")
+	}
+}
+
+func TestRecoverInvalidIndices(t *testing.T) {
+	_, shares, _ := split(t, 2, 3)
+
+	if _, err := Recover([]*Share{shares[0], shares[0]}, 2); err == nil {
+		t.Fatal("repeated share accepted")
+	}
+	if _, err := Recover(shares[:1], 2); err == nil {
+		t.Fatal("too few shares accepted")
+	}
+}
+
+func TestThresholdSignature(t *testing.T) {
+	secret, shares, commits := split(t, 3, 5)
+	msg := []byte("transfer 10 coins")
+
+	partials := make([]*PartialSignature, len(shares))
+	for i, s := range shares {
+		partials[i] = Sign(s, msg)
+		if !VerifyPartial(commits, partials[i], msg) {
+			t.Fatalf("partial signature %d rejected", i)
+		}
+	}
+	if VerifyPartial(commits, partials[0], []byte("other")) {
+		t.Fatal("partial signature accepted for another message")
+	}
+	if VerifyPartial(commits, &PartialSignature{2, partials[0].Sig}, msg) {
+		t.Fatal("partial signature accepted at the wrong index")
+	}
+
+	// The combination of any three partial signatures is the signature under
+	// the whole key.
+	want := new(bn256.G1).ScalarMult(HashMessage(msg), secret)
+	for _, set := range [][]*PartialSignature{
+		partials[:3],
+		{partials[4], partials[1], partials[3]},
+		partials[2:],
+	} {
+		sig, err := Combine(set, commits.Threshold())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sig.Marshal(), want.Marshal()) {
+			t.Fatal("combined signature differs from signature under the whole key")
+		}
+		if !Verify(commits.PublicKey(), msg, sig) {
+			t.Fatal("combined signature rejected")
+		}
+	}
+
+	sig, _ := Combine(partials, 3)
+	if Verify(commits.PublicKey(), []byte("other"), sig) {
+		t.Fatal("signature accepted for another message")
+	}
+	if _, err := Combine(partials[:2], 3); err == nil {
+		t.Fatal("too few partial signatures accepted")
+	}
+
+	// A bad partial signature spoils the combination.
+	bad := &PartialSignature{partials[0].Index, partials[1].Sig}
+	sig, _ = Combine([]*PartialSignature{bad, partials[2], partials[3]}, 3)
+	if Verify(commits.PublicKey(), msg, sig) {
+		t.Fatal("signature combined from a bad partial signature accepted")
+	}
+}
+
+func BenchmarkCombine(b *testing.B) {
+	secret, _ := rand.Int(rand.Reader, bn256.Order)
+	shares, _, _ := Split(rand.Reader, secret, 67, 100)
+	partials := make([]*PartialSignature, len(shares))
+	for i, s := range shares {
+		partials[i] = Sign(s, []byte("bench"))
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Combine(partials, 67)
+	}
+}
@@ -0,0 +1,131 @@
+// Package transcript implements Fiat-Shamir transcripts over bn256.
+//
+// A Transcript absorbs labelled messages, group elements and scalars and
+// derives challenge scalars from everything absorbed so far, so that a prover
+// and a verifier that absorb the same values in the same order derive the
+// same challenges. Every value is absorbed with its label, so values cannot
+// be confused with each other or with values of another protocol.
+//
+// The construction is simple enough to reproduce in a smart contract. With H
+// the hash function of the transcript and len(x) the length of x as a 4-byte
+// big-endian number, the state starts as 32 zero bytes and absorbing msg under
+// label sets
+//
+//	state = H(state ‖ len(label) ‖ label ‖ len(msg) ‖ msg)
+//
+// A challenge under label is the first 64 bytes of
+//
+//	H(state ‖ len(label) ‖ label ‖ 0x00) ‖ H(state ‖ len(label) ‖ label ‖ 0x01) ‖ …
+//
+// read as a big-endian number and reduced modulo Order; it is then absorbed
+// as a scalar under the same label. New starts by absorbing the protocol
+// label under "dom-sep".
+package transcript
+
+import (
+	"crypto/sha256"
+	"hash"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/encoding"
+	"golang.org/x/crypto/sha3"
+)
+
+// Transcript is a Fiat-Shamir transcript. It is not safe for concurrent use.
+type Transcript struct {
+	hash  func() hash.Hash
+	state []byte
+}
+
+// New returns a transcript for the protocol identified by label, which uses
+// SHA-256.
+func New(label string) *Transcript {
+	return NewWithHash(label, sha256.New)
+}
+
+// NewKeccak returns a transcript for the protocol identified by label, which
+// uses Keccak-256, as the EVM does, to keep verification cheap on chain.
+func NewKeccak(label string) *Transcript {
+	return NewWithHash(label, sha3.NewLegacyKeccak256)
+}
+
+// NewWithHash returns a transcript for the protocol identified by label, which
+// uses the hash function returned by h. Its output must be at least 32 bytes.
+func NewWithHash(label string, h func() hash.Hash) *Transcript {
+	t := &Transcript{hash: h, state: make([]byte, 32)}
+	t.AppendMessage("dom-sep", []byte(label))
+	return t
+}
+
+// Clone returns an independent copy of t, for example to derive challenges
+// for several branches of a protocol from a common prefix.
+func (t *Transcript) Clone() *Transcript {
+	return &Transcript{hash: t.hash, state: append([]byte(nil), t.state...)}
+}
+
+// AppendMessage absorbs msg under label.
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	h := t.hash()
+	h.Write(t.state)
+	writeBytes(h, []byte(label))
+	writeBytes(h, msg)
+	t.state = h.Sum(t.state[:0])
+}
+
+// AppendG1 absorbs the compressed encoding of p, in the
+// encoding.GnarkCompressed format, under label.
+func (t *Transcript) AppendG1(label string, p *bn256.G1) {
+	t.AppendMessage(label, encoding.GnarkCompressed.MarshalG1(p))
+}
+
+// AppendG2 absorbs the compressed encoding of p, in the
+// encoding.GnarkCompressed format, under label.
+func (t *Transcript) AppendG2(label string, p *bn256.G2) {
+	t.AppendMessage(label, encoding.GnarkCompressed.MarshalG2(p))
+}
+
+// AppendGT absorbs the encoding of p under label. GT has no compressed
+// encoding, so this is the output of GT.Marshal.
+func (t *Transcript) AppendGT(label string, p *bn256.GT) {
+	t.AppendMessage(label, p.Marshal())
+}
+
+// AppendScalar absorbs k mod Order under label as a 32-byte big-endian number.
+func (t *Transcript) AppendScalar(label string, k *big.Int) {
+	t.AppendMessage(label, scalarBytes(k))
+}
+
+// ChallengeScalar returns a challenge derived from everything absorbed so far,
+// which is close to uniform modulo Order, and absorbs it under label.
+func (t *Transcript) ChallengeScalar(label string) *big.Int {
+	var wide []byte
+	for i := byte(0); len(wide) < 64; i++ {
+		h := t.hash()
+		h.Write(t.state)
+		writeBytes(h, []byte(label))
+		h.Write([]byte{i})
+		wide = h.Sum(wide)
+	}
+
+	c := new(big.Int).SetBytes(wide[:64])
+	c.Mod(c, bn256.Order)
+	t.AppendScalar(label, c)
+	return c
+}
+
+// writeBytes writes b to h, preceded by its length.
+func writeBytes(h hash.Hash, b []byte) {
+	n := uint32(len(b))
+	h.Write([]byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)})
+	h.Write(b)
+}
+
+// scalarBytes returns the 32-byte big-endian encoding of k mod Order.
+func scalarBytes(k *big.Int) []byte {
+	r := new(big.Int).Mod(k, bn256.Order)
+	b := r.Bytes()
+	ret := make([]byte, 32)
+	copy(ret[32-len(b):], b)
+	return ret
+}
@@ -0,0 +1,164 @@
+package transcript
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+	"github.com/clearmatics/bn256/encoding"
+	"golang.org/x/crypto/sha3"
+)
+
+// TestConstruction checks the transcript against a direct computation of the
+// construction described in the package documentation.
+func TestConstruction(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		new  func(string) *Transcript
+		h    func([]byte) []byte
+	}{
+		{"sha256", New, func(b []byte) []byte { d := sha256.Sum256(b); return d[:] }},
+		{"keccak", NewKeccak, func(b []byte) []byte { h := sha3.NewLegacyKeccak256(); h.Write(b); return h.Sum(nil) }},
+	} {
+		lp := func(b []byte) []byte {
+			return append([]byte{0, 0, 0, byte(len(b))}, b...)
+		}
+		cat := func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+
+		state := make([]byte, 32)
+		state = tc.h(cat(state, lp([]byte("dom-sep")), lp([]byte("proto"))))
+		state = tc.h(cat(state, lp([]byte("msg")), lp([]byte("hello"))))
+		wide := cat(
+			tc.h(cat(state, lp([]byte("c")), []byte{0})),
+			tc.h(cat(state, lp([]byte("c")), []byte{1})),
+		)
+		want := new(big.Int).SetBytes(wide)
+		want.Mod(want, bn256.Order)
+
+		tr := tc.new("proto")
+		tr.AppendMessage("msg", []byte("hello"))
+		if got := tr.ChallengeScalar("c"); got.Cmp(want) != 0 {
+			t.Fatalf("%s: got challenge %x, want %x", tc.name, got, want)
+		}
+
+		// The challenge is absorbed.
+		state = tc.h(cat(state, lp([]byte("c")), lp(scalarBytes(want))))
+		if !bytes.Equal(tr.state, state) {
+			t.Fatalf("%s: challenge not absorbed", tc.name)
+		}
+	}
+}
+
+func TestDeterminism(t *testing.T) {
+	_, g1, _ := bn256.RandomG1(rand.Reader)
+	_, g2, _ := bn256.RandomG2(rand.Reader)
+	gt := bn256.Pair(g1, g2)
+	k, _ := rand.Int(rand.Reader, bn256.Order)
+
+	run := func(tr *Transcript) *big.Int {
+		tr.AppendMessage("m", []byte("message"))
+		tr.AppendG1("g1", g1)
+		tr.AppendG2("g2", g2)
+		tr.AppendGT("gt", gt)
+		tr.AppendScalar("k", k)
+		return tr.ChallengeScalar("c")
+	}
+
+	a, b := run(New("proto")), run(New("proto"))
+	if a.Cmp(b) != 0 {
+		t.Fatal("same transcript gave different challenges")
+	}
+	if a.Cmp(bn256.Order) >= 0 || a.Sign() < 0 {
+		t.Fatal("challenge not reduced")
+	}
+	if c := run(New("other")); a.Cmp(c) == 0 {
+		t.Fatal("protocol label ignored")
+	}
+	if c := run(NewKeccak("proto")); a.Cmp(c) == 0 {
+		t.Fatal("hash function ignored")
+	}
+
+	// Successive challenges differ.
+	tr := New("proto")
+	if tr.ChallengeScalar("c").Cmp(tr.ChallengeScalar("c")) == 0 {
+		t.Fatal("successive challenges are equal")
+	}
+}
+
+// TestSeparation checks that labels and message boundaries are bound.
+func TestSeparation(t *testing.T) {
+	challenge := func(f func(tr *Transcript)) *big.Int {
+		tr := New("proto")
+		f(tr)
+		return tr.ChallengeScalar("c")
+	}
+
+	variants := []*big.Int{
+		challenge(func(tr *Transcript) { tr.AppendMessage("a", []byte("bc")) }),
+		challenge(func(tr *Transcript) { tr.AppendMessage("ab", []byte("c")) }),
+		challenge(func(tr *Transcript) { tr.AppendMessage("b", []byte("bc")) }),
+		challenge(func(tr *Transcript) {
+			tr.AppendMessage("a", []byte("b"))
+			tr.AppendMessage("a", []byte("c"))
+		}),
+		challenge(func(tr *Transcript) { tr.AppendScalar("a", big.NewInt(1)) }),
+		challenge(func(tr *Transcript) { tr.AppendScalar("a", big.NewInt(2)) }),
+		challenge(func(tr *Transcript) {}),
+	}
+	for i := range variants {
+		for j := i + 1; j < len(variants); j++ {
+			if variants[i].Cmp(variants[j]) == 0 {
+				t.Fatalf("variants %d and %d give the same challenge", i, j)
+			}
+		}
+	}
+
+	// Scalars are absorbed modulo Order.
+	a := challenge(func(tr *Transcript) { tr.AppendScalar("a", big.NewInt(1)) })
+	b := challenge(func(tr *Transcript) { tr.AppendScalar("a", new(big.Int).Add(bn256.Order, big.NewInt(1))) })
+	if a.Cmp(b) != 0 {
+		t.Fatal("scalar not reduced")
+	}
+}
+
+func TestClone(t *testing.T) {
+	tr := New("proto")
+	tr.AppendMessage("m", []byte("prefix"))
+	clone := tr.Clone()
+
+	if tr.ChallengeScalar("c").Cmp(clone.ChallengeScalar("c")) != 0 {
+		t.Fatal("clone gave a different challenge")
+	}
+	tr.AppendMessage("m", []byte("x"))
+	clone.AppendMessage("m", []byte("y"))
+	if tr.ChallengeScalar("c").Cmp(clone.ChallengeScalar("c")) == 0 {
+		t.Fatal("clone is not independent")
+	}
+}
+
+func TestAppendPoint(t *testing.T) {
+	g := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	_, g2, _ := bn256.RandomG2(rand.Reader)
+
+	tr, want := New("proto"), New("proto")
+	tr.AppendG1("g1", g)
+	tr.AppendG2("g2", g2)
+	// The generator is (1, 2), and 2 is the smaller root.
+	want.AppendMessage("g1", mustHex("8000000000000000000000000000000000000000000000000000000000000001"))
+	want.AppendMessage("g2", encoding.GnarkCompressed.MarshalG2(g2))
+	if !bytes.Equal(tr.state, want.state) {
+		t.Fatal("points not absorbed in their compressed encoding")
+	}
+}
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
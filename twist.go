@@ -31,8 +31,8 @@ var twistGen = &twistPoint{
 }
 
 func (c *twistPoint) String() string {
-	c.MakeAffine()
-	x, y := gfP2Decode(&c.x), gfP2Decode(&c.y)
+	a := c.affine()
+	x, y := gfP2Decode(&a.x), gfP2Decode(&a.y)
 	return "(" + x.String() + ", " + y.String() + ")"
 }
 
@@ -43,23 +43,30 @@ func (c *twistPoint) Set(a *twistPoint) {
 	c.t.Set(&a.t)
 }
 
-// IsOnCurve returns true iff c is on the curve.
+// IsOnCurve returns true iff c is on the curve and in G₂.
 func (c *twistPoint) IsOnCurve() bool {
-	c.MakeAffine()
+	if !c.isOnTwist() {
+		return false
+	}
+	cneg := &twistPoint{}
+	cneg.Mul(c, Order)
+	return cneg.z.IsZero()
+}
+
+// isOnTwist returns true iff c is on the curve, which may hold outside G₂. Like
+// curvePoint.IsOnCurve, it checks y²=x³+bz⁶ on the Jacobian coordinates.
+func (c *twistPoint) isOnTwist() bool {
 	if c.IsInfinity() {
 		return true
 	}
 
-	y2, x3 := &gfP2{}, &gfP2{}
+	y2, x3, z6 := &gfP2{}, &gfP2{}, &gfP2{}
 	y2.Square(&c.y)
-	x3.Square(&c.x).Mul(x3, &c.x).Add(x3, twistB)
+	x3.Square(&c.x).Mul(x3, &c.x)
+	z6.Square(&c.z).Mul(z6, &c.z).Square(z6).Mul(z6, twistB)
+	x3.Add(x3, z6)
 
-	if *y2 != *x3 {
-		return false
-	}
-	cneg := &twistPoint{}
-	cneg.Mul(c, Order)
-	return cneg.z.IsZero()
+	return *y2 == *x3
 }
 
 func (c *twistPoint) SetInfinity() {
@@ -134,6 +141,11 @@ func (c *twistPoint) Add(a, b *twistPoint) {
 
 func (c *twistPoint) Double(a *twistPoint) {
 	// See http://hyperelliptic.org/EFD/g1p/auto-code/shortw/jacobian-0/doubling/dbl-2009-l.op3
+
+	// Compute z first, as a.y is overwritten below when c aliases a.
+	z := (&gfP2{}).Mul(&a.y, &a.z)
+	z.Add(z, z)
+
 	A := (&gfP2{}).Square(&a.x)
 	B := (&gfP2{}).Square(&a.y)
 	C := (&gfP2{}).Square(B)
@@ -157,8 +169,7 @@ func (c *twistPoint) Double(a *twistPoint) {
 	t2.Mul(e, &c.y)
 	c.y.Sub(t2, t)
 
-	t.Mul(&a.y, &a.z)
-	c.z.Add(t, t)
+	c.z.Set(z)
 }
 
 func (c *twistPoint) Mul(a *twistPoint, scalar *big.Int) {
@@ -176,6 +187,15 @@ func (c *twistPoint) Mul(a *twistPoint, scalar *big.Int) {
 	c.Set(sum)
 }
 
+// affine returns a copy of c in affine coordinates, leaving c unchanged so
+// that it may be shared between goroutines.
+func (c *twistPoint) affine() *twistPoint {
+	a := &twistPoint{}
+	a.Set(c)
+	a.MakeAffine()
+	return a
+}
+
 func (c *twistPoint) MakeAffine() {
 	if c.z.IsOne() {
 		return
@@ -187,6 +207,17 @@ func (c *twistPoint) MakeAffine() {
 	}
 
 	zInv := (&gfP2{}).Invert(&c.z)
+	c.makeAffineWith(zInv)
+}
+
+// makeAffineWith converts c to affine coordinates given zInv = 1/c.z, which
+// lets the inversion be shared between many points.
+func (c *twistPoint) makeAffineWith(zInv *gfP2) {
+	if c.IsInfinity() {
+		c.SetInfinity()
+		return
+	}
+
 	t := (&gfP2{}).Mul(&c.y, zInv)
 	zInv2 := (&gfP2{}).Square(zInv)
 	c.y.Mul(t, zInv2)
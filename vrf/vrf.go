@@ -0,0 +1,115 @@
+// Package vrf implements a verifiable random function based on BLS
+// signatures.
+//
+// The proof for an input α under the private key x is the BLS signature
+// π = x·H(α) ∈ G₁, which anyone can check against the public key x·g₂ with a
+// pairing. The output is a hash of the encoding of π. BLS signatures are
+// deterministic and there is exactly one valid signature for each input, so
+// the output is unique: not even the holder of the private key can produce a
+// second output for the same input. Without the private key the output is
+// unpredictable.
+package vrf
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/clearmatics/bn256"
+)
+
+// Size is the length in bytes of the output of the VRF.
+const Size = sha256.Size
+
+// ProofSize is the length in bytes of a proof.
+const ProofSize = 64
+
+// Domain separation tags for hashing inputs and proofs.
+var (
+	inputDomain  = []byte("bn256/vrf/input")
+	outputDomain = []byte("bn256/vrf/output")
+)
+
+var errMalformed = errors.New("vrf: malformed proof")
+
+// PublicKey is a VRF public key.
+type PublicKey struct {
+	P *bn256.G2
+}
+
+// PrivateKey is a VRF private key.
+type PrivateKey struct {
+	PublicKey
+	X *big.Int
+}
+
+// GenerateKey returns a new key pair using randomness from r.
+func GenerateKey(r io.Reader) (*PrivateKey, error) {
+	for {
+		x, P, err := bn256.RandomG2(r)
+		if err != nil {
+			return nil, err
+		}
+		// A zero key would make every proof the identity.
+		if x.Sign() != 0 {
+			return &PrivateKey{PublicKey{P}, x}, nil
+		}
+	}
+}
+
+// Prove returns the proof for alpha under sk.
+func Prove(sk *PrivateKey, alpha []byte) []byte {
+	return new(bn256.G1).ScalarMult(bn256.HashToG1(alpha, inputDomain), sk.X).Marshal()
+}
+
+// Verify returns true iff proof is the proof for alpha under pk.
+func Verify(pk *PublicKey, alpha, proof []byte) bool {
+	pi, err := decodeProof(proof)
+	if err != nil || isIdentity(pk.P.Marshal()) {
+		return false
+	}
+
+	// e(π, g₂) = e(H(α), pk)
+	g2 := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+	negH := new(bn256.G1).Neg(bn256.HashToG1(alpha, inputDomain))
+	return bn256.PairingCheck([]*bn256.G1{pi, negH}, []*bn256.G2{g2, pk.P})
+}
+
+// ProofToHash returns the output of the VRF for proof. It does not check that
+// proof is valid, which must be done with Verify before the output is trusted.
+func ProofToHash(proof []byte) ([]byte, error) {
+	pi, err := decodeProof(proof)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	h.Write([]byte{byte(len(outputDomain))})
+	h.Write(outputDomain)
+	h.Write(pi.Marshal())
+	return h.Sum(nil), nil
+}
+
+// decodeProof returns the point encoded by proof, which must be exactly the
+// canonical encoding of a point other than the identity.
+func decodeProof(proof []byte) (*bn256.G1, error) {
+	if len(proof) != ProofSize || isIdentity(proof) {
+		return nil, errMalformed
+	}
+	pi := new(bn256.G1)
+	if _, err := pi.Unmarshal(proof); err != nil {
+		return nil, err
+	}
+	return pi, nil
+}
+
+// isIdentity returns true iff m is the encoding of the identity.
+func isIdentity(m []byte) bool {
+	for _, b := range m {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package vrf
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/clearmatics/bn256"
+)
+
+func TestProveVerify(t *testing.T) {
+	sk, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alpha := []byte("epoch 42")
+
+	proof := Prove(sk, alpha)
+	if len(proof) != ProofSize {
+		t.Fatalf("proof is %d bytes", len(proof))
+	}
+	if !Verify(&sk.PublicKey, alpha, proof) {
+		t.Fatal("valid proof rejected")
+	}
+	beta, err := ProofToHash(proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(beta) != Size {
+		t.Fatalf("output is %d bytes", len(beta))
+	}
+
+	if Verify(&sk.PublicKey, []byte("epoch 43"), proof) {
+		t.Fatal("proof accepted for another input")
+	}
+	other, _ := GenerateKey(rand.Reader)
+	if Verify(&other.PublicKey, alpha, proof) {
+		t.Fatal("proof accepted under another key")
+	}
+}
+
+func TestDeterminism(t *testing.T) {
+	sk, _ := GenerateKey(rand.Reader)
+	alpha := []byte("epoch 42")
+
+	a, b := Prove(sk, alpha), Prove(sk, alpha)
+	if !bytes.Equal(a, b) {
+		t.Fatal("proofs for the same input differ")
+	}
+	betaA, _ := ProofToHash(a)
+	betaB, _ := ProofToHash(b)
+	if !bytes.Equal(betaA, betaB) {
+		t.Fatal("outputs for the same input differ")
+	}
+
+	betaC, _ := ProofToHash(Prove(sk, []byte("epoch 43")))
+	if bytes.Equal(betaA, betaC) {
+		t.Fatal("outputs for different inputs are equal")
+	}
+	other, _ := GenerateKey(rand.Reader)
+	betaD, _ := ProofToHash(Prove(other, alpha))
+	if bytes.Equal(betaA, betaD) {
+		t.Fatal("outputs under different keys are equal")
+	}
+}
+
+// TestUniqueness checks that no proof other than the honest one verifies, so
+// that the output for an input cannot be chosen.
+func TestUniqueness(t *testing.T) {
+	sk, _ := GenerateKey(rand.Reader)
+	alpha := []byte("epoch 42")
+	proof := Prove(sk, alpha)
+	pi := new(bn256.G1)
+	if _, err := pi.Unmarshal(proof); err != nil {
+		t.Fatal(err)
+	}
+
+	g := new(bn256.G1).ScalarBaseMult(big.NewInt(1))
+	candidates := [][]byte{
+		new(bn256.G1).Add(pi, g).Marshal(),
+		new(bn256.G1).Neg(pi).Marshal(),
+		new(bn256.G1).ScalarMult(pi, big.NewInt(2)).Marshal(),
+		Prove(&PrivateKey{sk.PublicKey, new(big.Int).Add(sk.X, big.NewInt(1))}, alpha),
+		make([]byte, ProofSize),
+		proof[:ProofSize-1],
+		append(append([]byte(nil), proof...), 0),
+	}
+	for i, c := range candidates {
+		if Verify(&sk.PublicKey, alpha, c) {
+			t.Errorf("candidate %d accepted", i)
+		}
+	}
+
+	// A proof that is not a point on the curve has no output.
+	bad := append([]byte(nil), proof...)
+	bad[ProofSize-1] ^= 1
+	if _, err := ProofToHash(bad); err == nil {
+		t.Fatal("output computed for malformed proof")
+	}
+}
+
+func TestIdentityKey(t *testing.T) {
+	sk := &PrivateKey{PublicKey{new(bn256.G2).ScalarBaseMult(new(big.Int))}, new(big.Int)}
+	alpha := []byte("epoch 42")
+	if Verify(&sk.PublicKey, alpha, Prove(sk, alpha)) {
+		t.Fatal("proof under the identity key accepted")
+	}
+}
+
+func BenchmarkProve(b *testing.B) {
+	sk, _ := GenerateKey(rand.Reader)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Prove(sk, []byte("bench"))
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	sk, _ := GenerateKey(rand.Reader)
+	proof := Prove(sk, []byte("bench"))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Verify(&sk.PublicKey, []byte("bench"), proof)
+	}
+}